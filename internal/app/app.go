@@ -26,12 +26,31 @@ func Start(st *state.State) error {
 		st.PrintConfig()
 	case constant.AppModeType.HandleParam:
 		// nop - proceed to exit
+	case constant.AppModeType.RestoreBackup:
+		err = restoreBackup(st, os.Stdin, os.Stdout)
+	case constant.AppModeType.EncryptHosts:
+		err = encryptHosts(st, ui.PromptPassphrase, os.Stdout)
+	case constant.AppModeType.DecryptHosts:
+		err = decryptHosts(st, ui.PromptPassphrase, os.Stdout)
+	case constant.AppModeType.LintSSHConfig:
+		err = lintSSHConfig(st, os.Stdout)
 	}
 
 	return err
 }
 
 func startUI(st *state.State) error {
+	// Theme is loaded first, because passphrase prompt is using it.
+	err := theme.Load(st.AppHome, st.Theme, st.Logger)
+	if err != nil {
+		st.Logger.Error("[APP] Cannot load theme %q: %v. Fall back to default theme", st.Theme, err)
+	}
+
+	err = unlockHostsFile(st, ui.PromptPassphrase)
+	if err != nil {
+		return err
+	}
+
 	// Init storage
 	str, err := storage.Initialize(st.Context, st, st.Logger)
 	if err != nil {
@@ -43,11 +62,6 @@ func startUI(st *state.State) error {
 		str.Close()
 	}()
 
-	err = theme.Load(st.AppHome, st.Theme, st.Logger)
-	if err != nil {
-		st.Logger.Error("[APP] Cannot load theme %q: %v. Fall back to default theme", st.Theme, err)
-	}
-
 	// Run user interface and block
 	err = ui.Start(st.Context, str, st)
 	if err != nil {
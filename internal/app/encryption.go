@@ -0,0 +1,129 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/storage"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// passphrasePrompt - asks user for a passphrase, see ui.PromptPassphrase.
+type passphrasePrompt func(title string, validate func(string) error) (string, error)
+
+// unlockHostsFile - if hosts file is encrypted, asks user for a passphrase which is used to decrypt it.
+// When key file is set, checks that it can be used to decrypt hosts file instead, and asks for the
+// key file passphrase only if the key file is protected.
+func unlockHostsFile(st *state.State, prompt passphrasePrompt) error {
+	if !storage.IsEncrypted(st.AppHome) {
+		return nil
+	}
+
+	var passphrase string
+	var err error
+	if utils.StringEmpty(&st.KeyFile) {
+		passphrase, err = prompt("Enter passphrase to decrypt hosts file", func(value string) error {
+			return storage.VerifyEncryptionKey(st.AppHome, storage.EncryptionKey{Passphrase: value})
+		})
+	} else {
+		passphrase, err = promptKeyFilePassphrase(prompt, func(value string) error {
+			return storage.VerifyEncryptionKey(st.AppHome, storage.EncryptionKey{KeyFile: st.KeyFile, Passphrase: value})
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	st.Passphrase = passphrase
+	return nil
+}
+
+// encryptHosts - encrypts hosts.yaml with a key file or with a new passphrase.
+func encryptHosts(st *state.State, prompt passphrasePrompt, out io.Writer) error {
+	if storage.IsEncrypted(st.AppHome) {
+		return errors.New("hosts file is already encrypted")
+	}
+
+	// Count backups before encryption, as afterwards they are moved to the encrypted backups folder.
+	plainBackups, err := storage.ListBackups(st.AppHome)
+	if err != nil {
+		return fmt.Errorf("cannot list backups: %w", err)
+	}
+
+	key := storage.EncryptionKey{KeyFile: st.KeyFile}
+	if utils.StringEmpty(&key.KeyFile) {
+		key.Passphrase, err = promptNewPassphrase(prompt)
+	} else {
+		key.Passphrase, err = promptKeyFilePassphrase(prompt, func(value string) error {
+			return storage.VerifyKeyFile(key.KeyFile, value)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = storage.EncryptHostsFile(st.AppHome, key, st.Logger); err != nil {
+		return fmt.Errorf("cannot encrypt hosts file: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(out, "Hosts file encrypted")
+	if len(plainBackups) > 0 {
+		_, _ = fmt.Fprintf(out, "%d backups encrypted\n", len(plainBackups))
+	}
+
+	return nil
+}
+
+// promptKeyFilePassphrase - asks for the key file passphrase, if verify reports that the key file is protected.
+func promptKeyFilePassphrase(prompt passphrasePrompt, verify func(passphrase string) error) (string, error) {
+	err := verify("")
+	if !errors.Is(err, storage.ErrKeyFilePassphraseRequired) {
+		return "", err
+	}
+
+	return prompt("Enter passphrase to unlock key file", verify)
+}
+
+func promptNewPassphrase(prompt passphrasePrompt) (string, error) {
+	passphrase, err := prompt("Enter new passphrase to encrypt hosts file", func(value string) error {
+		if value == "" {
+			return errors.New("passphrase cannot be empty")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = prompt("Repeat passphrase", func(value string) error {
+		if value != passphrase {
+			return errors.New("passphrases do not match")
+		}
+
+		return nil
+	})
+
+	return passphrase, err
+}
+
+// decryptHosts - decrypts hosts file, so that it's stored as plain text hosts.yaml again.
+func decryptHosts(st *state.State, prompt passphrasePrompt, out io.Writer) error {
+	if !storage.IsEncrypted(st.AppHome) {
+		return errors.New("hosts file is not encrypted")
+	}
+
+	if err := unlockHostsFile(st, prompt); err != nil {
+		return err
+	}
+
+	key := storage.EncryptionKey{Passphrase: st.Passphrase, KeyFile: st.KeyFile}
+	if err := storage.DecryptHostsFile(st.AppHome, key, st.Logger); err != nil {
+		return fmt.Errorf("cannot decrypt hosts file: %w", err)
+	}
+
+	_, _ = fmt.Fprintln(out, "Hosts file decrypted")
+	return nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/storage"
+	testutils "github.com/grafviktor/goto/internal/testutils"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+// mockPrompt returns answers one by one, an answer is returned only if it passes validation.
+func mockPrompt(answers ...string) passphrasePrompt {
+	return func(_ string, validate func(string) error) (string, error) {
+		if len(answers) == 0 {
+			return "", errors.New("no more answers")
+		}
+
+		answer := answers[0]
+		answers = answers[1:]
+		return answer, validate(answer)
+	}
+}
+
+func Test_encryptAndDecryptHosts_Passphrase(t *testing.T) {
+	appHome := t.TempDir()
+	st := &state.State{AppHome: appHome, Logger: &mocklogger.Logger{}}
+	hostsFilePath := filepath.Join(appHome, "hosts.yaml")
+	require.NoError(t, os.WriteFile(hostsFilePath, []byte("- host:\n"), 0o600))
+
+	var out bytes.Buffer
+	err := encryptHosts(st, mockPrompt("secret", "typo"), &out)
+	require.Error(t, err, "passphrases do not match")
+	require.False(t, storage.IsEncrypted(appHome))
+
+	err = encryptHosts(st, mockPrompt("secret", "secret"), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "Hosts file encrypted")
+	require.True(t, storage.IsEncrypted(appHome))
+	require.NoFileExists(t, hostsFilePath)
+
+	err = unlockHostsFile(st, mockPrompt("wrong"))
+	require.ErrorIs(t, err, storage.ErrWrongEncryptionKey)
+
+	out.Reset()
+	err = decryptHosts(st, mockPrompt("secret"), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "Hosts file decrypted")
+	require.False(t, storage.IsEncrypted(appHome))
+	data, err := os.ReadFile(hostsFilePath)
+	require.NoError(t, err)
+	require.Equal(t, "- host:\n", string(data))
+}
+
+func Test_encryptHosts_KeyFile(t *testing.T) {
+	appHome := t.TempDir()
+	keyFile, _ := testutils.WriteSSHKey(t, "")
+	require.NoError(t, os.WriteFile(filepath.Join(appHome, "hosts.yaml"), []byte("- host:\n"), 0o600))
+	require.NoError(t, os.MkdirAll(storage.BackupFolder(appHome), 0o700))
+	backupFile := filepath.Join(storage.BackupFolder(appHome), "hosts-20261017-150405.000000.yaml")
+	require.NoError(t, os.WriteFile(backupFile, []byte("backup"), 0o600))
+
+	st := &state.State{AppHome: appHome, KeyFile: keyFile, Logger: &mocklogger.Logger{}}
+	var out bytes.Buffer
+	// Passphrase is not requested, when key file is not protected.
+	err := encryptHosts(st, mockPrompt(), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "1 backups encrypted")
+	require.NoFileExists(t, backupFile)
+	backups, err := storage.ListBackups(appHome)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	data, err := os.ReadFile(backups[0].Path)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "backup")
+
+	err = encryptHosts(st, mockPrompt(), &out)
+	require.Error(t, err, "already encrypted")
+
+	require.NoError(t, unlockHostsFile(st, mockPrompt()))
+	st.KeyFile = ""
+	require.Error(t, unlockHostsFile(st, mockPrompt("passphrase")))
+}
+
+func Test_encryptHosts_ProtectedKeyFile(t *testing.T) {
+	appHome := t.TempDir()
+	keyFile, _ := testutils.WriteSSHKey(t, "secret")
+	require.NoError(t, os.WriteFile(filepath.Join(appHome, "hosts.yaml"), []byte("- host:\n"), 0o600))
+
+	st := &state.State{AppHome: appHome, KeyFile: keyFile, Logger: &mocklogger.Logger{}}
+	var out bytes.Buffer
+	err := encryptHosts(st, mockPrompt("wrong"), &out)
+	require.ErrorIs(t, err, storage.ErrWrongEncryptionKey)
+	require.False(t, storage.IsEncrypted(appHome))
+
+	require.NoError(t, encryptHosts(st, mockPrompt("secret"), &out))
+	require.True(t, storage.IsEncrypted(appHome))
+
+	require.NoError(t, unlockHostsFile(st, mockPrompt("secret")))
+	require.Equal(t, "secret", st.Passphrase)
+
+	out.Reset()
+	require.NoError(t, decryptHosts(st, mockPrompt("secret"), &out))
+	require.Contains(t, out.String(), "Hosts file decrypted")
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/samber/lo"
+
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/storage/sshconfig"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// lintSSHConfig - reads ssh_config file and additional sources, including the files which are loaded
+// with Include directive, and prints problems which are found. Returns an error if any of the problems
+// is an error, so that the command can be used in CI pipelines. Remote files are not cached, so that
+// the problems are reported for their current versions.
+func lintSSHConfig(st *state.State, out io.Writer) error {
+	if !st.SSHConfigEnabled {
+		return errors.New("ssh_config support is disabled, enable it with -e ssh_config option")
+	}
+
+	utils.SetURLAccess(st.RemoteAccess)
+
+	errorCount, warningCount := 0, 0
+	for _, source := range lo.Uniq(append([]string{st.SSHConfigPath}, st.SSHConfigSources...)) {
+		lexer := sshconfig.NewFileLexer(source, st.Logger)
+		parser := sshconfig.NewParser(lexer, st.Logger)
+		// Parse error is not checked, because the lexer reports the files which cannot be read.
+		hosts, _ := parser.Parse()
+
+		for _, diagnostic := range append(lexer.GetDiagnostics(), parser.Diagnostics()...) {
+			_, _ = fmt.Fprintln(out, diagnostic.String())
+			if diagnostic.Severity == sshconfig.Severity.Error {
+				errorCount++
+			} else {
+				warningCount++
+			}
+		}
+
+		_, _ = fmt.Fprintf(out, "%s: %d hosts loaded\n", utils.RedactURL(source), len(hosts))
+	}
+
+	_, _ = fmt.Fprintf(out, "%d errors, %d warnings\n", errorCount, warningCount)
+	if errorCount > 0 {
+		return fmt.Errorf("ssh_config contains %d errors", errorCount)
+	}
+
+	return nil
+}
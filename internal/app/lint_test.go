@@ -0,0 +1,46 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func Test_lintSSHConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+	sourcePath := filepath.Join(tmpDir, "team_config")
+	require.NoError(t, os.WriteFile(configPath, []byte("Host web1\n  HostName web1.internal\n"), 0o600))
+	require.NoError(t, os.WriteFile(sourcePath, []byte("Host *.web\n  User root\n"), 0o600))
+
+	st := &state.State{
+		Logger:           &mocklogger.Logger{},
+		SSHConfigEnabled: true,
+		SSHConfigPath:    configPath,
+		SSHConfigSources: []string{sourcePath},
+	}
+
+	// Warnings do not fail the check.
+	var out bytes.Buffer
+	require.NoError(t, lintSSHConfig(st, &out))
+	require.Contains(t, out.String(), configPath+": 1 hosts loaded\n")
+	require.Contains(t, out.String(), sourcePath+":1: warning: Host *.web only contains patterns")
+	require.Contains(t, out.String(), "0 errors, 1 warnings\n")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("Host web1\n  Port ssh\nHost web1\n"), 0o600))
+	out.Reset()
+	err := lintSSHConfig(st, &out)
+	require.ErrorContains(t, err, "ssh_config contains 1 errors")
+	require.Contains(t, out.String(), configPath+`:2: error: Invalid port "ssh", the value is ignored`)
+	// Repeated aliases are valid in ssh_config, they're only reported as warnings.
+	require.Contains(t, out.String(), configPath+`:3: warning: Host alias "web1" is already declared at `+configPath+":1")
+
+	st.SSHConfigEnabled = false
+	require.Error(t, lintSSHConfig(st, &out))
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/storage"
+)
+
+// restoreBackup - lists hosts.yaml backups and asks user which one should be restored.
+func restoreBackup(st *state.State, in io.Reader, out io.Writer) error {
+	backups, err := storage.ListBackups(st.AppHome)
+	if err != nil {
+		return fmt.Errorf("cannot list backups: %w", err)
+	}
+
+	if len(backups) == 0 {
+		_, _ = fmt.Fprintf(out, "No backups found in %s\n", storage.BackupFolder(st.AppHome))
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "Backups found in %s:\n", storage.BackupFolder(st.AppHome))
+	for i, backup := range backups {
+		_, _ = fmt.Fprintf(out, "%3d) %s  %s  %d bytes\n",
+			i+1, backup.Name, backup.Created.Format("2006-01-02 15:04:05"), backup.Size)
+	}
+
+	_, _ = fmt.Fprintf(out, "Select backup to restore [1-%d] or press Enter to cancel: ", len(backups))
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		_, _ = fmt.Fprintln(out, "Cancelled")
+		return nil
+	}
+
+	index, err := strconv.Atoi(answer)
+	if err != nil || index < 1 || index > len(backups) {
+		return fmt.Errorf("invalid backup number: %q", answer)
+	}
+
+	backup := backups[index-1]
+	if err = storage.RestoreBackup(st.AppHome, backup, st.BackupCount, st.Logger); err != nil {
+		return fmt.Errorf("cannot restore backup: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(out, "Hosts file restored from %s\n", backup.Name)
+	return nil
+}
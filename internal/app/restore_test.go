@@ -0,0 +1,52 @@
+package app
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/storage"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func Test_restoreBackup(t *testing.T) {
+	appHome := t.TempDir()
+	st := &state.State{AppHome: appHome, Logger: &mocklogger.Logger{}, BackupCount: 5}
+	hostsFilePath := filepath.Join(appHome, "hosts.yaml")
+	backupFilePath := filepath.Join(storage.BackupFolder(appHome), "hosts-20261017-150405.000000.yaml")
+
+	// No backups
+	var out bytes.Buffer
+	err := restoreBackup(st, strings.NewReader(""), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "No backups found")
+
+	require.NoError(t, os.MkdirAll(storage.BackupFolder(appHome), 0o700))
+	require.NoError(t, os.WriteFile(backupFilePath, []byte("backup"), 0o600))
+	require.NoError(t, os.WriteFile(hostsFilePath, []byte("current"), 0o600))
+
+	// User cancels the operation
+	out.Reset()
+	err = restoreBackup(st, strings.NewReader("\n"), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "hosts-20261017-150405.000000.yaml")
+	require.Contains(t, out.String(), "Cancelled")
+
+	// Invalid input
+	err = restoreBackup(st, strings.NewReader("2\n"), &out)
+	require.Error(t, err)
+
+	// Restore
+	out.Reset()
+	err = restoreBackup(st, strings.NewReader("1\n"), &out)
+	require.NoError(t, err)
+	require.Contains(t, out.String(), "Hosts file restored")
+	data, err := os.ReadFile(hostsFilePath)
+	require.NoError(t, err)
+	require.Equal(t, "backup", string(data))
+}
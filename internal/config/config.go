@@ -19,18 +19,25 @@ import (
 const (
 	appName          = "goto"
 	FeatureSSHConfig = "ssh_config"
+	// CommandLint checks ssh_config files and prints problems, which are found, see "gg lint -h".
+	CommandLint = "lint"
 )
 
 // Configuration structs contains user-definable parameters.
 type Configuration struct {
-	AppMode        constant.AppMode
-	AppName        string
-	DisableFeature FeatureFlag
-	EnableFeature  FeatureFlag
-	SetTheme       string
-	AppHome        string            `env:"GG_HOME"`
-	LogLevel       constant.LogLevel `env:"GG_LOG_LEVEL"            envDefault:"info"`
-	SSHConfigPath  string            `env:"GG_SSH_CONFIG_FILE_PATH"`
+	AppMode          constant.AppMode
+	AppName          string
+	DisableFeature   FeatureFlag
+	EnableFeature    FeatureFlag
+	SetTheme         string
+	AppHome          string            `env:"GG_HOME"`
+	BackupCount      int               `env:"GG_BACKUP_COUNT"         envDefault:"5"`
+	DefaultInventory string            `env:"GG_DEFAULT_INVENTORY"`
+	Inventory        string            `env:"GG_INVENTORY"`
+	KeyFile          string            `env:"GG_KEY_FILE"`
+	LogLevel         constant.LogLevel `env:"GG_LOG_LEVEL"            envDefault:"info"`
+	SSHConfigPath    string            `env:"GG_SSH_CONFIG_FILE_PATH"`
+	SSHConfigSources StringList        `env:"GG_SSH_CONFIG_SOURCES"   envSeparator:","`
 	// SetSSHConfigPath is not the same as SSHConfigPath, as when this is set, we must
 	// write the value to state file and exit. When SSHConfigPath is set, we just use it
 	// as the path to ssh config within the current application run.
@@ -68,6 +75,9 @@ func parseEnvironmentVariables() (*Configuration, error) {
 func parseCommandLineFlags(envConfig *Configuration, args []string, exitOnError bool) (*Configuration, error) {
 	var cmdConfig Configuration
 	var shouldDisplayVersionAndExit bool
+	var shouldRestoreBackup bool
+	var shouldEncryptHosts bool
+	var shouldDecryptHosts bool
 
 	// flag.ExitOnError - means exit the program if an error occurs while parsing flags
 	// flag.ContinueOnError - means return error and let developer to decide how to handle this error,
@@ -90,6 +100,11 @@ func parseCommandLineFlags(envConfig *Configuration, args []string, exitOnError
 		envConfig.SSHConfigPath,
 		"Specifies an alternative per-user SSH configuration file path",
 	)
+	fs.Var(
+		&cmdConfig.SSHConfigSources,
+		"ssh-config-source",
+		"Load hosts from additional ssh_config file or URL, can be used several times",
+	)
 	fs.Var(
 		&cmdConfig.EnableFeature,
 		"e",
@@ -102,15 +117,66 @@ func parseCommandLineFlags(envConfig *Configuration, args []string, exitOnError
 	)
 	fs.StringVar(&cmdConfig.SetTheme, "set-theme", "", "Set application theme")
 	fs.StringVar(&cmdConfig.SetSSHConfigPath, "set-ssh-config-path", "", "Set SSH configuration file path or URL.")
+	fs.BoolVar(&shouldRestoreBackup, "restore-backup", false, "List hosts file backups and restore one of them")
+	fs.BoolVar(&shouldEncryptHosts, "encrypt-hosts", false, "Encrypt hosts file")
+	fs.BoolVar(&shouldDecryptHosts, "decrypt-hosts", false, "Decrypt hosts file")
+	fs.StringVar(
+		&cmdConfig.KeyFile,
+		"key-file",
+		envConfig.KeyFile,
+		"Use age or ssh private key instead of passphrase to encrypt hosts file",
+	)
+	fs.StringVar(
+		&cmdConfig.Inventory,
+		"inventory",
+		envConfig.Inventory,
+		fmt.Sprintf("Load additional host files or folders with *.yaml host files, separated by %q",
+			string(os.PathListSeparator)),
+	)
+	fs.StringVar(
+		&cmdConfig.DefaultInventory,
+		"default-inventory",
+		envConfig.DefaultInventory,
+		"Host file where new hosts are saved",
+	)
+
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(),
+			"Usage:\n  gg [options]\n  gg %s [options] - check ssh_config files and exit\n\nOptions:\n", CommandLint)
+		fs.PrintDefaults()
+	}
 
 	err := fs.Parse(args[1:]) // args should not include program name, see docs
 	if err != nil {
 		return nil, err
 	}
 
+	// Command can be followed by options, for instance "gg lint -s ~/.ssh/config".
+	shouldLintSSHConfig := fs.Arg(0) == CommandLint
+	if shouldLintSSHConfig {
+		if err = fs.Parse(fs.Args()[1:]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Backup count can only be set using environment variable.
+	cmdConfig.BackupCount = envConfig.BackupCount
+	// Command line sources replace the ones which are set by environment variable.
+	if len(cmdConfig.SSHConfigSources) == 0 {
+		cmdConfig.SSHConfigSources = envConfig.SSHConfigSources
+	}
+
 	switch {
 	case shouldDisplayVersionAndExit:
 		cmdConfig.AppMode = constant.AppModeType.DisplayInfo
+	case shouldLintSSHConfig:
+		cmdConfig.AppMode = constant.AppModeType.LintSSHConfig
+	case shouldRestoreBackup:
+		cmdConfig.AppMode = constant.AppModeType.RestoreBackup
+	case shouldEncryptHosts:
+		cmdConfig.AppMode = constant.AppModeType.EncryptHosts
+	case shouldDecryptHosts:
+		cmdConfig.AppMode = constant.AppModeType.DecryptHosts
 	case cmdConfig.EnableFeature != "":
 		fmt.Printf("[CONFIG] Enable feature %q\n", cmdConfig.EnableFeature.String())
 		cmdConfig.AppMode = constant.AppModeType.HandleParam
@@ -147,5 +213,9 @@ func setConfigDefaults(config *Configuration) (*Configuration, error) {
 		return nil, fmt.Errorf("unsupported log level: %q", config.LogLevel)
 	}
 
+	if config.BackupCount < 0 {
+		return nil, fmt.Errorf("backup count cannot be negative: %d", config.BackupCount)
+	}
+
 	return config, nil
 }
@@ -28,9 +28,11 @@ func Test_parseEnvironmentConfig(t *testing.T) {
 		t.Setenv("GG_HOME", "")
 		t.Setenv("GG_LOG_LEVEL", "")
 		t.Setenv("GG_SSH_CONFIG_FILE_PATH", "")
+		t.Setenv("GG_BACKUP_COUNT", "")
 
 		envConfig, err := parseEnvironmentVariables()
 		require.NoError(t, err)
+		require.Equal(t, 5, envConfig.BackupCount)
 		require.Empty(t, envConfig.AppHome)
 		require.Empty(t, envConfig.AppMode)
 		require.Empty(t, envConfig.DisableFeature)
@@ -44,9 +46,19 @@ func Test_parseEnvironmentConfig(t *testing.T) {
 		t.Setenv("GG_HOME", "/root")
 		t.Setenv("GG_LOG_LEVEL", "debug")
 		t.Setenv("GG_SSH_CONFIG_FILE_PATH", "/tmp/custom_config")
+		t.Setenv("GG_BACKUP_COUNT", "10")
+		t.Setenv("GG_KEY_FILE", "/root/.ssh/id_ed25519")
+		t.Setenv("GG_INVENTORY", "/team/inventory:/root/personal.yaml")
+		t.Setenv("GG_DEFAULT_INVENTORY", "/root/personal.yaml")
+		t.Setenv("GG_SSH_CONFIG_SOURCES", "/team/ssh_config,https://example.com/ssh_config")
 
 		envConfig, err := parseEnvironmentVariables()
 		require.NoError(t, err)
+		require.Equal(t, 10, envConfig.BackupCount)
+		require.Equal(t, "/root/.ssh/id_ed25519", envConfig.KeyFile)
+		require.Equal(t, "/team/inventory:/root/personal.yaml", envConfig.Inventory)
+		require.Equal(t, "/root/personal.yaml", envConfig.DefaultInventory)
+		require.Equal(t, StringList{"/team/ssh_config", "https://example.com/ssh_config"}, envConfig.SSHConfigSources)
 		require.Equal(t, "/root", envConfig.AppHome)
 		require.Empty(t, envConfig.AppMode)
 		require.Empty(t, envConfig.DisableFeature)
@@ -60,6 +72,7 @@ func Test_parseEnvironmentConfig(t *testing.T) {
 func Test_parseCommandLineFlags(t *testing.T) {
 	envConfig := &Configuration{
 		AppHome:       "/tmp/home",
+		BackupCount:   3,
 		LogLevel:      "info",
 		SSHConfigPath: "/tmp/custom_config",
 	}
@@ -200,6 +213,76 @@ func Test_parseCommandLineFlags(t *testing.T) {
 				SetTheme:         "",
 			},
 			wantError: false,
+		}, {
+			name: "Restore backup",
+			args: []string{"--restore-backup"},
+			wantConfig: &Configuration{
+				AppHome:        "/tmp/home",
+				AppMode:        "RESTORE_BACKUP",
+				DisableFeature: "",
+				EnableFeature:  "",
+				LogLevel:       "info",
+				SSHConfigPath:  "/tmp/custom_config",
+				SetTheme:       "",
+			},
+			wantError: false,
+		}, {
+			name: "Encrypt hosts with key file",
+			args: []string{"--encrypt-hosts", "--key-file", "/tmp/id_ed25519"},
+			wantConfig: &Configuration{
+				AppHome:       "/tmp/home",
+				AppMode:       "ENCRYPT_HOSTS",
+				KeyFile:       "/tmp/id_ed25519",
+				LogLevel:      "info",
+				SSHConfigPath: "/tmp/custom_config",
+			},
+			wantError: false,
+		}, {
+			name: "Inventory",
+			args: []string{"--inventory", "/team/inventory", "--default-inventory", "/team/inventory/new.yaml"},
+			wantConfig: &Configuration{
+				AppHome:          "/tmp/home",
+				DefaultInventory: "/team/inventory/new.yaml",
+				Inventory:        "/team/inventory",
+				LogLevel:         "info",
+				SSHConfigPath:    "/tmp/custom_config",
+			},
+			wantError: false,
+		}, {
+			name: "Additional ssh_config sources",
+			args: []string{"--ssh-config-source", "/team/ssh_config", "--ssh-config-source", "https://example.com/ssh_config"},
+			wantConfig: &Configuration{
+				AppHome:          "/tmp/home",
+				LogLevel:         "info",
+				SSHConfigPath:    "/tmp/custom_config",
+				SSHConfigSources: StringList{"/team/ssh_config", "https://example.com/ssh_config"},
+			},
+			wantError: false,
+		}, {
+			name: "Decrypt hosts",
+			args: []string{"--decrypt-hosts"},
+			wantConfig: &Configuration{
+				AppHome:       "/tmp/home",
+				AppMode:       "DECRYPT_HOSTS",
+				LogLevel:      "info",
+				SSHConfigPath: "/tmp/custom_config",
+			},
+			wantError: false,
+		}, {
+			name: "Lint ssh_config with options after the command",
+			args: []string{"-l", "debug", "lint", "-s", "/team/ssh_config"},
+			wantConfig: &Configuration{
+				AppHome:       "/tmp/home",
+				AppMode:       "LINT_SSH_CONFIG",
+				LogLevel:      "debug",
+				SSHConfigPath: "/team/ssh_config",
+			},
+			wantError: false,
+		}, {
+			name:       "Lint ssh_config with unknown option",
+			args:       []string{"lint", "--unknown"},
+			wantConfig: nil,
+			wantError:  true,
 		},
 	}
 
@@ -224,6 +307,11 @@ func Test_parseCommandLineFlags(t *testing.T) {
 			require.Equal(t, tt.wantConfig.SSHConfigPath, cfg.SSHConfigPath)
 			require.Equal(t, tt.wantConfig.SetSSHConfigPath, cfg.SetSSHConfigPath)
 			require.Equal(t, tt.wantConfig.SetTheme, cfg.SetTheme)
+			require.Equal(t, tt.wantConfig.KeyFile, cfg.KeyFile)
+			require.Equal(t, tt.wantConfig.Inventory, cfg.Inventory)
+			require.Equal(t, tt.wantConfig.DefaultInventory, cfg.DefaultInventory)
+			require.Equal(t, tt.wantConfig.SSHConfigSources, cfg.SSHConfigSources)
+			require.Equal(t, envConfig.BackupCount, cfg.BackupCount)
 		})
 	}
 }
@@ -260,4 +348,14 @@ func Test_setConfigDefaults(t *testing.T) {
 
 	_, err = setConfigDefaults(config)
 	require.Error(t, err)
+
+	// Test with negative backup count
+	config = &Configuration{
+		AppHome:     tempDir,
+		LogLevel:    constant.LogLevelType.INFO,
+		BackupCount: -1,
+	}
+
+	_, err = setConfigDefaults(config)
+	require.Error(t, err)
 }
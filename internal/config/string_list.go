@@ -0,0 +1,16 @@
+package config
+
+import "strings"
+
+// StringList represents a command line flag which can be used several times, every value is appended to the list.
+type StringList []string
+
+func (sl *StringList) String() string {
+	return strings.Join(*sl, ",")
+}
+
+// Set appends the value to the list.
+func (sl *StringList) Set(value string) error {
+	*sl = append(*sl, value)
+	return nil
+}
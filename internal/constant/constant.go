@@ -40,13 +40,15 @@ type HostStorageEnum string
 
 // HostStorageType defines the type of the underlying storage of a host.
 var HostStorageType = struct {
-	Combined  HostStorageEnum
-	SSHConfig HostStorageEnum
-	YAMLFile  HostStorageEnum
+	Combined          HostStorageEnum
+	EncryptedYAMLFile HostStorageEnum
+	SSHConfig         HostStorageEnum
+	YAMLFile          HostStorageEnum
 }{
-	Combined:  "COMBINED",
-	SSHConfig: "SSH_CONFIG",
-	YAMLFile:  "YAML_FILE",
+	Combined:          "COMBINED",
+	EncryptedYAMLFile: "ENCRYPTED_YAML_FILE",
+	SSHConfig:         "SSH_CONFIG",
+	YAMLFile:          "YAML_FILE",
 }
 
 type LogLevel = string
@@ -62,11 +64,19 @@ var LogLevelType = struct {
 type AppMode = string
 
 var AppModeType = struct {
-	StartUI     AppMode
-	DisplayInfo AppMode
-	HandleParam AppMode
+	StartUI       AppMode
+	DisplayInfo   AppMode
+	HandleParam   AppMode
+	RestoreBackup AppMode
+	EncryptHosts  AppMode
+	DecryptHosts  AppMode
+	LintSSHConfig AppMode
 }{
-	StartUI:     "START_UI",
-	DisplayInfo: "DISPLAY_INFO",
-	HandleParam: "HANDLE_PARAM",
+	StartUI:       "START_UI",
+	DisplayInfo:   "DISPLAY_INFO",
+	HandleParam:   "HANDLE_PARAM",
+	RestoreBackup: "RESTORE_BACKUP",
+	EncryptHosts:  "ENCRYPT_HOSTS",
+	DecryptHosts:  "DECRYPT_HOSTS",
+	LintSSHConfig: "LINT_SSH_CONFIG",
 }
@@ -0,0 +1,135 @@
+package host
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafviktor/goto/internal/model/sshcommand"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// ForwardType - is the kind of port forwarding, see ssh '-L', '-R' and '-D' options.
+type ForwardType string
+
+// ForwardTypes - supported port forwarding kinds.
+var ForwardTypes = struct {
+	Local   ForwardType
+	Remote  ForwardType
+	Dynamic ForwardType
+}{
+	Local:   "local",
+	Remote:  "remote",
+	Dynamic: "dynamic",
+}
+
+// Forward is a named port forwarding. Spec is written as ssh expects it: "[bind_address:]port:host:hostport"
+// for local and remote forwarding, and "[bind_address:]port" for dynamic forwarding.
+type Forward struct {
+	Name string      `yaml:"name"`
+	Type ForwardType `yaml:"type"`
+	Spec string      `yaml:"spec"`
+}
+
+// Validate - returns an error if ssh cannot use the forwarding.
+func (f Forward) Validate() error {
+	if utils.StringEmpty(&f.Name) || strings.ContainsAny(f.Name, " ,") {
+		return errors.New("forward name must not be empty or contain spaces and commas")
+	}
+
+	parts := splitForwardSpec(f.Spec)
+	switch f.Type {
+	case ForwardTypes.Local, ForwardTypes.Remote:
+		if len(parts) != 3 && len(parts) != 4 {
+			return fmt.Errorf("forward %q must be written as [bind_address:]port:host:hostport", f.Name)
+		}
+
+		if utils.StringEmpty(&parts[len(parts)-2]) {
+			return fmt.Errorf("forward %q does not contain destination host", f.Name)
+		}
+
+		// Remote port 0 makes the server allocate a port.
+		if !isValidPort(parts[len(parts)-1], false) || !isValidPort(parts[len(parts)-3], f.Type == ForwardTypes.Remote) {
+			return fmt.Errorf("forward %q contains invalid port", f.Name)
+		}
+	case ForwardTypes.Dynamic:
+		if len(parts) != 1 && len(parts) != 2 {
+			return fmt.Errorf("forward %q must be written as [bind_address:]port", f.Name)
+		}
+
+		if !isValidPort(parts[len(parts)-1], false) {
+			return fmt.Errorf("forward %q contains invalid port", f.Name)
+		}
+	default:
+		return fmt.Errorf("forward %q has unknown type %q", f.Name, f.Type)
+	}
+
+	return nil
+}
+
+// ListenPort - returns the port which ssh listens to. It's a local port for local and dynamic forwarding,
+// and a port on the remote host for remote forwarding.
+func (f Forward) ListenPort() string {
+	parts := splitForwardSpec(f.Spec)
+	if f.Type == ForwardTypes.Dynamic {
+		return parts[len(parts)-1]
+	}
+
+	if len(parts) < 3 {
+		return ""
+	}
+
+	return parts[len(parts)-3]
+}
+
+// Destination - returns the host and the port, where connections are forwarded to. Dynamic forwarding does
+// not have a destination, an empty string is returned.
+func (f Forward) Destination() string {
+	parts := splitForwardSpec(f.Spec)
+	if f.Type == ForwardTypes.Dynamic || len(parts) < 3 {
+		return ""
+	}
+
+	return strings.Join(parts[len(parts)-2:], ":")
+}
+
+// option - returns ssh command option for the forwarding.
+func (f Forward) option() sshcommand.Option {
+	switch f.Type {
+	case ForwardTypes.Remote:
+		return sshcommand.OptionRemoteForward{Value: f.Spec}
+	case ForwardTypes.Dynamic:
+		return sshcommand.OptionDynamicForward{Value: f.Spec}
+	default:
+		return sshcommand.OptionLocalForward{Value: f.Spec}
+	}
+}
+
+// splitForwardSpec - splits the spec by colons, which are not enclosed in square brackets,
+// so that IPv6 addresses are not split: "[::1]:8080:db:5432" => "[::1]", "8080", "db", "5432".
+func splitForwardSpec(spec string) []string {
+	parts := []string{}
+	depth := 0
+	start := 0
+	for i, r := range spec {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ':':
+			if depth == 0 {
+				parts = append(parts, spec[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, spec[start:])
+}
+
+func isValidPort(value string, allowZero bool) bool {
+	port, err := strconv.ParseUint(value, 10, 16)
+	return err == nil && (port > 0 || allowZero)
+}
@@ -0,0 +1,61 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/constant"
+)
+
+func TestForward_Validate(t *testing.T) {
+	tests := []struct {
+		forward Forward
+		valid   bool
+	}{
+		{forward: Forward{Name: "db", Type: ForwardTypes.Local, Spec: "5432:db.internal:5432"}, valid: true},
+		{forward: Forward{Name: "db", Type: ForwardTypes.Local, Spec: "127.0.0.1:5432:db.internal:5432"}, valid: true},
+		{forward: Forward{Name: "db", Type: ForwardTypes.Local, Spec: "[::1]:5432:[2001:db8::10]:5432"}, valid: true},
+		{forward: Forward{Name: "web", Type: ForwardTypes.Remote, Spec: "0:localhost:80"}, valid: true},
+		{forward: Forward{Name: "socks", Type: ForwardTypes.Dynamic, Spec: "1080"}, valid: true},
+		{forward: Forward{Name: "socks", Type: ForwardTypes.Dynamic, Spec: "localhost:1080"}, valid: true},
+		{forward: Forward{Name: "", Type: ForwardTypes.Local, Spec: "5432:db:5432"}, valid: false},
+		{forward: Forward{Name: "my db", Type: ForwardTypes.Local, Spec: "5432:db:5432"}, valid: false},
+		{forward: Forward{Name: "db", Type: ForwardTypes.Local, Spec: "0:db:5432"}, valid: false},
+		{forward: Forward{Name: "db", Type: ForwardTypes.Local, Spec: "5432:db"}, valid: false},
+		{forward: Forward{Name: "db", Type: ForwardTypes.Local, Spec: "5432::5432"}, valid: false},
+		{forward: Forward{Name: "db", Type: ForwardTypes.Local, Spec: "5432:db:70000"}, valid: false},
+		{forward: Forward{Name: "socks", Type: ForwardTypes.Dynamic, Spec: "1080:db:5432"}, valid: false},
+		{forward: Forward{Name: "db", Type: "unknown", Spec: "5432:db:5432"}, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.forward.Type)+" "+tt.forward.Spec, func(t *testing.T) {
+			require.Equal(t, tt.valid, tt.forward.Validate() == nil)
+		})
+	}
+}
+
+func TestForward_ListenPortAndDestination(t *testing.T) {
+	local := Forward{Name: "db", Type: ForwardTypes.Local, Spec: "[::1]:5432:[2001:db8::10]:6432"}
+	require.Equal(t, "5432", local.ListenPort())
+	require.Equal(t, "[2001:db8::10]:6432", local.Destination())
+
+	dynamic := Forward{Name: "socks", Type: ForwardTypes.Dynamic, Spec: "localhost:1080"}
+	require.Equal(t, "1080", dynamic.ListenPort())
+	require.Empty(t, dynamic.Destination())
+}
+
+func TestCmdSSHTunnel(t *testing.T) {
+	forward := Forward{Name: "db", Type: ForwardTypes.Local, Spec: "5432:db.internal:5432"}
+	tunnelOptions := " -N -o BatchMode=yes -o ExitOnForwardFailure=yes -L 5432:db.internal:5432"
+
+	host := Host{Address: "localhost", LoginName: "root", RemotePort: "2222", JumpChain: "bastion"}
+	require.Equal(t, osCmdPrefix+"ssh"+tunnelOptions+" -p 2222 -l root -J bastion localhost", host.CmdSSHTunnel(forward))
+
+	host = Host{Title: "db-gateway", StorageType: constant.HostStorageType.SSHConfig}
+	require.Equal(t, osCmdPrefix+"ssh"+tunnelOptions+" db-gateway", host.CmdSSHTunnel(forward))
+
+	host = Host{Address: "user@localhost -p 2222"}
+	require.Equal(t, osCmdPrefix+"ssh"+tunnelOptions+" user@localhost -p 2222", host.CmdSSHTunnel(forward))
+}
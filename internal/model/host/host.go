@@ -2,29 +2,103 @@
 package host
 
 import (
+	"net/url"
+	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/samber/lo"
+
 	"github.com/grafviktor/goto/internal/constant"
 	"github.com/grafviktor/goto/internal/model/sshcommand"
 	"github.com/grafviktor/goto/internal/model/sshconfig"
+	"github.com/grafviktor/goto/internal/utils"
 )
 
+// Directive is a single ssh_config option, for instance "ProxyJump bastion". Value is stored
+// as it's written in the file, including quotes.
+type Directive struct {
+	Keyword string
+	Value   string
+}
+
+// PatternBlock is an ssh_config "Host" block, which is declared with wildcard or negated patterns,
+// for instance "Host *.prod !db.prod". It is not a host on its own, but its options apply to all
+// hosts which match the patterns. Patterns is empty for the options which are declared before
+// the first "Host" line, as they apply to all hosts.
+type PatternBlock struct {
+	Patterns   string
+	SourcePath string
+	Line       int
+	Directives []Directive
+}
+
+// InheritedDirective is an option, which applies to a host, but is declared in another "Host" block.
+// Pattern, SourcePath and Line point to that block. Overrides is true when the host block declares
+// the same option, but ssh uses the inherited value, because it's obtained first.
+type InheritedDirective struct {
+	Directive
+	Pattern    string
+	SourcePath string
+	Line       int
+	Overrides  bool
+}
+
 // Host model definition.
 type Host struct {
-	Address          string                   `yaml:"address"`
-	Description      string                   `yaml:"description,omitempty"`
-	Group            string                   `yaml:"group,omitempty"`
-	ID               int                      `yaml:"-"`
-	IdentityFilePath string                   `yaml:"identity_file_path,omitempty"`
-	LoginName        string                   `yaml:"username,omitempty"`
-	RemotePort       string                   `yaml:"network_port,omitempty"`
-	SSHHostConfig    *sshconfig.Config        `yaml:"-"`
-	StorageType      constant.HostStorageEnum `yaml:"-"`
-	Title            string                   `yaml:"title"`
+	Address string `yaml:"address"`
+	// Aliases are additional patterns from ssh_config "Host" line.
+	Aliases []string `yaml:"-"`
+	// Color is read from '# GG:COLOR' metadata in ssh_config.
+	Color       string `yaml:"-"`
+	Description string `yaml:"description,omitempty"`
+	// Directives contain all options from ssh_config host block in the order they're declared,
+	// including the ones which are mapped to other fields, like User or Port.
+	Directives []Directive `yaml:"-"`
+	// Forwards are port forwardings, which are started in background, see CmdSSHTunnel.
+	Forwards []Forward `yaml:"forwards,omitempty"`
+	Group    string    `yaml:"group,omitempty"`
+	// Hidden is read from '# GG:HIDE' metadata in ssh_config.
+	Hidden           bool   `yaml:"-"`
+	ID               string `yaml:"id"`
+	IdentityFilePath string `yaml:"identity_file_path,omitempty"`
+	// Inherited contains effective options, which the host receives from wildcard "Host" blocks.
+	Inherited []InheritedDirective `yaml:"-"`
+	// JumpChain is the value of ssh '-J' option, which is built from ProxyJump, see ResolveJumpChain.
+	JumpChain string `yaml:"-"`
+	LoginName string `yaml:"username,omitempty"`
+	// MatchCriteria are criteria of ssh_config "Match" blocks, which might apply to the host.
+	MatchCriteria []string `yaml:"-"`
+	// Note is read from '# GG:NOTE' metadata in ssh_config.
+	Note string `yaml:"-"`
+	// Pinned is read from '# GG:PIN' metadata in ssh_config.
+	Pinned bool `yaml:"-"`
+	// ProxyJump contains IDs of jump hosts, which are used to reach the host.
+	ProxyJump []string `yaml:"proxy_jump,omitempty"`
+	// RemoteCommand is executed on the host instead of the login shell.
+	RemoteCommand string `yaml:"remote_command,omitempty"`
+	RemotePort    string `yaml:"network_port,omitempty"`
+	// RequestTTY is one of RequestTTYModes.
+	RequestTTY string `yaml:"request_tty,omitempty"`
+	// SSHConfigSource is only set for hosts which are loaded from additional ssh_config sources,
+	// such hosts are readonly.
+	SSHConfigSource string            `yaml:"-"`
+	SSHHostConfig   *sshconfig.Config `yaml:"-"`
+	// SSHOptions are additional ssh flags and options in "Keyword=value" format, see sshcommand.ExtraOption.
+	SSHOptions  []string                 `yaml:"ssh_options,omitempty"`
+	SourcePath  string                   `yaml:"-"`
+	StorageType constant.HostStorageEnum `yaml:"-"`
+	// Tags are stored in yaml file or read from '# GG:TAGS' metadata in ssh_config, see MatchTags.
+	Tags  []string `yaml:"tags,omitempty"`
+	Title string   `yaml:"title"`
 }
 
+// RequestTTYModes - values of RequestTTY setting, see ssh_config(5). When the setting is empty, a terminal
+// is requested for the remote command, see Host.CmdSSHConnect.
+var RequestTTYModes = []string{"auto", "yes", "force", "no"}
+
 // NewHost - constructs new Host model.
-func NewHost(id int, title, description, address, loginName, identityFilePath, remotePort string) Host {
+func NewHost(id, title, description, address, loginName, identityFilePath, remotePort string) Host {
 	return Host{
 		ID:               id,
 		Title:            title,
@@ -41,11 +115,19 @@ func (h *Host) Clone() Host {
 	newHost := Host{
 		Title:            h.Title,
 		Group:            h.Group,
+		Tags:             slices.Clone(h.Tags),
 		Description:      h.Description,
 		Address:          h.Address,
 		LoginName:        h.LoginName,
 		IdentityFilePath: h.IdentityFilePath,
 		RemotePort:       h.RemotePort,
+		ProxyJump:        slices.Clone(h.ProxyJump),
+		Forwards:         slices.Clone(h.Forwards),
+		SSHOptions:       slices.Clone(h.SSHOptions),
+		RemoteCommand:    h.RemoteCommand,
+		RequestTTY:       h.RequestTTY,
+		SourcePath:       h.SourcePath,
+		SSHConfigSource:  h.SSHConfigSource,
 		StorageType:      h.StorageType,
 	}
 
@@ -66,52 +148,160 @@ func (h *Host) IsUserDefinedSSHCommand() bool {
 // CmdSSHConnect - returns SSH command for connecting to a remote host.
 func (h *Host) CmdSSHConnect() string {
 	if h.IsUserDefinedSSHCommand() {
-		return sshcommand.Build(sshcommand.OptionAddress{Value: h.Address})
+		return sshcommand.Build(h.sessionOptions(sshcommand.OptionAddress{Value: h.Address})...)
 	}
 
 	if h.StorageType == constant.HostStorageType.SSHConfig {
 		// When it's SSHConfig storage type, we need to use the title as a host name.
 		// This is because the by addressing the host by alias, we get all its settings from ssh_config.
-		return sshcommand.Build(sshcommand.OptionAddress{Value: h.Title})
+		return sshcommand.Build(h.sshConfigFileOptions(h.sessionOptions(sshcommand.OptionAddress{Value: h.Title})...)...)
 	}
 
-	return sshcommand.Build([]sshcommand.Option{
+	options := append([]sshcommand.Option{
 		sshcommand.OptionPrivateKey{Value: h.IdentityFilePath},
 		sshcommand.OptionRemotePort{Value: h.RemotePort},
 		sshcommand.OptionLoginName{Value: h.LoginName},
-		sshcommand.OptionAddress{Value: h.Address},
-	}...)
+		sshcommand.OptionProxyJump{Value: h.JumpChain},
+	}, h.extraOptions()...)
+
+	return sshcommand.Build(append(options, h.sessionOptions(sshcommand.OptionAddress{Value: h.Address})...)...)
+}
+
+// sessionOptions - surrounds the address with terminal and remote command options. When RequestTTY is not set,
+// a terminal is requested for the remote command, because the command runs in the user's terminal, and commands
+// like "tmux" or "sudo -i" require it.
+func (h *Host) sessionOptions(address sshcommand.Option) []sshcommand.Option {
+	if utils.StringEmpty(&h.RemoteCommand) {
+		return []sshcommand.Option{sshcommand.OptionRequestTTY{Value: h.RequestTTY}, address}
+	}
+
+	requestTTY := lo.Ternary(utils.StringEmpty(&h.RequestTTY), "yes", h.RequestTTY)
+	options := []sshcommand.Option{sshcommand.OptionRequestTTY{Value: requestTTY}}
+	// ssh does not run the command, if ssh_config declares another one for the host.
+	if h.SSHHostConfig != nil {
+		if configured := h.SSHHostConfig.Value("remotecommand"); configured != "" && configured != "none" {
+			options = append(options, sshcommand.OptionSSHOption{Value: "RemoteCommand=none"})
+		}
+	}
+
+	return append(options, address, sshcommand.OptionRemoteCommand{Value: h.RemoteCommand})
 }
 
 // CmdSSHConfig - returns SSH command for loading host default configuration.
 func (h *Host) CmdSSHConfig() string {
 	if h.StorageType == constant.HostStorageType.SSHConfig {
-		return sshcommand.Build(sshcommand.OptionReadHostConfig{Value: h.Title})
+		return sshcommand.Build(h.sshConfigFileOptions(sshcommand.OptionReadHostConfig{Value: h.Title})...)
 	}
 
 	if h.IsUserDefinedSSHCommand() {
 		return sshcommand.Build(sshcommand.OptionReadHostConfig{Value: h.Address})
 	}
 
-	return sshcommand.Build([]sshcommand.Option{
+	// Extra options are passed to 'ssh -G' as well, so that the effective config reflects them.
+	options := append([]sshcommand.Option{
 		sshcommand.OptionPrivateKey{Value: h.IdentityFilePath},
 		sshcommand.OptionRemotePort{Value: h.RemotePort},
 		sshcommand.OptionLoginName{Value: h.LoginName},
-		sshcommand.OptionReadHostConfig{Value: h.Address},
-	}...)
+		sshcommand.OptionProxyJump{Value: h.JumpChain},
+	}, h.extraOptions()...)
+
+	return sshcommand.Build(append(options, sshcommand.OptionReadHostConfig{Value: h.Address})...)
+}
+
+// CmdSSHTunnel - returns SSH command, which only starts port forwarding and does not open a shell.
+// The command must not ask for a password, because it runs in background. It ends when ssh cannot
+// listen to the port, instead of connecting without the forwarding.
+func (h *Host) CmdSSHTunnel(forward Forward) string {
+	options := []sshcommand.Option{
+		sshcommand.OptionNoRemoteCommand{},
+		sshcommand.OptionSSHOption{Value: "BatchMode=yes"},
+		sshcommand.OptionSSHOption{Value: "ExitOnForwardFailure=yes"},
+		forward.option(),
+	}
+
+	if h.IsUserDefinedSSHCommand() {
+		return sshcommand.Build(append(options, sshcommand.OptionAddress{Value: h.Address})...)
+	}
+
+	if h.StorageType == constant.HostStorageType.SSHConfig {
+		return sshcommand.Build(h.sshConfigFileOptions(append(options, sshcommand.OptionAddress{Value: h.Title})...)...)
+	}
+
+	options = append(options,
+		sshcommand.OptionPrivateKey{Value: h.IdentityFilePath},
+		sshcommand.OptionRemotePort{Value: h.RemotePort},
+		sshcommand.OptionLoginName{Value: h.LoginName},
+		sshcommand.OptionProxyJump{Value: h.JumpChain},
+	)
+	options = append(options, h.extraOptions()...)
+
+	return sshcommand.Build(append(options, sshcommand.OptionAddress{Value: h.Address})...)
 }
 
 // CmdSSHCopyID - returns SSH command for copying SSH key to a remote host (see ssh-copy-id).
+// The key is copied through the jump hosts, which are printed by 'ssh -G', unless the chain
+// of the host is resolved.
 func (h *Host) CmdSSHCopyID() string {
-	return sshcommand.CopyIDCommand(
+	jumpChain := h.JumpChain
+	if utils.StringEmpty(&jumpChain) && h.SSHHostConfig.Value("proxyjump") != "none" {
+		jumpChain = h.SSHHostConfig.Value("proxyjump")
+	}
+
+	options := append([]sshcommand.Option{
 		sshcommand.OptionLoginName{Value: h.SSHHostConfig.User},
 		sshcommand.OptionRemotePort{Value: h.SSHHostConfig.Port},
 		sshcommand.OptionPrivateKey{Value: h.SSHHostConfig.IdentityFile},
-		sshcommand.OptionAddress{Value: h.SSHHostConfig.Hostname},
-	)
+		sshcommand.OptionProxyJump{Value: jumpChain},
+	}, h.extraOptions()...)
+
+	return sshcommand.CopyIDCommand(append(options, sshcommand.OptionAddress{Value: h.SSHHostConfig.Hostname})...)
+}
+
+// extraOptions - returns additional ssh flags and options of the host in the order they're declared.
+func (h *Host) extraOptions() []sshcommand.Option {
+	options := make([]sshcommand.Option, 0, len(h.SSHOptions))
+	for _, value := range h.SSHOptions {
+		options = append(options, sshcommand.ExtraOption(value))
+	}
+
+	return options
+}
+
+// sshConfigFileOptions - prepends ssh_config file option, when the host is loaded from an additional
+// ssh_config source, so that ssh reads the host settings from that source.
+func (h *Host) sshConfigFileOptions(options ...sshcommand.Option) []sshcommand.Option {
+	if utils.StringEmpty(&h.SSHConfigSource) {
+		return options
+	}
+
+	configFile := sshcommand.OptionConfigFilePath{Value: sshconfig.PathForSource(h.SSHConfigSource)}
+	return append([]sshcommand.Option{configFile}, options...)
 }
 
 // IsReadOnly - returns true if host storage does not support modification.
+// Hosts loaded from a local ssh_config file are writable, whilst hosts which
+// come from a remote location (URL) or from additional sources are readonly.
 func (h *Host) IsReadOnly() bool {
-	return h.StorageType == constant.HostStorageType.SSHConfig
+	if h.StorageType != constant.HostStorageType.SSHConfig {
+		return false
+	}
+
+	return utils.IsSupportedURL(h.SourcePath) || !utils.StringEmpty(&h.SSHConfigSource)
+}
+
+// SourceLabel - returns a short name of the additional ssh_config source, which the host is loaded
+// from: host name for remote sources and file name for local ones. Returns an empty string for
+// other hosts.
+func (h *Host) SourceLabel() string {
+	if utils.StringEmpty(&h.SSHConfigSource) {
+		return ""
+	}
+
+	if utils.IsSupportedURL(h.SSHConfigSource) {
+		if sourceURL, err := url.Parse(h.SSHConfigSource); err == nil {
+			return sourceURL.Host
+		}
+	}
+
+	return filepath.Base(h.SSHConfigSource)
 }
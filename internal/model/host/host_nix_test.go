@@ -28,3 +28,34 @@ func TestCmdSSHCopyID(t *testing.T) {
 	actual := host.CmdSSHCopyID()
 	require.Equal(t, "ssh-copy-id -p 2222 -i /home/username/.ssh/test root@localhost", actual)
 }
+
+func TestCmdSSHCopyID_ProxyJump(t *testing.T) {
+	host := Host{
+		JumpChain: "admin@bastion:2222",
+		SSHHostConfig: &sshconfig.Config{
+			Hostname: "localhost",
+			User:     "root",
+		},
+	}
+
+	require.Equal(t, "ssh-copy-id -o ProxyJump=admin@bastion:2222 root@localhost", host.CmdSSHCopyID())
+
+	// Jump hosts, which are declared in ssh_config, are printed by 'ssh -G'.
+	host.JumpChain = ""
+	host.SSHHostConfig = sshconfig.Parse("hostname localhost\nuser root\nproxyjump gateway")
+	require.Equal(t, "ssh-copy-id -o ProxyJump=gateway root@localhost", host.CmdSSHCopyID())
+}
+
+func TestCmdSSHCopyID_ExtraOptions(t *testing.T) {
+	host := Host{
+		SSHOptions: []string{"-A", "StrictHostKeyChecking=accept-new"},
+		SSHHostConfig: &sshconfig.Config{
+			Hostname: "localhost",
+			User:     "root",
+		},
+	}
+
+	// ssh-copy-id only passes options to ssh, flags are converted to options.
+	expected := "ssh-copy-id -o ForwardAgent=yes -o StrictHostKeyChecking=accept-new root@localhost"
+	require.Equal(t, expected, host.CmdSSHCopyID())
+}
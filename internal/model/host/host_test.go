@@ -2,17 +2,19 @@ package host
 
 import (
 	"fmt"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafviktor/goto/internal/constant"
+	"github.com/grafviktor/goto/internal/model/sshconfig"
 )
 
 func TestNewHost(t *testing.T) {
 	expectedHost := Host{
-		ID:               1,
+		ID:               "1",
 		Title:            "TestTitle",
 		Description:      "TestDescription",
 		Address:          "TestAddress",
@@ -39,23 +41,28 @@ func TestNewHost(t *testing.T) {
 func TestCloneHost(t *testing.T) {
 	// Create a host to clone
 	originalHost := Host{
-		ID:               1,
+		ID:               "1",
 		Title:            "TestTitle",
 		Description:      "TestDescription",
 		Address:          "TestAddress",
 		RemotePort:       "1234",
 		LoginName:        "TestUser",
 		IdentityFilePath: "/path/to/private/key",
+		Tags:             []string{"prod", "db"},
+		ProxyJump:        []string{"2"},
+		SSHOptions:       []string{"-A"},
+		RemoteCommand:    "uptime",
+		RequestTTY:       "yes",
+		Forwards:         []Forward{{Name: "db", Type: ForwardTypes.Local, Spec: "5432:db:5432"}},
 	}
 
 	// Clone the host
 	clonedHost := originalHost.Clone()
 
-	// ID of the new host should always be "0", we should not copy the ID of the original host
-	require.Equal(t,
-		0,
+	// ID of the new host should always be empty, we should not copy the ID of the original host
+	require.Empty(t,
 		clonedHost.ID,
-		"Clone function should create a new host, but host ID should be equal to '0'",
+		"Clone function should create a new host, but host ID should be empty",
 	)
 
 	// Set the ID of the cloned host to the original host's ID just for the sake of using DeepEqual.
@@ -115,6 +122,16 @@ func TestIsUserDefinedSSHCommand(t *testing.T) {
 			},
 			expected: fmt.Sprintf("%s%s", osCmdPrefix, "ssh LOCALHOST_ALIAS"),
 		},
+		{
+			name: "Host loaded from additional ssh_config source",
+			host: Host{
+				Address:         "localhost",
+				Title:           "LOCALHOST_ALIAS",
+				StorageType:     constant.HostStorageType.SSHConfig,
+				SSHConfigSource: "/tmp/team_config",
+			},
+			expected: fmt.Sprintf("%s%s", osCmdPrefix, `ssh -F "/tmp/team_config" LOCALHOST_ALIAS`),
+		},
 	}
 
 	for _, tt := range tests {
@@ -141,6 +158,14 @@ func TestCmdSSHConfig(t *testing.T) {
 			},
 			expected: fmt.Sprintf("%s%s", osCmdPrefix, "ssh -i /tmp -p 2222 -l root -G localhost"),
 		},
+		{
+			name: "NOT user defined ssh command - extra ssh options",
+			host: Host{
+				Address:    "localhost",
+				SSHOptions: []string{"-A", "ServerAliveInterval=30"},
+			},
+			expected: fmt.Sprintf("%s%s", osCmdPrefix, "ssh -A -o ServerAliveInterval=30 -G localhost"),
+		},
 		{
 			name: "User defined ssh command",
 			host: Host{
@@ -167,6 +192,16 @@ func TestCmdSSHConfig(t *testing.T) {
 			},
 			expected: fmt.Sprintf("%s%s", osCmdPrefix, "ssh -G LOCALHOST_ALIAS"),
 		},
+		{
+			name: "Host loaded from additional ssh_config source",
+			host: Host{
+				Address:         "localhost",
+				Title:           "LOCALHOST_ALIAS",
+				StorageType:     constant.HostStorageType.SSHConfig,
+				SSHConfigSource: "/tmp/team_config",
+			},
+			expected: fmt.Sprintf("%s%s", osCmdPrefix, `ssh -F "/tmp/team_config" -G LOCALHOST_ALIAS`),
+		},
 	}
 
 	for _, tt := range tests {
@@ -212,3 +247,93 @@ func TestCmdSSHConnect(t *testing.T) {
 		})
 	}
 }
+
+func TestCmdSSHConnect_ExtraOptions(t *testing.T) {
+	host := Host{
+		Address:    "localhost",
+		LoginName:  "root",
+		SSHOptions: []string{"-C", "StrictHostKeyChecking=accept-new"},
+	}
+
+	expected := fmt.Sprintf("%s%s", osCmdPrefix, "ssh -l root -C -o StrictHostKeyChecking=accept-new localhost")
+	require.Equal(t, expected, host.CmdSSHConnect())
+
+	// Custom ssh commands contain all options in the command.
+	host.Address = "root@localhost -p 2222"
+	require.Equal(t, fmt.Sprintf("%s%s", osCmdPrefix, "ssh root@localhost -p 2222"), host.CmdSSHConnect())
+}
+
+func TestCmdSSHConnect_RemoteCommand(t *testing.T) {
+	host := Host{Address: "localhost", RemoteCommand: "tmux new -A -s main"}
+	// Terminal is requested for the remote command, unless RequestTTY is set.
+	expected := fmt.Sprintf("%s%s", osCmdPrefix, `ssh -t localhost "tmux new -A -s main"`)
+	require.Equal(t, expected, host.CmdSSHConnect())
+
+	host.RequestTTY = "no"
+	expected = fmt.Sprintf("%s%s", osCmdPrefix, `ssh -T localhost "tmux new -A -s main"`)
+	require.Equal(t, expected, host.CmdSSHConnect())
+
+	// RemoteCommand, which is declared in ssh_config, is replaced.
+	host = Host{
+		Title:         "LOCALHOST_ALIAS",
+		StorageType:   constant.HostStorageType.SSHConfig,
+		RemoteCommand: "uptime",
+		SSHHostConfig: sshconfig.Parse("hostname localhost\nremotecommand tmux attach"),
+	}
+	expected = fmt.Sprintf("%s%s", osCmdPrefix, `ssh -t -o RemoteCommand=none LOCALHOST_ALIAS "uptime"`)
+	require.Equal(t, expected, host.CmdSSHConnect())
+
+	host = Host{Address: "root@localhost -p 2222", RequestTTY: "force"}
+	expected = fmt.Sprintf("%s%s", osCmdPrefix, `ssh -tt root@localhost -p 2222`)
+	require.Equal(t, expected, host.CmdSSHConnect())
+}
+
+func TestIsReadOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		host     Host
+		expected bool
+	}{
+		{
+			name:     "YAML host",
+			host:     Host{StorageType: constant.HostStorageType.YAMLFile},
+			expected: false,
+		},
+		{
+			name:     "Host from local ssh_config",
+			host:     Host{StorageType: constant.HostStorageType.SSHConfig, SourcePath: "/home/user/.ssh/config"},
+			expected: false,
+		},
+		{
+			name:     "Host from remote ssh_config",
+			host:     Host{StorageType: constant.HostStorageType.SSHConfig, SourcePath: "https://example.com/config"},
+			expected: true,
+		},
+		{
+			name: "Host from additional ssh_config source",
+			host: Host{
+				StorageType:     constant.HostStorageType.SSHConfig,
+				SourcePath:      "/home/user/work/config",
+				SSHConfigSource: "/home/user/work/config",
+			},
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, tt.host.IsReadOnly())
+		})
+	}
+}
+
+func TestSourceLabel(t *testing.T) {
+	host := Host{StorageType: constant.HostStorageType.SSHConfig}
+	require.Empty(t, host.SourceLabel())
+
+	host.SSHConfigSource = "https://example.com/devops/ssh_config"
+	require.Equal(t, "example.com", host.SourceLabel())
+
+	host.SSHConfigSource = filepath.Join("home", "user", "work_config")
+	require.Equal(t, "work_config", host.SourceLabel())
+}
@@ -29,3 +29,35 @@ func TestCmdSSHCopyID(t *testing.T) {
 	expected := `cmd /c type "C:\Users\username\.ssh\test.pub" | ssh root@localhost -p 2222 "cat >> ~/.ssh/authorized_keys && chmod 600 ~/.ssh/authorized_keys && echo Key added. Now try logging into the machine."`
 	require.Equal(t, expected, actual)
 }
+
+func TestCmdSSHCopyID_ProxyJump(t *testing.T) {
+	t.Setenv("USERPROFILE", `C:\Users\username`)
+	host := Host{
+		JumpChain: "admin@bastion:2222",
+		SSHHostConfig: &sshconfig.Config{
+			Hostname:     "localhost",
+			IdentityFile: "~/.ssh/test",
+			User:         "root",
+		},
+	}
+
+	actual := host.CmdSSHCopyID()
+	expected := `cmd /c type "C:\Users\username\.ssh\test.pub" | ssh root@localhost -J admin@bastion:2222 "cat >> ~/.ssh/authorized_keys && chmod 600 ~/.ssh/authorized_keys && echo Key added. Now try logging into the machine."`
+	require.Equal(t, expected, actual)
+}
+
+func TestCmdSSHCopyID_ExtraOptions(t *testing.T) {
+	t.Setenv("USERPROFILE", `C:\Users\username`)
+	host := Host{
+		SSHOptions: []string{"-A", "StrictHostKeyChecking=accept-new"},
+		SSHHostConfig: &sshconfig.Config{
+			Hostname:     "localhost",
+			IdentityFile: "~/.ssh/test",
+			User:         "root",
+		},
+	}
+
+	actual := host.CmdSSHCopyID()
+	expected := `cmd /c type "C:\Users\username\.ssh\test.pub" | ssh root@localhost -A -o StrictHostKeyChecking=accept-new "cat >> ~/.ssh/authorized_keys && chmod 600 ~/.ssh/authorized_keys && echo Key added. Now try logging into the machine."`
+	require.Equal(t, expected, actual)
+}
@@ -0,0 +1,35 @@
+package host
+
+import (
+	"crypto/rand"
+	"crypto/sha1" //nolint:gosec // SHA1 is used to derive a stable identifier, not for security purposes.
+	"fmt"
+	"strings"
+)
+
+// NewID - generates a new random host identifier (UUID version 4).
+func NewID() string {
+	var uuid [16]byte
+	_, _ = rand.Read(uuid[:])
+	uuid[6] = (uuid[6] & 0x0f) | 0x40 //nolint:mnd // UUID version 4
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 //nolint:mnd // RFC 4122 variant
+
+	return formatUUID(uuid)
+}
+
+// DeriveID - returns a host identifier (UUID version 5 layout) which is always the same for
+// the same input. It is used for hosts which do not have an identifier stored alongside with
+// them, for instance, when a host is loaded from a remote ssh_config file.
+func DeriveID(parts ...string) string {
+	hash := sha1.Sum([]byte(strings.Join(parts, "\x00"))) //nolint:gosec // See import comment.
+	var uuid [16]byte
+	copy(uuid[:], hash[:16])
+	uuid[6] = (uuid[6] & 0x0f) | 0x50 //nolint:mnd // UUID version 5
+	uuid[8] = (uuid[8] & 0x3f) | 0x80 //nolint:mnd // RFC 4122 variant
+
+	return formatUUID(uuid)
+}
+
+func formatUUID(uuid [16]byte) string {
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
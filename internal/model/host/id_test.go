@@ -0,0 +1,29 @@
+package host
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[45][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewID(t *testing.T) {
+	id1 := NewID()
+	id2 := NewID()
+
+	require.Regexp(t, uuidRegex, id1)
+	require.Regexp(t, uuidRegex, id2)
+	require.NotEqual(t, id1, id2)
+}
+
+func TestDeriveID(t *testing.T) {
+	id := DeriveID("/home/user/.ssh/config", "alpha")
+
+	require.Regexp(t, uuidRegex, id)
+	require.Equal(t, id, DeriveID("/home/user/.ssh/config", "alpha"))
+	require.NotEqual(t, id, DeriveID("/home/user/.ssh/config", "beta"))
+	// Make sure that parts are separated and do not produce the same input when concatenated.
+	require.NotEqual(t, DeriveID("ab", "c"), DeriveID("a", "bc"))
+}
@@ -0,0 +1,107 @@
+package host
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"github.com/grafviktor/goto/internal/constant"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+var (
+	// ErrJumpHostNotFound - is returned when a jump host is deleted, but the host still refers to it.
+	ErrJumpHostNotFound = errors.New("jump host not found")
+	// ErrJumpHostCycle - is returned when jump hosts refer to each other or to the host itself.
+	ErrJumpHostCycle = errors.New("jump hosts refer to each other")
+	// ErrJumpHostInvalid - is returned when a jump host cannot be passed to ssh '-J' option.
+	ErrJumpHostInvalid = errors.New("host cannot be used as a jump host")
+)
+
+// ResolveJumpChain - builds the value of ssh '-J' option from jump hosts and stores it in JumpChain.
+// lookup returns a host by its ID. When a jump host has its own jump hosts, they're inserted before it,
+// because ssh must reach them first. Jump hosts from ssh_config are referenced by alias, so that ssh
+// reads their settings from the file. Other hosts are referenced as [user@]host[:port], note that their
+// identity files are not passed to ssh. Hosts from ssh_config and custom ssh commands do not have
+// jump hosts, JumpChain is always empty for them.
+func (h *Host) ResolveJumpChain(lookup func(hostID string) (Host, error)) error {
+	h.JumpChain = ""
+	if !h.HasJumpHosts() {
+		return nil
+	}
+
+	hops, err := jumpHops(*h, lookup, []string{h.ID})
+	if err != nil {
+		return err
+	}
+
+	h.JumpChain = strings.Join(hops, ",")
+	return nil
+}
+
+// HasJumpHosts - returns true if the host is connected through jump hosts, which are selected in the app.
+func (h *Host) HasJumpHosts() bool {
+	return len(h.ProxyJump) > 0 &&
+		h.StorageType != constant.HostStorageType.SSHConfig &&
+		!h.IsUserDefinedSSHCommand()
+}
+
+// jumpHops - returns jump hosts in the order ssh connects to them. path contains IDs of the hosts, which
+// are being resolved, it's used to detect cycles.
+func jumpHops(h Host, lookup func(hostID string) (Host, error), path []string) ([]string, error) {
+	hops := []string{}
+	for _, jumpHostID := range h.ProxyJump {
+		jumpHost, err := lookup(jumpHostID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q refers to host with id %s", ErrJumpHostNotFound, h.Title, jumpHostID)
+		}
+
+		if slices.Contains(path, jumpHostID) {
+			return nil, fmt.Errorf("%w: %q refers to %q", ErrJumpHostCycle, h.Title, jumpHost.Title)
+		}
+
+		if jumpHost.HasJumpHosts() {
+			nestedHops, err := jumpHops(jumpHost, lookup, append(slices.Clone(path), jumpHostID))
+			if err != nil {
+				return nil, err
+			}
+
+			hops = append(hops, nestedHops...)
+		}
+
+		hop, err := jumpHost.jumpSpec()
+		if err != nil {
+			return nil, err
+		}
+
+		hops = append(hops, hop)
+	}
+
+	return hops, nil
+}
+
+// jumpSpec - returns the host in the format, which ssh '-J' option expects.
+func (h *Host) jumpSpec() (string, error) {
+	if h.StorageType == constant.HostStorageType.SSHConfig {
+		return h.Title, nil
+	}
+
+	if h.IsUserDefinedSSHCommand() {
+		return "", fmt.Errorf("%w: %q is defined by ssh command", ErrJumpHostInvalid, h.Title)
+	}
+
+	spec := utils.UnbracketAddress(strings.TrimSpace(h.Address))
+	if !utils.StringEmpty(&h.RemotePort) {
+		spec = net.JoinHostPort(spec, strings.TrimSpace(h.RemotePort))
+	} else {
+		spec = utils.BracketAddress(spec)
+	}
+
+	if !utils.StringEmpty(&h.LoginName) {
+		spec = strings.TrimSpace(h.LoginName) + "@" + spec
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,102 @@
+package host
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/constant"
+)
+
+func hostLookup(hosts ...Host) func(string) (Host, error) {
+	return func(hostID string) (Host, error) {
+		for _, h := range hosts {
+			if h.ID == hostID {
+				return h, nil
+			}
+		}
+
+		return Host{}, constant.ErrNotFound
+	}
+}
+
+func TestResolveJumpChain(t *testing.T) {
+	bastion := Host{ID: "1", Title: "bastion", Address: "bastion.com", LoginName: "admin", RemotePort: "2222"}
+	gateway := Host{ID: "2", Title: "gateway", StorageType: constant.HostStorageType.SSHConfig}
+	ipv6 := Host{ID: "3", Title: "ipv6", Address: "2001:db8::10"}
+	inner := Host{ID: "4", Title: "inner", Address: "10.0.0.1", ProxyJump: []string{"1"}}
+	lookup := hostLookup(bastion, gateway, ipv6, inner)
+
+	tests := []struct {
+		name     string
+		host     Host
+		expected string
+	}{
+		{
+			name:     "No jump hosts",
+			host:     Host{Address: "localhost"},
+			expected: "",
+		},
+		{
+			name:     "Several jump hosts, ssh_config host is referenced by alias",
+			host:     Host{Address: "localhost", ProxyJump: []string{"1", "2"}},
+			expected: "admin@bastion.com:2222,gateway",
+		},
+		{
+			name:     "IPv6 jump host",
+			host:     Host{Address: "localhost", ProxyJump: []string{"3"}},
+			expected: "[2001:db8::10]",
+		},
+		{
+			name:     "Jump hosts of a jump host are inserted before it",
+			host:     Host{Address: "localhost", ProxyJump: []string{"4"}},
+			expected: "admin@bastion.com:2222,10.0.0.1",
+		},
+		{
+			name:     "Custom ssh command ignores jump hosts",
+			host:     Host{Address: "localhost -p 2222", ProxyJump: []string{"1"}},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, tt.host.ResolveJumpChain(lookup))
+			require.Equal(t, tt.expected, tt.host.JumpChain)
+		})
+	}
+}
+
+func TestResolveJumpChain_Errors(t *testing.T) {
+	a := Host{ID: "1", Title: "a", Address: "a.com", ProxyJump: []string{"2"}}
+	b := Host{ID: "2", Title: "b", Address: "b.com", ProxyJump: []string{"1"}}
+	custom := Host{ID: "3", Title: "custom", Address: "user@custom.com -p 2222"}
+	self := Host{ID: "4", Title: "self", Address: "localhost", ProxyJump: []string{"4"}}
+	lookup := hostLookup(a, b, custom, self)
+
+	tests := []struct {
+		host     Host
+		expected error
+	}{
+		{host: Host{Title: "deleted", Address: "localhost", ProxyJump: []string{"deleted"}}, expected: ErrJumpHostNotFound},
+		{host: a, expected: ErrJumpHostCycle},
+		{host: self, expected: ErrJumpHostCycle},
+		{host: Host{Title: "custom", Address: "localhost", ProxyJump: []string{"3"}}, expected: ErrJumpHostInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %v", tt.host.Title, tt.expected), func(t *testing.T) {
+			tt.host.JumpChain = "stale"
+			err := tt.host.ResolveJumpChain(lookup)
+			require.ErrorIs(t, err, tt.expected)
+			require.Empty(t, tt.host.JumpChain)
+		})
+	}
+}
+
+func TestCmdSSHConnect_ProxyJump(t *testing.T) {
+	host := Host{Address: "localhost", LoginName: "root", JumpChain: "admin@bastion.com:2222,gateway"}
+	require.Equal(t, osCmdPrefix+"ssh -l root -J admin@bastion.com:2222,gateway localhost", host.CmdSSHConnect())
+	require.Equal(t, osCmdPrefix+"ssh -l root -J admin@bastion.com:2222,gateway -G localhost", host.CmdSSHConfig())
+}
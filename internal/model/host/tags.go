@@ -0,0 +1,47 @@
+package host
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+)
+
+// HasTag - returns true if the host has the tag. Tags are case-insensitive.
+func (h *Host) HasTag(tag string) bool {
+	return slices.ContainsFunc(h.Tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
+
+// MatchTags - returns true if the host has all of the tags when matchAll is true, or at least one of them
+// otherwise. Any host matches an empty list of tags.
+func (h *Host) MatchTags(tags []string, matchAll bool) bool {
+	if len(tags) == 0 {
+		return true
+	}
+
+	if matchAll {
+		return lo.EveryBy(tags, h.HasTag)
+	}
+
+	return lo.SomeBy(tags, h.HasTag)
+}
+
+// AllTags - returns unique tags of the hosts sorted alphabetically. When the same tag is written in
+// different case, the first spelling is returned.
+func AllTags(hosts []Host) []string {
+	tags := []string{}
+	for _, h := range hosts {
+		for _, tag := range h.Tags {
+			if !slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	slices.SortFunc(tags, func(a, b string) int {
+		return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	return tags
+}
@@ -0,0 +1,44 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchTags(t *testing.T) {
+	h := Host{Tags: []string{"prod", "DB", "eu-west"}}
+
+	require.True(t, h.HasTag("db"))
+	require.False(t, h.HasTag("web"))
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAll bool
+		expected bool
+	}{
+		{"No tags", nil, true, true},
+		{"All tags match", []string{"prod", "db"}, true, true},
+		{"Not all tags match", []string{"prod", "web"}, true, false},
+		{"Any tag matches", []string{"prod", "web"}, false, true},
+		{"No tag matches", []string{"staging", "web"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expected, h.MatchTags(tt.tags, tt.matchAll))
+		})
+	}
+}
+
+func TestAllTags(t *testing.T) {
+	hosts := []Host{
+		{Tags: []string{"prod", "web"}},
+		{Tags: []string{"Prod", "db"}},
+		{},
+	}
+
+	require.Equal(t, []string{"db", "prod", "web"}, AllTags(hosts))
+	require.Empty(t, AllTags(nil))
+}
@@ -13,13 +13,28 @@ func Build(options ...Option) string {
 	sb := strings.Builder{}
 	sb.WriteString(baseCmd)
 
+	hasConfigFile := false
+	var remoteCommand Option
 	for _, option := range options {
+		if _, isRemoteCommand := option.(OptionRemoteCommand); isRemoteCommand {
+			remoteCommand = option
+			continue
+		}
+
+		_, isConfigFile := option.(OptionConfigFilePath)
+		hasConfigFile = hasConfigFile || isConfigFile
 		addOption(&sb, option)
 	}
 
-	if sshconfig.IsEnabled() && sshconfig.IsUserDefinedPath() {
+	// Host specific ssh_config file takes precedence.
+	if !hasConfigFile && sshconfig.IsEnabled() && sshconfig.IsUserDefinedPath() {
 		addOption(&sb, OptionConfigFilePath{Value: sshconfig.Path()})
 	}
 
+	// ssh treats all arguments, which follow the command, as a part of the command.
+	if remoteCommand != nil {
+		addOption(&sb, remoteCommand)
+	}
+
 	return sb.String()
 }
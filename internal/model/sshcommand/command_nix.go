@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/grafviktor/goto/internal/utils"
 )
 
 // BaseCMD return OS specific 'ssh' command.
@@ -24,7 +26,8 @@ func CopyIDCommand(options ...Option) string {
 	for _, option := range options {
 		switch opt := option.(type) {
 		case OptionAddress:
-			hostname = opt.Value
+			// ssh-copy-id expects IPv6 address in square brackets: "user@[2001:db8::10]".
+			hostname = utils.BracketAddress(opt.Value)
 		case OptionLoginName:
 			username = fmt.Sprintf("%s@", opt.Value)
 		case OptionPrivateKey:
@@ -33,6 +36,16 @@ func CopyIDCommand(options ...Option) string {
 				opt.Value = strings.Replace(opt.Value, "~", os.Getenv("HOME"), 1)
 			}
 			addOption(&sb, opt)
+		case OptionProxyJump:
+			// ssh-copy-id does not support '-J' flag, but passes ssh options to ssh.
+			if !utils.StringEmpty(&opt.Value) {
+				sb.WriteString(" -o ProxyJump=" + strings.TrimSpace(opt.Value))
+			}
+		case OptionFlag:
+			// ssh-copy-id does not pass flags to ssh, only options with '-o' flag.
+			for _, value := range FlagOptions(opt.Value) {
+				addOption(&sb, OptionSSHOption{Value: value})
+			}
 		default:
 			addOption(&sb, opt)
 		}
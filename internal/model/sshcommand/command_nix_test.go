@@ -31,3 +31,10 @@ func TestCopyIDCommand(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+func TestCopyIDCommand_IPv6(t *testing.T) {
+	// ssh-copy-id expects IPv6 address in square brackets.
+	expected := "ssh-copy-id username@[2001:db8::10]"
+	require.Equal(t, expected, CopyIDCommand(OptionAddress{Value: "2001:db8::10"}, OptionLoginName{Value: "username"}))
+	require.Equal(t, expected, CopyIDCommand(OptionAddress{Value: "[2001:db8::10]"}, OptionLoginName{Value: "username"}))
+}
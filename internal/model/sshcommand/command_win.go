@@ -23,11 +23,14 @@ func CopyIDCommand(options ...Option) string {
 	var username string
 	var remotePort string
 	var privateKey string
+	var proxyJump string
+	extraOptions := strings.Builder{}
 
 	for _, option := range options {
 		switch opt := option.(type) {
 		case OptionAddress:
-			hostname = opt.Value
+			// The key is copied by ssh, which expects IPv6 address without square brackets.
+			hostname = utils.UnbracketAddress(opt.Value)
 		case OptionLoginName:
 			username = fmt.Sprintf("%s@", opt.Value)
 		case OptionRemotePort:
@@ -39,15 +42,21 @@ func CopyIDCommand(options ...Option) string {
 				opt.Value = strings.Replace(opt.Value, "/", "\\", -1)
 			}
 			privateKey = opt.Value
+		case OptionProxyJump:
+			proxyJump = constructKeyValueOption("-J", opt.Value)
+		case OptionSSHOption, OptionFlag:
+			addOption(&extraOptions, opt)
 		}
 	}
 
 	installKeyCommand := `"cat >> ~/.ssh/authorized_keys && chmod 600 ~/.ssh/authorized_keys && echo Key added. Now try logging into the machine."`
-	return fmt.Sprintf(`cmd /c type "%s.pub" | ssh %s%s%s %s`,
+	return fmt.Sprintf(`cmd /c type "%s.pub" | ssh %s%s%s%s%s %s`,
 		privateKey,
 		username,
 		hostname,
 		remotePort,
+		proxyJump,
+		extraOptions.String(),
 		installKeyCommand,
 	)
 }
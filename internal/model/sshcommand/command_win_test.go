@@ -32,3 +32,17 @@ func TestCopyIDCommand(t *testing.T) {
 
 	require.Equal(t, expected, actual)
 }
+
+func TestCopyIDCommand_IPv6(t *testing.T) {
+	os.Setenv("USERPROFILE", `c:\Users\username`)
+
+	// The key is copied by ssh, which expects IPv6 address without brackets.
+	expected := `cmd /c type "c:\Users\username\.ssh\id_rsa.pub" | ssh username@2001:db8::10 "cat >> ~/.ssh/authorized_keys && chmod 600 ~/.ssh/authorized_keys && echo Key added. Now try logging into the machine."`
+	actual := CopyIDCommand(
+		OptionAddress{Value: "[2001:db8::10]"},
+		OptionLoginName{Value: "username"},
+		OptionPrivateKey{Value: "~/.ssh/id_rsa"},
+	)
+
+	require.Equal(t, expected, actual)
+}
@@ -0,0 +1,123 @@
+package sshcommand
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// extraFlags - ssh flags without arguments, which can be added to a host. Flags with arguments are set
+// with ssh_config options instead, for instance "-p 22" is "Port=22".
+const extraFlags = "46AaCgKkqTtvXxY"
+
+// knownOptions - ssh_config keywords in lower case, which can be passed to ssh with '-o' flag, see ssh_config(5).
+// Options, which values almost always contain spaces, such as ProxyCommand, LocalCommand, KnownHostsCommand and
+// SetEnv, are not listed, as extra options cannot contain spaces.
+var knownOptions = []string{
+	"addkeystoagent", "addressfamily", "batchmode", "bindaddress", "bindinterface",
+	"canonicaldomains", "canonicalizefallbacklocal", "canonicalizehostname", "canonicalizemaxdots",
+	"canonicalizepermittedcnames", "casignaturealgorithms", "certificatefile", "channeltimeout",
+	"checkhostip", "ciphers", "clearallforwardings", "compression", "connectionattempts",
+	"connecttimeout", "controlmaster", "controlpath", "controlpersist", "dynamicforward",
+	"enableescapecommandline", "enablesshkeysign", "escapechar", "exitonforwardfailure",
+	"fingerprinthash", "forkafterauthentication", "forwardagent", "forwardx11", "forwardx11timeout",
+	"forwardx11trusted", "gatewayports", "globalknownhostsfile", "gssapiauthentication",
+	"gssapidelegatecredentials", "hashknownhosts", "hostbasedacceptedalgorithms",
+	"hostbasedauthentication", "hostkeyalgorithms", "hostkeyalias", "hostname", "identitiesonly",
+	"identityagent", "identityfile", "ignoreunknown", "include", "ipqos", "kbdinteractiveauthentication",
+	"kbdinteractivedevices", "kexalgorithms", "localforward", "loglevel", "logverbose", "macs",
+	"nohostauthenticationforlocalhost", "numberofpasswordprompts", "obscurekeystroketiming",
+	"passwordauthentication", "permitlocalcommand", "permitremoteopen", "pkcs11provider", "port",
+	"preferredauthentications", "proxyjump", "proxyusefdpass", "pubkeyacceptedalgorithms",
+	"pubkeyauthentication", "rekeylimit", "remotecommand", "remoteforward", "requesttty", "requiredrsasize",
+	"revokedhostkeys", "securitykeyprovider", "sendenv", "serveralivecountmax", "serveraliveinterval",
+	"sessiontype", "stdinnull", "streamlocalbindmask", "streamlocalbindunlink", "stricthostkeychecking",
+	"syslogfacility", "tag", "tcpkeepalive", "tunnel", "tunneldevice", "updatehostkeys", "user",
+	"userknownhostsfile", "verifyhostkeydns", "visualhostkey", "xauthlocation",
+}
+
+// flagOptions - ssh_config options, which are set by ssh flags, see ssh(1). '-v' is handled separately,
+// as it can be repeated.
+var flagOptions = map[rune][]string{
+	'4': {"AddressFamily=inet"},
+	'6': {"AddressFamily=inet6"},
+	'A': {"ForwardAgent=yes"},
+	'a': {"ForwardAgent=no"},
+	'C': {"Compression=yes"},
+	'g': {"GatewayPorts=yes"},
+	'K': {"GSSAPIAuthentication=yes", "GSSAPIDelegateCredentials=yes"},
+	'k': {"GSSAPIDelegateCredentials=no"},
+	'q': {"LogLevel=QUIET"},
+	'T': {"RequestTTY=no"},
+	't': {"RequestTTY=yes"},
+	'X': {"ForwardX11=yes"},
+	'x': {"ForwardX11=no"},
+	'Y': {"ForwardX11=yes", "ForwardX11Trusted=yes"},
+}
+
+// maxVerbosity - ssh ignores more than 3 '-v' flags.
+const maxVerbosity = 3
+
+// FlagOptions - converts ssh flags, for instance "-AC", to ssh_config options, which are passed with '-o'
+// flag. It is used for tools, which pass options to ssh, but do not accept ssh flags, like ssh-copy-id.
+func FlagOptions(value string) []string {
+	options := []string{}
+	verbosity := 0
+	for _, flag := range strings.TrimPrefix(value, "-") {
+		if flag == 'v' {
+			verbosity++
+			continue
+		}
+
+		options = append(options, flagOptions[flag]...)
+	}
+
+	if verbosity > 0 {
+		options = append(options, fmt.Sprintf("LogLevel=DEBUG%d", min(verbosity, maxVerbosity)))
+	}
+
+	return options
+}
+
+// ExtraOption - converts an option, which user adds to a host, to a command line option. Flags, for instance
+// "-A", are passed as is, other options are written as "Keyword=value" and passed with '-o' flag.
+func ExtraOption(value string) Option {
+	if strings.HasPrefix(value, "-") {
+		return OptionFlag{Value: value}
+	}
+
+	return OptionSSHOption{Value: value}
+}
+
+// ValidateExtraOption - returns an error if ssh does not support the option, see ExtraOption.
+func ValidateExtraOption(value string) error {
+	if value == "" || strings.ContainsAny(value, " \t\"'") {
+		return errors.New("ssh option must not be empty or contain spaces and quotes")
+	}
+
+	if flags, isFlag := strings.CutPrefix(value, "-"); isFlag {
+		if flags == "" {
+			return errors.New("ssh flag is empty")
+		}
+
+		for _, flag := range flags {
+			if !strings.ContainsRune(extraFlags, flag) {
+				return fmt.Errorf("ssh flag %q is not supported, use one of -%s", value, extraFlags)
+			}
+		}
+
+		return nil
+	}
+
+	keyword, optionValue, found := strings.Cut(value, "=")
+	if !found || optionValue == "" {
+		return fmt.Errorf("ssh option %q must be written as Keyword=value", value)
+	}
+
+	if !slices.Contains(knownOptions, strings.ToLower(keyword)) {
+		return fmt.Errorf("ssh option %q is unknown", keyword)
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package sshcommand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtraOption(t *testing.T) {
+	require.Equal(t, OptionFlag{Value: "-A"}, ExtraOption("-A"))
+	require.Equal(t, OptionSSHOption{Value: "ServerAliveInterval=30"}, ExtraOption("ServerAliveInterval=30"))
+}
+
+func TestValidateExtraOption(t *testing.T) {
+	tests := []struct {
+		value    string
+		errorMsg string
+	}{
+		{value: "-A"},
+		{value: "-AC"},
+		{value: "ServerAliveInterval=30"},
+		{value: "stricthostkeychecking=accept-new"},
+		{value: "Ciphers=aes128-ctr,aes256-ctr"},
+		{value: "", errorMsg: "must not be empty"},
+		{value: "ServerAliveInterval 30", errorMsg: "must not be empty or contain spaces"},
+		{value: "-", errorMsg: "ssh flag is empty"},
+		{value: "-p", errorMsg: `ssh flag "-p" is not supported`},
+		{value: "ServerAliveInterval", errorMsg: "must be written as Keyword=value"},
+		{value: "ServerAliveInterval=", errorMsg: "must be written as Keyword=value"},
+		{value: "Host=localhost", errorMsg: `ssh option "Host" is unknown`},
+		// Values of these options contain spaces, therefore they cannot be used.
+		{value: "ProxyCommand=nc", errorMsg: `ssh option "ProxyCommand" is unknown`},
+		{value: "SetEnv=TERM=xterm", errorMsg: `ssh option "SetEnv" is unknown`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			err := ValidateExtraOption(tt.value)
+			if tt.errorMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestFlagOptions(t *testing.T) {
+	require.Equal(t, []string{"ForwardAgent=yes"}, FlagOptions("-A"))
+	require.Equal(t, []string{"Compression=yes", "ForwardX11=yes", "ForwardX11Trusted=yes"}, FlagOptions("-CY"))
+	require.Equal(t, []string{"RequestTTY=yes", "LogLevel=DEBUG2"}, FlagOptions("-vtv"))
+	require.Equal(t, []string{"LogLevel=DEBUG3"}, FlagOptions("-vvvv"))
+	require.Empty(t, FlagOptions("-"))
+
+	// Every supported flag can be converted.
+	for _, flag := range extraFlags {
+		require.NotEmpty(t, FlagOptions("-"+string(flag)), "flag -%c", flag)
+	}
+}
@@ -23,8 +23,33 @@ type (
 	OptionReadHostConfig struct{ Value string }
 	// OptionConfigFilePath - is a path to ssh_config file.
 	OptionConfigFilePath struct{ Value string }
+	// OptionProxyJump - is a comma separated list of jump hosts. Example: admin@bastion:2222,gateway.
+	OptionProxyJump struct{ Value string }
+	// OptionLocalForward - forwards local port to a remote destination. Example: 5432:db.internal:5432.
+	OptionLocalForward struct{ Value string }
+	// OptionRemoteForward - forwards remote port to a local destination. Example: 8080:localhost:80.
+	OptionRemoteForward struct{ Value string }
+	// OptionDynamicForward - starts SOCKS proxy on a local port. Example: 1080.
+	OptionDynamicForward struct{ Value string }
+	// OptionNoRemoteCommand - does not execute a remote command, it is used when only port forwarding is required.
+	OptionNoRemoteCommand struct{}
+	// OptionSSHOption - is an option in ssh_config format, which is passed with '-o' flag. Example: BatchMode=yes.
+	OptionSSHOption struct{ Value string }
+	// OptionFlag - is a flag without arguments. Example: -A.
+	OptionFlag struct{ Value string }
+	// OptionRequestTTY - is ssh RequestTTY setting: "yes", "force", "no" or "auto". Example: yes => -t.
+	OptionRequestTTY struct{ Value string }
+	// OptionRemoteCommand - is a command, which is executed on a remote host. Example: tmux new -A -s main.
+	OptionRemoteCommand struct{ Value string }
 )
 
+// requestTTYFlags - ssh flags for RequestTTY settings, "auto" is ssh default, it does not require a flag.
+var requestTTYFlags = map[string]string{
+	"yes":   " -t",
+	"force": " -tt",
+	"no":    " -T",
+}
+
 func constructKeyValueOption(optionFlag, optionValue string) string {
 	optionValue = strings.TrimSpace(optionValue)
 	if optionValue != "" {
@@ -44,11 +69,35 @@ func addOption(sb *strings.Builder, rawParameter Option) {
 		option = constructKeyValueOption("-l", p.Value)
 	case OptionConfigFilePath:
 		option = constructKeyValueOption("-F", fmt.Sprintf("%q", p.Value))
+	case OptionProxyJump:
+		option = constructKeyValueOption("-J", p.Value)
+	case OptionLocalForward:
+		option = constructKeyValueOption("-L", p.Value)
+	case OptionRemoteForward:
+		option = constructKeyValueOption("-R", p.Value)
+	case OptionDynamicForward:
+		option = constructKeyValueOption("-D", p.Value)
+	case OptionNoRemoteCommand:
+		option = " -N"
+	case OptionSSHOption:
+		option = constructKeyValueOption("-o", p.Value)
+	case OptionFlag:
+		if p.Value != "" {
+			option = " " + p.Value
+		}
+	case OptionRequestTTY:
+		option = requestTTYFlags[strings.ToLower(strings.TrimSpace(p.Value))]
+	case OptionRemoteCommand:
+		// The command is passed as a single argument, the remote shell splits it.
+		if value := strings.TrimSpace(p.Value); value != "" {
+			option = fmt.Sprintf(` "%s"`, value)
+		}
 	case OptionReadHostConfig:
-		option = constructKeyValueOption("-G", utils.RemoveDuplicateSpaces(p.Value))
+		option = constructKeyValueOption("-G", utils.UnbracketAddress(utils.RemoveDuplicateSpaces(p.Value)))
 	case OptionAddress:
+		// ssh expects IPv6 address without square brackets.
 		if p.Value != "" {
-			option = fmt.Sprintf(" %s", utils.RemoveDuplicateSpaces(p.Value))
+			option = fmt.Sprintf(" %s", utils.UnbracketAddress(utils.RemoveDuplicateSpaces(p.Value)))
 		}
 	default:
 		return
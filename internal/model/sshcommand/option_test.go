@@ -76,6 +76,71 @@ func Test_AddOption(t *testing.T) {
 			rawParameter:   OptionAddress{Value: ""},
 			expectedResult: "",
 		},
+		{
+			name:           "OptionLocalForward with value",
+			rawParameter:   OptionLocalForward{Value: "5432:db.internal:5432"},
+			expectedResult: " -L 5432:db.internal:5432",
+		},
+		{
+			name:           "OptionRemoteForward with value",
+			rawParameter:   OptionRemoteForward{Value: "8080:localhost:80"},
+			expectedResult: " -R 8080:localhost:80",
+		},
+		{
+			name:           "OptionDynamicForward with value",
+			rawParameter:   OptionDynamicForward{Value: "1080"},
+			expectedResult: " -D 1080",
+		},
+		{
+			name:           "OptionNoRemoteCommand",
+			rawParameter:   OptionNoRemoteCommand{},
+			expectedResult: " -N",
+		},
+		{
+			name:           "OptionSSHOption with value",
+			rawParameter:   OptionSSHOption{Value: "BatchMode=yes"},
+			expectedResult: " -o BatchMode=yes",
+		},
+		{
+			name:           "OptionFlag with value",
+			rawParameter:   OptionFlag{Value: "-A"},
+			expectedResult: " -A",
+		},
+		{
+			name:           "OptionFlag with empty value",
+			rawParameter:   OptionFlag{Value: ""},
+			expectedResult: "",
+		},
+		{
+			name:           "OptionRequestTTY yes",
+			rawParameter:   OptionRequestTTY{Value: "yes"},
+			expectedResult: " -t",
+		},
+		{
+			name:           "OptionRequestTTY force",
+			rawParameter:   OptionRequestTTY{Value: "force"},
+			expectedResult: " -tt",
+		},
+		{
+			name:           "OptionRequestTTY no",
+			rawParameter:   OptionRequestTTY{Value: "no"},
+			expectedResult: " -T",
+		},
+		{
+			name:           "OptionRequestTTY auto",
+			rawParameter:   OptionRequestTTY{Value: "auto"},
+			expectedResult: "",
+		},
+		{
+			name:           "OptionRemoteCommand with value",
+			rawParameter:   OptionRemoteCommand{Value: " tmux new -A -s main "},
+			expectedResult: ` "tmux new -A -s main"`,
+		},
+		{
+			name:           "OptionRemoteCommand with empty value",
+			rawParameter:   OptionRemoteCommand{Value: ""},
+			expectedResult: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -130,6 +195,10 @@ func Test_Build_ConnectCommand(t *testing.T) {
 		&mocklogger.Logger{})
 	actual := Build(OptionAddress{Value: "example.com"})
 	require.Contains(t, actual, `ssh example.com -F "~/.ssh/custom_config"`)
+
+	// Remote command is always the last argument.
+	actual = Build(OptionRequestTTY{Value: "yes"}, OptionAddress{Value: "example.com"}, OptionRemoteCommand{Value: "uptime"})
+	require.Contains(t, actual, `ssh -t example.com -F "~/.ssh/custom_config" "uptime"`)
 }
 
 func Test_Build_LoadConfigCommand(t *testing.T) {
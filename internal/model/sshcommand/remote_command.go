@@ -0,0 +1,20 @@
+package sshcommand
+
+import (
+	"errors"
+	"strings"
+)
+
+// ValidateRemoteCommand - returns an error if the command cannot be passed to ssh, see OptionRemoteCommand.
+// The command is enclosed in quotes, therefore it must not contain quotes itself.
+func ValidateRemoteCommand(value string) error {
+	if strings.ContainsAny(value, "\"'") {
+		return errors.New("remote command must not contain quotes, put it into a script on the remote host")
+	}
+
+	if strings.ContainsAny(value, "\r\n") {
+		return errors.New("remote command must be a single line")
+	}
+
+	return nil
+}
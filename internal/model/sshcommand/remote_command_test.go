@@ -0,0 +1,16 @@
+package sshcommand
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateRemoteCommand(t *testing.T) {
+	require.NoError(t, ValidateRemoteCommand(""))
+	require.NoError(t, ValidateRemoteCommand("tmux new -A -s main"))
+	require.NoError(t, ValidateRemoteCommand("sudo -iu deploy"))
+	require.ErrorContains(t, ValidateRemoteCommand(`bash -c "uptime"`), "must not contain quotes")
+	require.ErrorContains(t, ValidateRemoteCommand("echo 'hi'"), "must not contain quotes")
+	require.ErrorContains(t, ValidateRemoteCommand("uptime\nreboot"), "single line")
+}
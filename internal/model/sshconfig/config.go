@@ -3,32 +3,97 @@ package sshconfig
 
 import (
 	"os/user"
-	"regexp"
+	"strings"
+	"sync"
 
 	"github.com/grafviktor/goto/internal/state"
 )
 
-// Config struct contains values loaded from ~/.ssh_config file.
+// Config contains effective host settings, which are printed by 'ssh -G <hostname>' command. All settings
+// are kept in Settings in the order they're printed. Settings, which are used by the app, are also copied to
+// the typed fields. Some settings, like 'identityfile' or 'sendenv', can have several values, ssh prints a
+// line for each of them. IdentityFile is the first identity file, which ssh tries.
 type Config struct {
-	// Values which should be extracted from 'ssh -G <hostname>' command:
-	// 1. 'hostname'
-	// 2. 'identityfile'
-	// 3. 'port'
-	// 4. 'user'
-	Hostname     string
-	IdentityFile string
-	Port         string
-	User         string
+	Hostname        string
+	IdentityFile    string
+	Port            string
+	User            string
+	IdentityFiles   []string
+	LocalForwards   []string
+	RemoteForwards  []string
+	DynamicForwards []string
+	SendEnv         []string
+	SetEnv          []string
+	Settings        []Setting
+}
+
+// Setting is a single 'ssh -G' setting, for instance "serveraliveinterval 30". Key is in lower case,
+// Values contain a value for each line, where the setting is printed.
+type Setting struct {
+	Key    string
+	Values []string
 }
 
 // Parse - parses 'ssh -G <hostname> command' output and returns Config struct.
 func Parse(config string) *Config {
-	return &Config{
-		Hostname:     getRegexFirstMatchingGroup(sshConfigHostnameRe.FindStringSubmatch(config)),
-		IdentityFile: getRegexFirstMatchingGroup(sshConfigIdentityFileRe.FindStringSubmatch(config)),
-		Port:         getRegexFirstMatchingGroup(sshConfigPortRe.FindStringSubmatch(config)),
-		User:         getRegexFirstMatchingGroup(sshConfigUserRe.FindStringSubmatch(config)),
+	c := &Config{}
+	index := make(map[string]int)
+	for _, line := range strings.Split(config, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, _ := strings.Cut(line, " ")
+		key = strings.ToLower(key)
+		value = strings.TrimSpace(value)
+		if i, found := index[key]; found {
+			c.Settings[i].Values = append(c.Settings[i].Values, value)
+			continue
+		}
+
+		index[key] = len(c.Settings)
+		c.Settings = append(c.Settings, Setting{Key: key, Values: []string{value}})
+	}
+
+	c.Hostname = c.Value("hostname")
+	c.IdentityFile = c.Value("identityfile")
+	c.Port = c.Value("port")
+	c.User = c.Value("user")
+	c.IdentityFiles = c.Values("identityfile")
+	c.LocalForwards = c.Values("localforward")
+	c.RemoteForwards = c.Values("remoteforward")
+	c.DynamicForwards = c.Values("dynamicforward")
+	c.SendEnv = c.Values("sendenv")
+	c.SetEnv = c.Values("setenv")
+
+	return c
+}
+
+// Value - returns the first value of the setting or an empty string if ssh does not print it.
+func (c *Config) Value(key string) string {
+	if values := c.Values(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Values - returns all values of the setting. The key is case-insensitive. Config can be nil,
+// when it's not loaded yet.
+func (c *Config) Values(key string) []string {
+	if c == nil {
+		return nil
 	}
+
+	key = strings.ToLower(key)
+	for _, setting := range c.Settings {
+		if setting.Key == key {
+			return setting.Values
+		}
+	}
+
+	return nil
 }
 
 // StubConfig - returns a stub SSH config. It is used on application startup when build application state and
@@ -54,21 +119,6 @@ func currentUsername() string {
 	return u.Username
 }
 
-var (
-	sshConfigHostnameRe     = regexp.MustCompile(`(?im)^hostname\s+(.*[^\r\n])`)
-	sshConfigIdentityFileRe = regexp.MustCompile(`(?im)^identityfile\s+(.*[^\r\n])`)
-	sshConfigPortRe         = regexp.MustCompile(`(?im)^port\s+(.*[^\r\n])`)
-	sshConfigUserRe         = regexp.MustCompile(`(?im)^user\s+(.*[^\r\n])`)
-)
-
-func getRegexFirstMatchingGroup(groups []string) string {
-	if len(groups) > 1 {
-		return groups[1]
-	}
-
-	return ""
-}
-
 /*
   SSHconfig paths below have nothing to do with model/config and
   should be moved out of here! This is a good victim for refactoring.
@@ -94,7 +144,12 @@ func IsEnabled() bool {
 	return state.Get().SSHConfigEnabled
 }
 
-var sshConfigPath *string
+var (
+	sshConfigPath *string
+	// sourcePaths maps additional ssh_config sources to the files which ssh should read.
+	sourcePaths   = make(map[string]string)
+	sourcePathsMu sync.RWMutex
+)
 
 // SetPath - set SSH config file path. This function does not validate or refine path.
 func SetPath(path string) {
@@ -110,3 +165,22 @@ func Path() string {
 	// Fallback to application state.
 	return state.Get().SSHConfigPath
 }
+
+// SetPathForSource - set ssh_config file path, which is used by hosts loaded from additional source.
+func SetPathForSource(source, path string) {
+	sourcePathsMu.Lock()
+	defer sourcePathsMu.Unlock()
+	sourcePaths[source] = path
+}
+
+// PathForSource - returns ssh_config file path for additional source. If the path is not set,
+// the source itself is returned.
+func PathForSource(source string) string {
+	sourcePathsMu.RLock()
+	defer sourcePathsMu.RUnlock()
+	if path, ok := sourcePaths[source]; ok {
+		return path
+	}
+
+	return source
+}
@@ -35,36 +35,54 @@ func TestParseConfig(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    string
-		expected *Config
+		expected Config
 	}{
 		{
 			name:  "Windows uses '\r\n' for lines ending.",
 			input: windowsMockSSHConfig,
-			expected: &Config{
+			expected: Config{
 				Hostname:     "mock_hostname",
 				IdentityFile: "c:/temp/mock_rsa_file",
 				User:         "mock_domain\\mock_user",
 				Port:         "22",
+				IdentityFiles: []string{
+					"c:/temp/mock_rsa_file",
+					"~/.ssh/id_dsa",
+					"~/.ssh/id_ecdsa",
+					"~/.ssh/id_ed25519",
+					"~/.ssh/id_xmss",
+				},
 			},
 		},
 		{
 			name:  "UNIX uses '\n' for lines ending.",
 			input: unixMockSSHConfig,
-			expected: &Config{
+			expected: Config{
 				Hostname:     "mock_hostname",
 				IdentityFile: "~/.ssh/mock_rsa_file",
 				User:         "mock_user",
 				Port:         "22",
+				IdentityFiles: []string{
+					"~/.ssh/mock_rsa_file",
+					"~/.ssh/id_dsa",
+					"~/.ssh/id_ecdsa",
+					"~/.ssh/id_ecdsa_sk",
+					"~/.ssh/id_ed25519",
+					"~/.ssh/id_ed25519_sk",
+					"~/.ssh/id_xmss",
+				},
+				SendEnv: []string{"LANG", "LC_*"},
 			},
 		},
 		{
 			name:  "Every config line ends with a beginning of next line title.",
 			input: unixMockSSHConfig2,
-			expected: &Config{
-				Hostname:     "prod-host1.localport",
-				IdentityFile: "~/.ssh/id_rsa",
-				User:         "prod-support.hostname",
-				Port:         "22",
+			expected: Config{
+				Hostname:      "prod-host1.localport",
+				IdentityFile:  "~/.ssh/id_rsa",
+				User:          "prod-support.hostname",
+				Port:          "22",
+				IdentityFiles: []string{"~/.ssh/id_rsa", "~/.ssh/id_ecdsa"},
 			},
 		},
 	}
@@ -72,11 +90,37 @@ func TestParseConfig(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			actual := Parse(tt.input)
-			require.Equal(t, tt.expected, actual)
+			// All settings are kept, they're checked separately.
+			require.NotEmpty(t, actual.Settings)
+			actual.Settings = nil
+			require.Equal(t, tt.expected, *actual)
 		})
 	}
 }
 
+func TestParseConfig_Settings(t *testing.T) {
+	config := Parse(unixMockSSHConfig)
+
+	// Settings are kept in the order they're printed, multi-valued settings are merged.
+	require.Equal(t, Setting{Key: "user", Values: []string{"mock_user"}}, config.Settings[0])
+	require.Equal(t, "30", config.Value("serveraliveinterval"))
+	require.Equal(t, "30", config.Value("ServerAliveInterval"))
+	require.Equal(t, []string{""}, config.Values("canonicaldomains"))
+	require.Equal(t, "/etc/ssh/ssh_known_hosts /etc/ssh/ssh_known_hosts2", config.Value("globalknownhostsfile"))
+	require.Empty(t, config.Value("proxyjump"))
+	require.Nil(t, config.Values("proxyjump"))
+
+	forwards := Parse("localforward 8080 localhost:80\nlocalforward [::1]:9090 db:5432\ndynamicforward 1080\n")
+	require.Equal(t, []string{"8080 localhost:80", "[::1]:9090 db:5432"}, forwards.LocalForwards)
+	require.Equal(t, []string{"1080"}, forwards.DynamicForwards)
+	require.Len(t, forwards.Settings, 2)
+
+	// Config is not loaded yet.
+	var notLoaded *Config
+	require.Empty(t, notLoaded.Value("user"))
+	require.Nil(t, notLoaded.Values("identityfile"))
+}
+
 func Test_Path(t *testing.T) {
 	_, err := state.Initialize(
 		context.TODO(),
@@ -91,3 +135,11 @@ func Test_Path(t *testing.T) {
 	SetPath("/custom/mock_config2")
 	require.Equal(t, "/custom/mock_config2", Path())
 }
+
+func Test_PathForSource(t *testing.T) {
+	// Source is used as is, unless it's replaced with a local copy.
+	require.Equal(t, "/tmp/team_config", PathForSource("/tmp/team_config"))
+
+	SetPathForSource("/tmp/team_config", "/tmp/team_config_copy")
+	require.Equal(t, "/tmp/team_config_copy", PathForSource("/tmp/team_config"))
+}
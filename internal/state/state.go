@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
@@ -33,6 +36,14 @@ const (
 	ViewEditItem
 	// ViewMessage mode is active when there was an error when attempted to connect to a remote host.
 	ViewMessage
+	// ViewPatternList mode is active when the app displays wildcard "Host" blocks from ssh_config.
+	ViewPatternList
+	// ViewHostDetails mode is active when the app displays effective host configuration, see 'ssh -G'.
+	ViewHostDetails
+	// ViewTunnelList mode is active when the app displays port forwardings of all hosts.
+	ViewTunnelList
+	// ViewTagList mode is active when the app displays host tags, which are used to filter the host list.
+	ViewTagList
 )
 
 // Once - this interface is used to avoid sync.Once restrictions in unit-tests.
@@ -60,22 +71,43 @@ type State struct {
 	// but persists it to disk using SetSSHConfigPath.
 	// This is done to distinguish between --set-ssh-config-path flag usage and
 	// and setting the path via command line -s or env variable.
-	AppHome                    string                `yaml:"-"`
-	AppMode                    constant.AppMode      `yaml:"-"`
-	Context                    context.Context       `yaml:"-"`
-	CurrentView                View                  `yaml:"-"`
-	Group                      string                `yaml:"group,omitempty"`
-	Height                     int                   `yaml:"-"`
-	IsUserDefinedSSHConfigPath bool                  `yaml:"-"`
-	Logger                     loggerInterface       `yaml:"-"`
-	LogLevel                   constant.LogLevel     `yaml:"-"`
-	ScreenLayout               constant.ScreenLayout `yaml:"screen_layout,omitempty"`
-	SetSSHConfigPath           string                `yaml:"ssh_config_path,omitempty"`
-	Selected                   int                   `yaml:"selected"`
-	SSHConfigEnabled           bool                  `yaml:"enable_ssh_config"`
-	SSHConfigPath              string                `yaml:"-"`
-	Theme                      string                `yaml:"theme,omitempty"`
-	Width                      int                   `yaml:"-"`
+	AppHome                    string            `yaml:"-"`
+	AppMode                    constant.AppMode  `yaml:"-"`
+	BackupCount                int               `yaml:"-"`
+	Context                    context.Context   `yaml:"-"`
+	CurrentView                View              `yaml:"-"`
+	DefaultInventory           string            `yaml:"-"`
+	Group                      string            `yaml:"group,omitempty"`
+	Height                     int               `yaml:"-"`
+	Inventories                []string          `yaml:"-"`
+	IsUserDefinedSSHConfigPath bool              `yaml:"-"`
+	KeyFile                    string            `yaml:"-"`
+	Logger                     loggerInterface   `yaml:"-"`
+	LogLevel                   constant.LogLevel `yaml:"-"`
+	// Passphrase is used to decrypt hosts file or a protected key file, it's requested when the app starts and never persisted.
+	Passphrase string `yaml:"-"`
+	// RemoteAccess contains credentials and TLS settings for remote ssh_config files. It's only edited by user.
+	RemoteAccess []utils.URLAccess `yaml:"remote_access,omitempty"`
+	// SavedSSHConfigSources are persisted to disk, see SSHConfigSources.
+	SavedSSHConfigSources []string              `yaml:"ssh_config_sources,omitempty"`
+	ScreenLayout          constant.ScreenLayout `yaml:"screen_layout,omitempty"`
+	SetSSHConfigPath      string                `yaml:"ssh_config_path,omitempty"`
+	Selected              string                `yaml:"selected"`
+	SSHConfigEnabled      bool                  `yaml:"enable_ssh_config"`
+	SSHConfigPath         string                `yaml:"-"`
+	// SSHConfigSources are additional ssh_config files and URLs. They're loaded from the state file,
+	// unless set via command line or env variable.
+	SSHConfigSources []string `yaml:"-"`
+	// TagFilter contains tags, which are selected in tag list view.
+	TagFilter []string `yaml:"tag_filter,omitempty"`
+	// TagFilterMatchAll is true when the host list displays hosts with all of the tags, otherwise hosts
+	// with at least one of them.
+	TagFilterMatchAll bool   `yaml:"tag_filter_match_all,omitempty"`
+	Theme             string `yaml:"theme,omitempty"`
+	Width             int    `yaml:"-"`
+	// persisted is the state which was read from or written to the file by the app.
+	// It's used to detect and merge changes made by other instances of the app.
+	persisted []byte
 }
 
 // Get - returns application state.
@@ -111,11 +143,15 @@ func Initialize(ctx context.Context,
 
 		// Set default values
 		st = &State{
-			AppMode:       cfg.AppMode,
-			AppHome:       cfg.AppHome,
-			Context:       ctx,
-			Logger:        lg,
-			SSHConfigPath: defaultSSHConfigPath,
+			AppMode:          cfg.AppMode,
+			AppHome:          cfg.AppHome,
+			BackupCount:      cfg.BackupCount,
+			Context:          ctx,
+			DefaultInventory: cfg.DefaultInventory,
+			Inventories:      filepath.SplitList(cfg.Inventory),
+			KeyFile:          cfg.KeyFile,
+			Logger:           lg,
+			SSHConfigPath:    defaultSSHConfigPath,
 		}
 
 		// Read state from file
@@ -131,13 +167,17 @@ func (s *State) readFromFile() {
 	// Why not unmarshal directly to State? Because we want to distinguish between null values
 	// and zero values especially for boolean parameters. Using pointers for that.
 	var loadedState struct {
-		Selected int    `yaml:"selected"`
-		Group    string `yaml:"group"`
+		Selected          string   `yaml:"selected"`
+		Group             string   `yaml:"group"`
+		TagFilter         []string `yaml:"tag_filter"`
+		TagFilterMatchAll bool     `yaml:"tag_filter_match_all"`
 		// Using pointers to distinguish between null and zero values.
-		Theme            *string `yaml:"theme"`
-		ScreenLayout     *string `yaml:"screen_layout"`
-		SSHConfigEnabled *bool   `yaml:"enable_ssh_config"`
-		SSHConfigPath    *string `yaml:"ssh_config_path"`
+		Theme            *string           `yaml:"theme"`
+		ScreenLayout     *string           `yaml:"screen_layout"`
+		SSHConfigEnabled *bool             `yaml:"enable_ssh_config"`
+		SSHConfigPath    *string           `yaml:"ssh_config_path"`
+		SSHConfigSources []string          `yaml:"ssh_config_sources"`
+		RemoteAccess     []utils.URLAccess `yaml:"remote_access"`
 	}
 
 	appStateFilePath := path.Join(s.AppHome, stateFile)
@@ -154,6 +194,8 @@ func (s *State) readFromFile() {
 	}
 
 	s.Group = loadedState.Group
+	s.TagFilter = loadedState.TagFilter
+	s.TagFilterMatchAll = loadedState.TagFilterMatchAll
 	s.Selected = loadedState.Selected
 
 	if loadedState.Theme == nil {
@@ -189,7 +231,17 @@ func (s *State) readFromFile() {
 		}
 	}
 
+	s.RemoteAccess = loadedState.RemoteAccess
+	s.SavedSSHConfigSources = loadedState.SSHConfigSources
+	s.SSHConfigSources, err = sshConfigSources(loadedState.SSHConfigSources)
+	if err != nil {
+		s.Logger.Error("[APPSTATE] Cannot apply ssh_config sources from state file: %v", err)
+	}
+
 	s.Logger.Debug("[APPSTATE] Screen layout: '%v'. Focused host id: '%v'", s.ScreenLayout, s.Selected)
+	// Remember loaded values, so that Persist can find out which of them were changed by this
+	// instance of the app. Command line options are applied later and count as changes.
+	s.persisted, _ = yaml.Marshal(s)
 }
 
 //nolint:gocognit // this function performs a single task and can't be splitted
@@ -248,6 +300,18 @@ func (s *State) applyConfig(cfg *config.Configuration) error {
 		s.IsUserDefinedSSHConfigPath = true
 	}
 
+	if len(cfg.SSHConfigSources) > 0 {
+		if !s.SSHConfigEnabled {
+			return errors.New("you must enable ssh_config support to use additional ssh_config sources")
+		}
+
+		sources, err := sshConfigSources(cfg.SSHConfigSources)
+		if err != nil {
+			return fmt.Errorf("cannot set ssh config source: %w", err)
+		}
+		s.SSHConfigSources = sources
+	}
+
 	if !utils.StringEmpty(&cfg.SetTheme) {
 		installedThemes := theme.ListInstalled(cfg.AppHome, s.Logger)
 		if !lo.Contains(installedThemes, cfg.SetTheme) {
@@ -260,6 +324,26 @@ func (s *State) applyConfig(cfg *config.Configuration) error {
 	return nil
 }
 
+// sshConfigSources - converts local ssh_config paths to absolute ones, and removes empty values.
+func sshConfigSources(sources []string) ([]string, error) {
+	result := []string{}
+	for _, source := range sources {
+		source = strings.TrimSpace(source)
+		if utils.StringEmpty(&source) {
+			continue
+		}
+
+		sourcePath, err := utils.SSHConfigPath(source)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, sourcePath)
+	}
+
+	return result, nil
+}
+
 // Persist saves app state to disk.
 func (s *State) Persist() error {
 	appStateFilePath := path.Join(s.AppHome, stateFile)
@@ -270,21 +354,83 @@ func (s *State) Persist() error {
 		return err
 	}
 
-	err = os.WriteFile(appStateFilePath, result, 0o600)
+	unlock, err := utils.LockFile(appStateFilePath)
+	if err != nil {
+		s.Logger.Error("[APPSTATE] Cannot lock application state file. %v", err)
+		return err
+	}
+	defer unlock()
+
+	result, err = s.mergeExternalChanges(appStateFilePath, result)
+	if err != nil {
+		s.Logger.Error("[APPSTATE] Cannot merge application state. %v", err)
+		return err
+	}
+
+	// The file is replaced atomically, so that it's not truncated if the app crashes while writing it.
+	err = utils.WriteFileAtomic(appStateFilePath, result, 0o600)
 	if err != nil {
 		s.Logger.Error("[APPSTATE] Cannot save application state. %v", err)
 		return err
 	}
 
+	s.persisted = result
 	return nil
 }
 
+// mergeExternalChanges - if the state file was modified by another instance of the app after it had been
+// read, then values which were changed by that instance are preserved, unless this instance changed them too.
+func (s *State) mergeExternalChanges(filePath string, result []byte) ([]byte, error) {
+	var base, theirs, ours map[string]any
+	fileData, err := os.ReadFile(filePath)
+	if err != nil || yaml.Unmarshal(fileData, &theirs) != nil || len(theirs) == 0 {
+		// The file does not exist or it is corrupted, nothing to merge with.
+		return result, nil
+	}
+
+	if err = yaml.Unmarshal(s.persisted, &base); err != nil {
+		return nil, err
+	}
+
+	if reflect.DeepEqual(base, theirs) {
+		return result, nil
+	}
+
+	s.Logger.Info("[APPSTATE] Application state was modified by another process, merge changes")
+	if err = yaml.Unmarshal(result, &ours); err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]any, len(ours))
+	for _, key := range lo.Uniq(slices.Concat(lo.Keys(base), lo.Keys(theirs), lo.Keys(ours))) {
+		ourValue, ourOK := ours[key]
+		baseValue, baseOK := base[key]
+		changedByUs := ourOK != baseOK || !reflect.DeepEqual(ourValue, baseValue)
+		if changedByUs {
+			if ourOK {
+				merged[key] = ourValue
+			}
+		} else if theirValue, ok := theirs[key]; ok {
+			merged[key] = theirValue
+		}
+	}
+
+	return yaml.Marshal(merged)
+}
+
 func (s *State) print() {
 	fmt.Printf("App home:          %s\n", s.AppHome)
 	fmt.Printf("Log level:         %s\n", s.LogLevel)
+	fmt.Printf("Backup count:      %d\n", s.BackupCount)
+	if len(s.Inventories) > 0 {
+		fmt.Printf("Inventory:         %s\n", strings.Join(s.Inventories, string(os.PathListSeparator)))
+	}
 	fmt.Printf("SSH config status: %s\n", lo.Ternary(s.SSHConfigEnabled, "enabled", "disabled"))
 	if s.SSHConfigEnabled {
-		fmt.Printf("SSH config path:   %s\n", s.SSHConfigPath)
+		fmt.Printf("SSH config path:   %s\n", utils.RedactURL(s.SSHConfigPath))
+		for _, source := range s.SSHConfigSources {
+			fmt.Printf("SSH config source: %s\n", utils.RedactURL(source))
+		}
 	}
 }
 
@@ -300,6 +446,20 @@ func (s *State) LogDetails() {
 	s.Logger.Info("[CONFIG] Application log level:   %q\n", s.LogLevel)
 	s.Logger.Info("[CONFIG] SSH config status:       %q\n", lo.Ternary(s.SSHConfigEnabled, "enabled", "disabled"))
 	if s.SSHConfigEnabled {
-		s.Logger.Info("[CONFIG] SSH config path:         %q\n", s.SSHConfigPath)
+		// URLs can contain passwords, they're never written to the log file.
+		s.Logger.Info("[CONFIG] SSH config path:         %q\n", utils.RedactURL(s.SSHConfigPath))
+		if len(s.SSHConfigSources) > 0 {
+			s.Logger.Info("[CONFIG] SSH config sources:      %q\n", lo.Map(s.SSHConfigSources, func(source string, _ int) string {
+				return utils.RedactURL(source)
+			}))
+		}
+		for _, access := range s.RemoteAccess {
+			// Only URL is logged, credentials are not.
+			s.Logger.Info("[CONFIG] Remote access settings:  %q\n", utils.RedactURL(access.URL))
+		}
+	}
+	s.Logger.Info("[CONFIG] Hosts file backup count: %d\n", s.BackupCount)
+	if len(s.Inventories) > 0 {
+		s.Logger.Info("[CONFIG] Inventory:               %q\n", s.Inventories)
 	}
 }
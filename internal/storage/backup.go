@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+const (
+	backupFolder     = "backups"
+	backupFilePrefix = "hosts-"
+	backupFileSuffix = ".yaml"
+	// backupTimeFormat is sortable, contains no characters which are forbidden in file names
+	// and is precise enough to keep several backups which are created within a second.
+	backupTimeFormat = "20060102-150405.000000"
+)
+
+// Backup describes a copy of hosts.yaml file which is created before the file is modified.
+type Backup struct {
+	Name    string
+	Path    string
+	Created time.Time
+	Size    int64
+}
+
+// BackupFolder - returns path to the folder where backups of hosts file are stored.
+// Backups of the encrypted file are stored in a sub-folder.
+func BackupFolder(appHome string) string {
+	if IsEncrypted(appHome) {
+		return path.Join(appHome, backupFolder, encryptedBackupFolder)
+	}
+
+	return path.Join(appHome, backupFolder)
+}
+
+// ListBackups - returns hosts.yaml backups which are stored in the application home folder.
+// The most recent backup comes first.
+func ListBackups(appHome string) ([]Backup, error) {
+	return listBackups(BackupFolder(appHome))
+}
+
+func listBackups(folder string) ([]Backup, error) {
+	entries, err := os.ReadDir(folder)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Backup{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0, len(entries))
+	for _, entry := range entries {
+		created, ok := parseBackupTime(entry.Name())
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, Backup{
+			Name:    entry.Name(),
+			Path:    path.Join(folder, entry.Name()),
+			Created: created,
+			Size:    info.Size(),
+		})
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int {
+		return b.Created.Compare(a.Created)
+	})
+
+	return backups, nil
+}
+
+// RestoreBackup - replaces hosts.yaml with the content of the backup. The current version of
+// hosts.yaml is backed up as well, so that the restore operation can be reverted.
+func RestoreBackup(appHome string, backup Backup, backupCount int, logger iLogger) error {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return fmt.Errorf("cannot read backup: %w", err)
+	}
+
+	hostsFilePath := path.Join(appHome, lo.Ternary(IsEncrypted(appHome), encryptedHostsFile, hostsFile))
+	unlock, err := utils.LockFile(hostsFilePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Keep at least one backup, otherwise the current file will be lost.
+	if err = createBackup(hostsFilePath, BackupFolder(appHome), max(backupCount, 1)); err != nil {
+		return fmt.Errorf("cannot backup current hosts file: %w", err)
+	}
+
+	logger.Info("[STORAGE] Restore hosts file from backup: %q", backup.Path)
+	return utils.WriteFileAtomic(hostsFilePath, data, 0o600)
+}
+
+// createBackup - copies filePath to backup folder and removes the oldest backups, so that
+// only backupCount copies are left. When backupCount is 0, backups are disabled.
+func createBackup(filePath, folder string, backupCount int) error {
+	if backupCount <= 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		// Nothing to back up yet.
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(folder, 0o700); err != nil {
+		return err
+	}
+
+	backupName := backupFilePrefix + time.Now().Format(backupTimeFormat) + backupFileSuffix
+	if err = utils.WriteFileAtomic(path.Join(folder, backupName), data, 0o600); err != nil {
+		return err
+	}
+
+	return pruneBackups(folder, backupCount)
+}
+
+func pruneBackups(folder string, backupCount int) error {
+	backups, err := listBackups(folder)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(backupCount, len(backups)):] {
+		if err = os.Remove(backup.Path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseBackupTime(fileName string) (time.Time, bool) {
+	timestamp, found := strings.CutPrefix(fileName, backupFilePrefix)
+	if !found {
+		return time.Time{}, false
+	}
+
+	timestamp, found = strings.CutSuffix(timestamp, backupFileSuffix)
+	if !found {
+		return time.Time{}, false
+	}
+
+	created, err := time.ParseInLocation(backupTimeFormat, timestamp, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return created, true
+}
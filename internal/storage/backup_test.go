@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func TestCreateBackup(t *testing.T) {
+	appHome := t.TempDir()
+	hostsFilePath := filepath.Join(appHome, hostsFile)
+
+	// File does not exist yet, nothing to back up
+	err := createBackup(hostsFilePath, BackupFolder(appHome), 2)
+	require.NoError(t, err)
+	backups, err := ListBackups(appHome)
+	require.NoError(t, err)
+	require.Empty(t, backups)
+
+	for _, content := range []string{"v1", "v2", "v3"} {
+		require.NoError(t, os.WriteFile(hostsFilePath, []byte(content), 0o600))
+		require.NoError(t, createBackup(hostsFilePath, BackupFolder(appHome), 2))
+		// Make sure that backups receive different timestamps
+		time.Sleep(time.Millisecond)
+	}
+
+	// Only 2 most recent backups are kept, the most recent comes first
+	backups, err = ListBackups(appHome)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	data, err := os.ReadFile(backups[0].Path)
+	require.NoError(t, err)
+	require.Equal(t, "v3", string(data))
+	data, err = os.ReadFile(backups[1].Path)
+	require.NoError(t, err)
+	require.Equal(t, "v2", string(data))
+	require.True(t, backups[0].Created.After(backups[1].Created))
+}
+
+func TestCreateBackup_Disabled(t *testing.T) {
+	appHome := t.TempDir()
+	hostsFilePath := filepath.Join(appHome, hostsFile)
+	require.NoError(t, os.WriteFile(hostsFilePath, []byte("v1"), 0o600))
+
+	require.NoError(t, createBackup(hostsFilePath, BackupFolder(appHome), 0))
+	require.NoDirExists(t, BackupFolder(appHome))
+}
+
+func TestListBackups_IgnoresUnknownFiles(t *testing.T) {
+	appHome := t.TempDir()
+	require.NoError(t, os.MkdirAll(BackupFolder(appHome), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(BackupFolder(appHome), "notes.txt"), []byte{}, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(BackupFolder(appHome), "hosts-invalid.yaml"), []byte{}, 0o600))
+	require.NoError(t, os.WriteFile(
+		filepath.Join(BackupFolder(appHome), "hosts-20261017-150405.000000.yaml"), []byte("data"), 0o600))
+
+	backups, err := ListBackups(appHome)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	require.Equal(t, "hosts-20261017-150405.000000.yaml", backups[0].Name)
+	require.Equal(t, int64(4), backups[0].Size)
+	require.Equal(t, 2026, backups[0].Created.Year())
+}
+
+func TestRestoreBackup(t *testing.T) {
+	appHome := t.TempDir()
+	hostsFilePath := filepath.Join(appHome, hostsFile)
+	require.NoError(t, os.WriteFile(hostsFilePath, []byte("old"), 0o600))
+	require.NoError(t, createBackup(hostsFilePath, BackupFolder(appHome), 5))
+	require.NoError(t, os.WriteFile(hostsFilePath, []byte("broken"), 0o600))
+
+	backups, err := ListBackups(appHome)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	time.Sleep(time.Millisecond)
+	err = RestoreBackup(appHome, backups[0], 5, &mocklogger.Logger{})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(hostsFilePath)
+	require.NoError(t, err)
+	require.Equal(t, "old", string(data))
+
+	// The version which was replaced is backed up as well
+	backups, err = ListBackups(appHome)
+	require.NoError(t, err)
+	require.Len(t, backups, 2)
+	data, err = os.ReadFile(backups[0].Path)
+	require.NoError(t, err)
+	require.Equal(t, "broken", string(data))
+}
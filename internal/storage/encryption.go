@@ -0,0 +1,384 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/hkdf"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"filippo.io/age"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+const (
+	encryptedHostsFile = "hosts.yaml.enc"
+	// encryptedBackupFolder is a sub-folder of backup folder, so that encrypted and plain text
+	// backups are never mixed up.
+	encryptedBackupFolder = "encrypted"
+	encryptionMagic       = "GGENC1"
+	encryptionKeySize     = 32
+	encryptionSaltSize    = 16
+	keyFileInfo           = "goto hosts file encryption"
+	ageSecretKeyPrefix    = "AGE-SECRET-KEY-"
+)
+
+// Key derivation functions, the value is stored in the file header.
+const (
+	kdfPassphrase byte = iota + 1
+	kdfKeyFile
+)
+
+// pbkdf2Iterations - is a variable to make it possible to override it in unit-tests.
+var pbkdf2Iterations = 600_000
+
+var (
+	// ErrWrongEncryptionKey - is returned when hosts file cannot be decrypted with the provided key.
+	ErrWrongEncryptionKey = errors.New("cannot decrypt hosts file, wrong passphrase or key file")
+	// ErrEncryptionKeyRequired - is returned when neither passphrase nor key file is provided.
+	ErrEncryptionKeyRequired = errors.New("passphrase or key file is required to decrypt hosts file")
+	// ErrKeyFilePassphraseRequired - is returned when the key file is protected with a passphrase.
+	ErrKeyFilePassphraseRequired = errors.New("key file is protected with a passphrase")
+	errNotEncrypted              = errors.New("file is not encrypted by goto")
+)
+
+// EncryptionKey describes a secret which is used to encrypt hosts file. The key file is an age
+// identity or an ssh private key, the encryption key is derived from its secret key, therefore
+// the file can be re-encoded, for instance, when its passphrase is changed. If the key file is
+// set, the passphrase is only used to decrypt the key file when it's protected.
+type EncryptionKey struct {
+	Passphrase string
+	KeyFile    string
+}
+
+// IsEncrypted - returns true if hosts are stored in the encrypted file.
+func IsEncrypted(appHome string) bool {
+	_, err := os.Stat(path.Join(appHome, encryptedHostsFile))
+	return err == nil
+}
+
+// VerifyEncryptionKey - checks that the encrypted hosts file can be decrypted with the key.
+func VerifyEncryptionKey(appHome string, key EncryptionKey) error {
+	data, err := os.ReadFile(path.Join(appHome, encryptedHostsFile))
+	if err != nil {
+		return err
+	}
+
+	_, err = newFileCipher(key).decrypt(data)
+	return err
+}
+
+// fileCipher encrypts data with AES-256-GCM. The key is derived from a passphrase using
+// PBKDF2 or from a key file using HKDF. File layout:
+//
+//	magic | kdf | salt | nonce | ciphertext
+//
+// magic, kdf and salt are authenticated as additional data.
+type fileCipher struct {
+	secret EncryptionKey
+	// Key derivation is slow by design, the key is cached and reused with the same salt.
+	keyKDF byte
+	salt   []byte
+	key    []byte
+}
+
+func newFileCipher(secret EncryptionKey) *fileCipher {
+	return &fileCipher{secret: secret}
+}
+
+func (c *fileCipher) kdf() byte {
+	if utils.StringEmpty(&c.secret.KeyFile) {
+		return kdfPassphrase
+	}
+
+	return kdfKeyFile
+}
+
+func (c *fileCipher) deriveKey(kdf byte, salt []byte) ([]byte, error) {
+	if c.key != nil && bytes.Equal(c.salt, salt) && kdf == c.keyKDF {
+		return c.key, nil
+	}
+
+	var key []byte
+	var err error
+	switch kdf {
+	case kdfPassphrase:
+		if c.secret.Passphrase == "" {
+			return nil, ErrEncryptionKeyRequired
+		}
+
+		key, err = pbkdf2.Key(sha256.New, c.secret.Passphrase, salt, pbkdf2Iterations, encryptionKeySize)
+	case kdfKeyFile:
+		if utils.StringEmpty(&c.secret.KeyFile) {
+			return nil, ErrEncryptionKeyRequired
+		}
+
+		var keyMaterial []byte
+		keyMaterial, err = readKeyFile(c.secret.KeyFile, c.secret.Passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err = hkdf.Key(sha256.New, keyMaterial, salt, keyFileInfo, encryptionKeySize)
+	default:
+		return nil, fmt.Errorf("unsupported key derivation function: %d", kdf)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.keyKDF, c.salt, c.key = kdf, salt, key
+	return key, nil
+}
+
+func (c *fileCipher) encrypt(plainText []byte) ([]byte, error) {
+	// When the file is re-encrypted, the key which was used to decrypt it is reused.
+	kdf, salt := c.keyKDF, c.salt
+	if c.key == nil {
+		kdf = c.kdf()
+		salt = make([]byte, encryptionSaltSize)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, err
+		}
+	}
+
+	key, err := c.deriveKey(kdf, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := append([]byte(encryptionMagic), kdf)
+	header = append(header, salt...)
+	result := append(bytes.Clone(header), nonce...)
+	return aead.Seal(result, nonce, plainText, header), nil
+}
+
+func (c *fileCipher) decrypt(data []byte) ([]byte, error) {
+	headerSize := len(encryptionMagic) + 1 + encryptionSaltSize
+	if len(data) < headerSize || string(data[:len(encryptionMagic)]) != encryptionMagic {
+		return nil, errNotEncrypted
+	}
+
+	kdf := data[len(encryptionMagic)]
+	salt := data[len(encryptionMagic)+1 : headerSize]
+	// Use passphrase or key file depending on how the file was encrypted.
+	key, err := c.deriveKey(kdf, bytes.Clone(salt))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < headerSize+aead.NonceSize() {
+		return nil, errNotEncrypted
+	}
+
+	nonce := data[headerSize : headerSize+aead.NonceSize()]
+	plainText, err := aead.Open(nil, nonce, data[headerSize+aead.NonceSize():], data[:headerSize])
+	if err != nil {
+		// Forget the key, as it's wrong.
+		c.keyKDF, c.salt, c.key = 0, nil, nil
+		return nil, ErrWrongEncryptionKey
+	}
+
+	return plainText, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptHostsFile - encrypts hosts.yaml and its backups, and removes their plain text versions.
+func EncryptHostsFile(appHome string, secret EncryptionKey, logger iLogger) error {
+	plainFilePath := path.Join(appHome, hostsFile)
+	encryptedFilePath := path.Join(appHome, encryptedHostsFile)
+	if IsEncrypted(appHome) {
+		return fmt.Errorf("hosts file is already encrypted: %s", encryptedFilePath)
+	}
+
+	// The same cipher is used for the backups, so that the key is only derived once.
+	fileCipher := newFileCipher(secret)
+	if err := convertHostsFile(plainFilePath, encryptedFilePath, fileCipher.encrypt, logger); err != nil {
+		return err
+	}
+
+	// Backups contain the same hosts, they're encrypted, so that they can still be restored.
+	err := encryptBackups(path.Join(appHome, backupFolder), path.Join(appHome, backupFolder, encryptedBackupFolder),
+		fileCipher.encrypt, logger)
+	if err != nil {
+		return fmt.Errorf("hosts file is encrypted, but plain text backups are left: %w", err)
+	}
+
+	return nil
+}
+
+// VerifyKeyFile - checks that the key file is an age identity or an ssh private key, which can be
+// used to encrypt hosts file.
+func VerifyKeyFile(filePath, passphrase string) error {
+	_, err := readKeyFile(filePath, passphrase)
+	return err
+}
+
+// readKeyFile - returns the secret key from an age identity or an ssh private key file. The key
+// is encoded the same way regardless of the file format, so that hosts file can be decrypted
+// after the key file is re-encoded.
+func readKeyFile(filePath, passphrase string) ([]byte, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read key file: %w", err)
+	}
+
+	if bytes.Contains(data, []byte(ageSecretKeyPrefix)) {
+		return readAgeIdentity(data)
+	}
+
+	key, err := ssh.ParseRawPrivateKey(data)
+	var passphraseMissingErr *ssh.PassphraseMissingError
+	if errors.As(err, &passphraseMissingErr) {
+		if passphrase == "" {
+			return nil, ErrKeyFilePassphraseRequired
+		}
+
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(data, []byte(passphrase))
+		if errors.Is(err, x509.IncorrectPasswordError) {
+			return nil, ErrWrongEncryptionKey
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read key file, only age identities and ssh private keys are supported: %w", err)
+	}
+
+	// ssh package returns a pointer to ed25519 key, which cannot be marshaled.
+	if ed25519Key, ok := key.(*ed25519.PrivateKey); ok {
+		key = *ed25519Key
+	}
+
+	result, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported key file: %w", err)
+	}
+
+	return result, nil
+}
+
+// readAgeIdentity - returns the first X25519 identity from an age identity file. Its canonical
+// encoding is used as a key material, as age does not expose the secret key itself.
+func readAgeIdentity(data []byte) ([]byte, error) {
+	for line := range strings.Lines(string(data)) {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, ageSecretKeyPrefix) {
+			continue
+		}
+
+		identity, err := age.ParseX25519Identity(line)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read age identity: %w", err)
+		}
+
+		return []byte(identity.String()), nil
+	}
+
+	return nil, errors.New("cannot read key file, age identity is not found")
+}
+
+// encryptBackups - moves plain text backups from plainFolder to encryptedFolder, and encrypts them.
+func encryptBackups(plainFolder, encryptedFolder string, encrypt func([]byte) ([]byte, error), logger iLogger) error {
+	backups, err := listBackups(plainFolder)
+	if err != nil || len(backups) == 0 {
+		return err
+	}
+
+	if err = os.MkdirAll(encryptedFolder, 0o700); err != nil {
+		return err
+	}
+
+	logger.Info("[STORAGE] Encrypt %d backups from %q", len(backups), plainFolder)
+	for _, backup := range backups {
+		data, err := os.ReadFile(backup.Path)
+		if err != nil {
+			return err
+		}
+
+		result, err := encrypt(data)
+		if err != nil {
+			return err
+		}
+
+		if err = utils.WriteFileAtomic(path.Join(encryptedFolder, backup.Name), result, 0o600); err != nil {
+			return err
+		}
+
+		if err = os.Remove(backup.Path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecryptHostsFile - decrypts hosts file and removes its encrypted version.
+func DecryptHostsFile(appHome string, secret EncryptionKey, logger iLogger) error {
+	plainFilePath := path.Join(appHome, hostsFile)
+	encryptedFilePath := path.Join(appHome, encryptedHostsFile)
+	if _, err := os.Stat(plainFilePath); err == nil {
+		return fmt.Errorf("plain text hosts file already exists: %s", plainFilePath)
+	}
+
+	return convertHostsFile(encryptedFilePath, plainFilePath, newFileCipher(secret).decrypt, logger)
+}
+
+func convertHostsFile(sourcePath, targetPath string, convert func([]byte) ([]byte, error), logger iLogger) error {
+	// Prevent other instances of the app from modifying the file while it is being converted.
+	unlock, err := utils.LockFile(sourcePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return err
+	}
+
+	result, err := convert(data)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("[STORAGE] Convert hosts file %q to %q", sourcePath, targetPath)
+	if err = utils.WriteFileAtomic(targetPath, result, 0o600); err != nil {
+		return err
+	}
+
+	return os.Remove(sourcePath)
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/constant"
+	model "github.com/grafviktor/goto/internal/model/host"
+	testutils "github.com/grafviktor/goto/internal/testutils"
+)
+
+func useFastKeyDerivation(t *testing.T) {
+	t.Helper()
+	originalIterations := pbkdf2Iterations
+	pbkdf2Iterations = 1000
+	t.Cleanup(func() { pbkdf2Iterations = originalIterations })
+}
+
+func Test_fileCipher_Passphrase(t *testing.T) {
+	useFastKeyDerivation(t)
+	encrypted, err := newFileCipher(EncryptionKey{Passphrase: "secret"}).encrypt([]byte("hosts"))
+	require.NoError(t, err)
+	require.NotContains(t, string(encrypted), "hosts")
+
+	decrypted, err := newFileCipher(EncryptionKey{Passphrase: "secret"}).decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "hosts", string(decrypted))
+
+	_, err = newFileCipher(EncryptionKey{Passphrase: "wrong"}).decrypt(encrypted)
+	require.ErrorIs(t, err, ErrWrongEncryptionKey)
+
+	_, err = newFileCipher(EncryptionKey{}).decrypt(encrypted)
+	require.ErrorIs(t, err, ErrEncryptionKeyRequired)
+
+	// Header is authenticated as well.
+	encrypted[len(encryptionMagic)+1] ^= 0xff
+	_, err = newFileCipher(EncryptionKey{Passphrase: "secret"}).decrypt(encrypted)
+	require.ErrorIs(t, err, ErrWrongEncryptionKey)
+
+	_, err = newFileCipher(EncryptionKey{Passphrase: "secret"}).decrypt([]byte("- host:\n"))
+	require.ErrorIs(t, err, errNotEncrypted)
+}
+
+func Test_fileCipher_KeyFile(t *testing.T) {
+	sshKeyFile, sshKey := testutils.WriteSSHKey(t, "")
+	tests := []struct {
+		name    string
+		keyFile string
+	}{
+		{"SSH private key", sshKeyFile},
+		{"Age identity", testutils.WriteAgeIdentity(t)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encrypted, err := newFileCipher(EncryptionKey{KeyFile: tt.keyFile}).encrypt([]byte("hosts"))
+			require.NoError(t, err)
+			require.NotContains(t, string(encrypted), "hosts")
+
+			decrypted, err := newFileCipher(EncryptionKey{KeyFile: tt.keyFile}).decrypt(encrypted)
+			require.NoError(t, err)
+			require.Equal(t, "hosts", string(decrypted))
+
+			// The file was encrypted with the key file, passphrase cannot be used to decrypt it.
+			_, err = newFileCipher(EncryptionKey{Passphrase: "secret"}).decrypt(encrypted)
+			require.ErrorIs(t, err, ErrEncryptionKeyRequired)
+
+			_, err = newFileCipher(EncryptionKey{KeyFile: testutils.WriteAgeIdentity(t)}).decrypt(encrypted)
+			require.ErrorIs(t, err, ErrWrongEncryptionKey)
+		})
+	}
+
+	encrypted, err := newFileCipher(EncryptionKey{KeyFile: sshKeyFile}).encrypt([]byte("hosts"))
+	require.NoError(t, err)
+
+	// The same key protected with a passphrase can still decrypt the file.
+	testutils.RewriteSSHKey(t, sshKeyFile, sshKey, "secret")
+	_, err = newFileCipher(EncryptionKey{KeyFile: sshKeyFile}).decrypt(encrypted)
+	require.ErrorIs(t, err, ErrKeyFilePassphraseRequired)
+	_, err = newFileCipher(EncryptionKey{KeyFile: sshKeyFile, Passphrase: "wrong"}).decrypt(encrypted)
+	require.ErrorIs(t, err, ErrWrongEncryptionKey)
+	decrypted, err := newFileCipher(EncryptionKey{KeyFile: sshKeyFile, Passphrase: "secret"}).decrypt(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, "hosts", string(decrypted))
+}
+
+func TestVerifyKeyFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "hosts.key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("random bytes"), 0o600))
+	require.ErrorContains(t, VerifyKeyFile(keyFile, ""), "only age identities and ssh private keys are supported")
+
+	require.NoError(t, os.WriteFile(keyFile, []byte("AGE-SECRET-KEY-1INVALID\n"), 0o600))
+	require.ErrorContains(t, VerifyKeyFile(keyFile, ""), "cannot read age identity")
+
+	require.Error(t, VerifyKeyFile(filepath.Join(t.TempDir(), "missing.key"), ""))
+	require.NoError(t, VerifyKeyFile(testutils.WriteAgeIdentity(t), ""))
+}
+
+func TestEncryptHostsFile_Backups(t *testing.T) {
+	useFastKeyDerivation(t)
+	appHome := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(appHome, hostsFile), []byte("- host:\n"), 0o600))
+	plainBackup := filepath.Join(appHome, backupFolder, "hosts-20261017-150405.000000.yaml")
+	require.NoError(t, os.MkdirAll(filepath.Dir(plainBackup), 0o700))
+	require.NoError(t, os.WriteFile(plainBackup, []byte("- host:\n    address: backup.com\n"), 0o600))
+
+	key := EncryptionKey{Passphrase: "secret"}
+	require.NoError(t, EncryptHostsFile(appHome, key, &testLogger{}))
+	require.NoFileExists(t, plainBackup)
+
+	data, err := os.ReadFile(filepath.Join(BackupFolder(appHome), filepath.Base(plainBackup)))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "backup.com")
+	decrypted, err := newFileCipher(key).decrypt(data)
+	require.NoError(t, err)
+	require.Contains(t, string(decrypted), "backup.com")
+}
+
+func TestEncryptedYAMLFile(t *testing.T) {
+	useFastKeyDerivation(t)
+	appHome := t.TempDir()
+	hostsFilePath := filepath.Join(appHome, hostsFile)
+	require.NoError(t, os.WriteFile(hostsFilePath, []byte("- host:\n    id: \"1\"\n    title: host1\n"), 0o600))
+
+	key := EncryptionKey{Passphrase: "secret"}
+	require.NoError(t, EncryptHostsFile(appHome, key, &testLogger{}))
+	require.NoFileExists(t, hostsFilePath)
+	require.True(t, IsEncrypted(appHome))
+	require.Error(t, EncryptHostsFile(appHome, key, &testLogger{}), "file is already encrypted")
+	require.ErrorIs(t, VerifyEncryptionKey(appHome, EncryptionKey{Passphrase: "wrong"}), ErrWrongEncryptionKey)
+	require.NoError(t, VerifyEncryptionKey(appHome, key))
+
+	st := newEncryptedYAMLStorage(context.TODO(), appHome, 1, key, &testLogger{})
+	require.Equal(t, constant.HostStorageType.EncryptedYAMLFile, st.Type())
+	hosts, err := st.GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+
+	_, err = st.Save(model.Host{Title: "host2", Address: "host2.com"})
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(appHome, encryptedHostsFile))
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "host2.com")
+
+	// Backups are encrypted as well.
+	backups, err := ListBackups(appHome)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	require.Equal(t, filepath.Join(appHome, backupFolder, encryptedBackupFolder), filepath.Dir(backups[0].Path))
+
+	_, err = newEncryptedYAMLStorage(context.TODO(), appHome, 0, EncryptionKey{Passphrase: "wrong"}, &testLogger{}).GetAll()
+	require.ErrorIs(t, err, ErrWrongEncryptionKey)
+
+	require.NoError(t, DecryptHostsFile(appHome, key, &testLogger{}))
+	require.False(t, IsEncrypted(appHome))
+	hosts, err = newYAMLStorage(context.TODO(), appHome, 0, &testLogger{}).GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+}
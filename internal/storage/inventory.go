@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// inventoryFilePattern - host files which are loaded from inventory folders.
+const inventoryFilePattern = "*.yaml"
+
+// getInventoryStorages - creates a storage for every additional host file. mainFilePath is
+// the hosts file from the application home folder, it's never loaded twice.
+func getInventoryStorages(
+	ctx context.Context,
+	st *state.State,
+	mainFilePath string,
+	logger iLogger,
+) ([]HostStorage, error) {
+	filePaths, err := inventoryFiles(st.Inventories)
+	if err != nil {
+		return nil, err
+	}
+
+	storages := []HostStorage{}
+	for _, filePath := range filePaths {
+		if samePath(filePath, mainFilePath) {
+			continue
+		}
+
+		logger.Info("[STORAGE] Load hosts from inventory file: %q", filePath)
+		storages = append(storages, newInventoryStorage(ctx, filePath, logger))
+	}
+
+	return storages, nil
+}
+
+// getDefaultStorage - returns the storage where new hosts are saved. If the default inventory
+// is not one of the storages, a new storage is created. The file is created once the first
+// host is saved.
+func getDefaultStorage(
+	ctx context.Context,
+	st *state.State,
+	storages []HostStorage,
+	logger iLogger,
+) (HostStorage, error) {
+	// The first storage keeps hosts in the application home folder.
+	if utils.StringEmpty(&st.DefaultInventory) {
+		return storages[0], nil
+	}
+
+	defaultPath, err := filepath.Abs(st.DefaultInventory)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default inventory path %q: %w", st.DefaultInventory, err)
+	}
+
+	if info, statErr := os.Stat(defaultPath); statErr == nil && info.IsDir() {
+		return nil, fmt.Errorf("default inventory must be a file: %q", defaultPath)
+	}
+
+	for _, storage := range storages {
+		if f, ok := storage.(fileStorage); ok && samePath(f.FilePath(), defaultPath) {
+			return storage, nil
+		}
+	}
+
+	logger.Info("[STORAGE] Save new hosts to inventory file: %q", defaultPath)
+	return newInventoryStorage(ctx, defaultPath, logger), nil
+}
+
+// inventoryFiles - converts a list of host files and folders into a list of absolute file paths.
+// Folders are replaced with "*.yaml" files, which they contain. Files which do not exist are kept,
+// they are created when a host is saved. Duplicates are removed.
+func inventoryFiles(paths []string) ([]string, error) {
+	result := []string{}
+	for _, inventoryPath := range paths {
+		if utils.StringEmpty(&inventoryPath) {
+			continue
+		}
+
+		absPath, err := filepath.Abs(inventoryPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inventory path %q: %w", inventoryPath, err)
+		}
+
+		filePaths := []string{absPath}
+		if info, statErr := os.Stat(absPath); statErr == nil && info.IsDir() {
+			// Glob only fails when the pattern is malformed. Results are sorted.
+			filePaths, _ = filepath.Glob(filepath.Join(absPath, inventoryFilePattern))
+		}
+
+		for _, filePath := range filePaths {
+			if !slices.Contains(result, filePath) {
+				result = append(result, filePath)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
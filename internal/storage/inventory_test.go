@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/state"
+)
+
+func TestInventoryFiles(t *testing.T) {
+	folder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "b.yaml"), nil, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "a.yaml"), nil, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(folder, "notes.txt"), nil, 0o600))
+	personal := filepath.Join(t.TempDir(), "personal.yaml")
+
+	files, err := inventoryFiles([]string{folder, "", personal, filepath.Join(folder, "a.yaml")})
+	require.NoError(t, err)
+	// Folder is replaced with sorted *.yaml files, missing files are kept and duplicates are removed.
+	require.Equal(t, []string{
+		filepath.Join(folder, "a.yaml"),
+		filepath.Join(folder, "b.yaml"),
+		personal,
+	}, files)
+}
+
+func TestGetStorages_Inventories(t *testing.T) {
+	appHome := t.TempDir()
+	teamFolder := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(teamFolder, "team.yaml"), nil, 0o600))
+	st := &state.State{
+		AppHome: appHome,
+		// The main hosts file should not be loaded twice.
+		Inventories: []string{teamFolder, filepath.Join(appHome, hostsFile)},
+	}
+
+	storages, defaultStorage, err := getStorages(context.TODO(), st, &testLogger{})
+	require.NoError(t, err)
+	require.Len(t, storages, 2)
+	require.Equal(t, storages[0], defaultStorage)
+	require.Equal(t, filepath.Join(teamFolder, "team.yaml"), storages[1].(fileStorage).FilePath())
+
+	// Default inventory can be one of the loaded files.
+	st.DefaultInventory = filepath.Join(teamFolder, "team.yaml")
+	storages, defaultStorage, err = getStorages(context.TODO(), st, &testLogger{})
+	require.NoError(t, err)
+	require.Len(t, storages, 2)
+	require.Equal(t, storages[1], defaultStorage)
+
+	// Or a file which does not exist yet.
+	st.DefaultInventory = filepath.Join(teamFolder, "new.yaml")
+	storages, defaultStorage, err = getStorages(context.TODO(), st, &testLogger{})
+	require.NoError(t, err)
+	require.Len(t, storages, 3)
+	require.Equal(t, st.DefaultInventory, defaultStorage.(fileStorage).FilePath())
+
+	// But not a folder.
+	st.DefaultInventory = teamFolder
+	_, _, err = getStorages(context.TODO(), st, &testLogger{})
+	require.Error(t, err)
+}
+
+func TestCombinedStorage_Inventories(t *testing.T) {
+	appHome := t.TempDir()
+	teamFile := filepath.Join(t.TempDir(), "team.yaml")
+	logger := &testLogger{}
+	personal := newYAMLStorage(context.TODO(), appHome, 0, logger)
+	team := newInventoryStorage(context.TODO(), teamFile, logger)
+	cs := combinedStorage{
+		storages:       []HostStorage{personal, team},
+		defaultStorage: team,
+		hosts:          make(map[string]model.Host),
+		hostStorageMap: make(map[string]HostStorage),
+		logger:         logger,
+	}
+
+	// Default file comes first.
+	require.Equal(t, []string{teamFile, personal.FilePath()}, cs.Inventories())
+
+	// New hosts are saved to the default file.
+	host, err := cs.Save(model.Host{Title: "new", Address: "localhost"})
+	require.NoError(t, err)
+	require.Equal(t, teamFile, host.SourcePath)
+
+	// The host is moved to another file, when its source path is changed.
+	host.SourcePath = personal.FilePath()
+	host, err = cs.Save(host)
+	require.NoError(t, err)
+	require.Equal(t, personal.FilePath(), host.SourcePath)
+
+	teamHosts, err := newInventoryStorage(context.TODO(), teamFile, logger).GetAll()
+	require.NoError(t, err)
+	require.Empty(t, teamHosts)
+
+	hosts, err := cs.GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, host.ID, hosts[0].ID)
+	require.Equal(t, personal.FilePath(), hosts[0].SourcePath)
+}
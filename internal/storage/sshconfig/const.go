@@ -8,7 +8,10 @@ const (
 	valueTypeRaw  valueTypeEnum = "raw"
 )
 
+// configSource is a file or URL which is read by the lexer. includedAt is the position of "Include"
+// directive, which loads the source, it's empty for the root source.
 type configSource struct {
-	value     string
-	valueType valueTypeEnum
+	value      string
+	valueType  valueTypeEnum
+	includedAt position
 }
@@ -0,0 +1,52 @@
+package sshconfig
+
+import (
+	"fmt"
+
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+type SeverityEnum string
+
+// Severity of a problem which is found in ssh_config file. Errors are the problems which lead to
+// missing or incorrect hosts, warnings are the constructions which are valid, but ignored by the app.
+var Severity = struct {
+	Error   SeverityEnum
+	Warning SeverityEnum
+}{
+	Error:   "error",
+	Warning: "warning",
+}
+
+// Diagnostic describes a problem which is found in ssh_config file. Line is 0 when the problem
+// relates to the whole file, for instance when the file cannot be read.
+type Diagnostic struct {
+	Source   string
+	Line     int
+	Severity SeverityEnum
+	Message  string
+}
+
+// String - formats the diagnostic as "file:line: severity: message". URL passwords are redacted.
+func (d Diagnostic) String() string {
+	if d.Line == 0 {
+		return fmt.Sprintf("%s: %s: %s", utils.RedactURL(d.Source), d.Severity, d.Message)
+	}
+
+	return fmt.Sprintf("%s:%d: %s: %s", utils.RedactURL(d.Source), d.Line, d.Severity, d.Message)
+}
+
+// position is a place in ssh_config file, where a token is declared.
+type position struct {
+	source string
+	line   int
+}
+
+func (p position) diagnostic(severity SeverityEnum, format string, args ...any) Diagnostic {
+	return Diagnostic{
+		Source:   p.source,
+		Line:     p.line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	}
+}
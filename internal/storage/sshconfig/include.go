@@ -0,0 +1,164 @@
+package sshconfig
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// globStar is a path segment which matches zero or more folders, for instance "conf.d/**/*.conf".
+const globStar = "**"
+
+// hasGlobStar - returns true if the pattern contains "**" segment.
+func hasGlobStar(pattern string) bool {
+	return slices.Contains(strings.Split(filepath.ToSlash(pattern), "/"), globStar)
+}
+
+// globRecursive - returns files which match the pattern. Unlike filepath.Glob, the pattern may contain
+// "**" segments. The second value contains the folders which are scanned by "**" segments, so that they
+// can be watched for new files.
+func globRecursive(pattern string) ([]string, []string, error) {
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	index := slices.Index(segments, globStar)
+	if index < 0 {
+		matches, err := filepath.Glob(pattern)
+		return matches, nil, err
+	}
+
+	// Leading part of the pattern can contain wildcards too, for instance "/etc/ssh/*.d/**/*.conf".
+	basePattern := filepath.FromSlash(strings.Join(segments[:index], "/"))
+	if basePattern == "" {
+		basePattern = string(filepath.Separator)
+	}
+	rest := filepath.FromSlash(strings.Join(segments[index+1:], "/"))
+	if rest == "" {
+		// Trailing "**" matches all files in the folder and its sub-folders.
+		rest = "*"
+	}
+
+	bases, err := filepath.Glob(basePattern)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var matches, folders []string
+	for _, base := range bases {
+		err = filepath.WalkDir(base, func(path string, entry fs.DirEntry, walkErr error) error {
+			if walkErr != nil || !entry.IsDir() {
+				// Folders which cannot be read are skipped, as the shell does.
+				return nil //nolint:nilerr // see the comment above
+			}
+
+			folders = append(folders, path)
+			// The rest of the pattern can contain other "**" segments.
+			restMatches, restFolders, restErr := globRecursive(filepath.Join(path, rest))
+			matches = append(matches, restMatches...)
+			folders = append(folders, restFolders...)
+			return restErr
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	slices.Sort(matches)
+	slices.Sort(folders)
+	return slices.Compact(matches), slices.Compact(folders), nil
+}
+
+var envVariableRe = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// expandEnvironment - replaces ${NAME} references with values of environment variables. As in OpenSSH,
+// undefined variables are an error, and $NAME syntax is not supported.
+func expandEnvironment(value string) (string, error) {
+	var err error
+	expanded := envVariableRe.ReplaceAllStringFunc(value, func(reference string) string {
+		name := envVariableRe.FindStringSubmatch(reference)[1]
+		envValue, found := os.LookupEnv(name)
+		if !found && err == nil {
+			err = fmt.Errorf("environment variable %q is not defined", name)
+		}
+
+		return envValue
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return expanded, nil
+}
+
+// expandTokens - replaces OpenSSH '%' tokens, for instance "%d" with their values. "%%" is a literal '%'.
+// Tokens which are not in the list are an error.
+func expandTokens(value string, tokens map[byte]string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '%' {
+			b.WriteByte(value[i])
+			continue
+		}
+
+		if i+1 == len(value) {
+			return "", fmt.Errorf("invalid token at the end of %q", value)
+		}
+
+		i++
+		if value[i] == '%' {
+			b.WriteByte('%')
+			continue
+		}
+
+		tokenValue, found := tokens[value[i]]
+		if !found {
+			return "", fmt.Errorf("token %%%c is not supported", value[i])
+		}
+
+		b.WriteString(tokenValue)
+	}
+
+	return b.String(), nil
+}
+
+// localTokens - returns values of '%' tokens, which do not depend on the host: local home folder (%d),
+// local user name (%u), local user ID (%i), local hostname with (%l) and without (%L) domain name.
+func localTokens() map[byte]string {
+	tokens := make(map[byte]string)
+	if home, err := os.UserHomeDir(); err == nil {
+		tokens['d'] = home
+	}
+
+	if currentUser, err := user.Current(); err == nil {
+		// On Windows, user name contains domain name: "DOMAIN\user".
+		tokens['u'] = currentUser.Username[strings.LastIndex(currentUser.Username, `\`)+1:]
+	}
+
+	tokens['i'] = strconv.Itoa(os.Getuid())
+
+	if hostname, err := os.Hostname(); err == nil {
+		tokens['l'] = hostname
+		tokens['L'], _, _ = strings.Cut(hostname, ".")
+	}
+
+	return tokens
+}
+
+// expandIncludePath - expands environment variables and '%' tokens in Include directive argument.
+func expandIncludePath(value string) (string, error) {
+	expanded, err := expandEnvironment(value)
+	if err != nil {
+		return "", err
+	}
+
+	if !strings.Contains(expanded, "%") {
+		return expanded, nil
+	}
+
+	return expandTokens(expanded, localTokens())
+}
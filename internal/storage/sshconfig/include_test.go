@@ -0,0 +1,164 @@
+package sshconfig
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func Test_globRecursive(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := []string{
+		"conf.d/a.conf",
+		"conf.d/team/b.conf",
+		"conf.d/team/servers/c.conf",
+		"conf.d/team/servers/readme.txt",
+	}
+	for _, file := range files {
+		path := filepath.Join(tmpDir, file)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte{}, 0o600))
+	}
+
+	confDir := filepath.Join(tmpDir, "conf.d")
+	teamDir := filepath.Join(confDir, "team")
+	serversDir := filepath.Join(teamDir, "servers")
+
+	// "**" matches zero or more folders.
+	matches, folders, err := globRecursive(filepath.Join(confDir, "**", "*.conf"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(confDir, "a.conf"),
+		filepath.Join(teamDir, "b.conf"),
+		filepath.Join(serversDir, "c.conf"),
+	}, matches)
+	require.Equal(t, []string{confDir, teamDir, serversDir}, folders)
+
+	// Trailing "**" matches all files and folders.
+	matches, _, err = globRecursive(filepath.Join(teamDir, "**"))
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		filepath.Join(teamDir, "b.conf"),
+		serversDir,
+		filepath.Join(serversDir, "c.conf"),
+		filepath.Join(serversDir, "readme.txt"),
+	}, matches)
+
+	// Pattern without "**" is resolved by filepath.Glob.
+	matches, folders, err = globRecursive(filepath.Join(confDir, "*.conf"))
+	require.NoError(t, err)
+	require.Equal(t, []string{filepath.Join(confDir, "a.conf")}, matches)
+	require.Empty(t, folders)
+
+	// Base folder does not exist.
+	matches, _, err = globRecursive(filepath.Join(tmpDir, "missing", "**", "*.conf"))
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
+
+func Test_expandEnvironment(t *testing.T) {
+	t.Setenv("GG_TEST_CONF_DIR", "/etc/ssh/conf.d")
+
+	expanded, err := expandEnvironment("${GG_TEST_CONF_DIR}/*.conf")
+	require.NoError(t, err)
+	require.Equal(t, "/etc/ssh/conf.d/*.conf", expanded)
+
+	// Only ${NAME} syntax is supported.
+	expanded, err = expandEnvironment("$GG_TEST_CONF_DIR/*.conf")
+	require.NoError(t, err)
+	require.Equal(t, "$GG_TEST_CONF_DIR/*.conf", expanded)
+
+	_, err = expandEnvironment("${GG_TEST_UNDEFINED}/*.conf")
+	require.ErrorContains(t, err, `environment variable "GG_TEST_UNDEFINED" is not defined`)
+}
+
+func Test_expandTokens(t *testing.T) {
+	tokens := map[byte]string{'d': "/home/user", 'u': "user"}
+
+	expanded, err := expandTokens("%d/.ssh/%u.conf", tokens)
+	require.NoError(t, err)
+	require.Equal(t, "/home/user/.ssh/user.conf", expanded)
+
+	expanded, err = expandTokens("100%%.conf", tokens)
+	require.NoError(t, err)
+	require.Equal(t, "100%.conf", expanded)
+
+	// Host dependent tokens cannot be expanded.
+	_, err = expandTokens("%h.conf", tokens)
+	require.ErrorContains(t, err, "token %h is not supported")
+
+	_, err = expandTokens("config%", tokens)
+	require.Error(t, err)
+}
+
+func TestLexer_Tokenize_IncludeExpansion(t *testing.T) {
+	tmpDir := t.TempDir()
+	nestedDir := filepath.Join(tmpDir, "conf.d", "team")
+	require.NoError(t, os.MkdirAll(nestedDir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "conf.d", "a.conf"), []byte("Host a\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(nestedDir, "b.conf"), []byte("Host b\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "env.conf"), []byte("Host env\n"), 0o600))
+	t.Setenv("GG_TEST_CONF_DIR", tmpDir)
+
+	configPath := filepath.Join(tmpDir, "config")
+	config := `Include conf.d/**/*.conf
+Include ${GG_TEST_CONF_DIR}/env.conf
+Include ${GG_TEST_UNDEFINED}/hosts.conf
+Include %h.conf
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+
+	lex := NewFileLexer(configPath, &mocklogger.Logger{})
+	tokens, err := lex.Tokenize()
+	require.NoError(t, err)
+
+	hosts := []string{}
+	for _, token := range tokens {
+		hosts = append(hosts, token.value)
+	}
+	require.Equal(t, []string{"a", "b", "env"}, hosts)
+	// Nested folders are watched, so that new files are detected.
+	require.Contains(t, lex.GetLocalPaths(), nestedDir)
+
+	messages := []string{}
+	for _, diagnostic := range lex.GetDiagnostics() {
+		messages = append(messages, fmt.Sprintf("%d %s", diagnostic.Line, diagnostic.Message))
+	}
+	require.Equal(t, []string{
+		`3 Cannot expand Include pattern "${GG_TEST_UNDEFINED}/hosts.conf": ` +
+			`environment variable "GG_TEST_UNDEFINED" is not defined`,
+		`4 Cannot expand Include pattern "%h.conf": token %h is not supported`,
+	}, messages)
+}
+
+func TestLexer_Tokenize_RemoteIncludeExpansion(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config":
+			_, _ = w.Write([]byte("Include ${GG_TEST_TEAM}/hosts.conf\nInclude conf.d/*.conf\n"))
+		case "/devops/hosts.conf":
+			_, _ = w.Write([]byte("Host remote\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+	t.Setenv("GG_TEST_TEAM", "devops")
+
+	lex := NewFileLexer(ts.URL+"/config", &mocklogger.Logger{})
+	tokens, err := lex.Tokenize()
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	require.Equal(t, "remote", tokens[0].value)
+
+	// Server does not list files, so patterns cannot be resolved.
+	require.Len(t, lex.GetDiagnostics(), 1)
+	require.Equal(t, "Patterns are not supported in remote Include: conf.d/*.conf", lex.GetDiagnostics()[0].Message)
+}
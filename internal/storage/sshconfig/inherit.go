@@ -0,0 +1,74 @@
+package sshconfig
+
+import (
+	"strings"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+)
+
+// multiValueKeywords - options, which are accumulated from all matching "Host" blocks. For all other
+// options, ssh uses the first obtained value.
+var multiValueKeywords = map[string]bool{
+	"certificatefile": true,
+	"dynamicforward":  true,
+	"identityfile":    true,
+	"localforward":    true,
+	"remoteforward":   true,
+	"sendenv":         true,
+	"setenv":          true,
+}
+
+// setInheritedDirectives - evaluates "Host" blocks in the same way as ssh does and assigns effective
+// options, which are declared in other blocks, for instance "Host *" or "Host *.prod", to the hosts.
+// Host own fields are not changed, otherwise inherited options would be written to the host block.
+func (p *Parser) setInheritedDirectives() {
+	for i, host := range p.foundHosts {
+		own := p.hostBlocks[i]
+		ownKeywords := make(map[string]bool, len(p.blocks[own].Directives))
+		for _, directive := range p.blocks[own].Directives {
+			ownKeywords[strings.ToLower(directive.Keyword)] = true
+		}
+
+		obtained := make(map[string]bool)
+		for index, block := range p.blocks {
+			if index != own && !p.blockApplies(index, host.Title) {
+				continue
+			}
+
+			for _, directive := range block.Directives {
+				keyword := strings.ToLower(directive.Keyword)
+				if obtained[keyword] && !multiValueKeywords[keyword] {
+					continue
+				}
+
+				obtained[keyword] = true
+				if index == own {
+					continue
+				}
+
+				p.foundHosts[i].Inherited = append(p.foundHosts[i].Inherited, model.InheritedDirective{
+					Directive:  directive,
+					Pattern:    block.Patterns,
+					SourcePath: block.SourcePath,
+					Line:       block.Line,
+					Overrides:  ownKeywords[keyword] && !multiValueKeywords[keyword],
+				})
+			}
+		}
+	}
+}
+
+// blockApplies - returns true if the block with the given index applies to the host alias. The first block
+// contains global options, which apply to all hosts.
+func (p *Parser) blockApplies(index int, alias string) bool {
+	if index == 0 {
+		return true
+	}
+
+	patterns, err := splitArguments(p.blocks[index].Patterns)
+	if err != nil {
+		return false
+	}
+
+	return matchHostPatterns(alias, patterns)
+}
@@ -0,0 +1,145 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func Test_matchHostPatterns(t *testing.T) {
+	tests := []struct {
+		alias    string
+		patterns []string
+		want     bool
+	}{
+		{alias: "web1", patterns: []string{"*"}, want: true},
+		{alias: "web1.prod", patterns: []string{"*.prod"}, want: true},
+		{alias: "WEB1.PROD", patterns: []string{"*.prod"}, want: true},
+		{alias: "web1.dev", patterns: []string{"*.prod"}, want: false},
+		{alias: "db.prod", patterns: []string{"*.prod", "!db.prod"}, want: false},
+		{alias: "db.prod", patterns: []string{"!db.prod", "*.prod"}, want: false},
+		{alias: "web1", patterns: []string{"!db*"}, want: false},
+		{alias: "web1", patterns: []string{"web?"}, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.alias, func(t *testing.T) {
+			require.Equal(t, tt.want, matchHostPatterns(tt.alias, tt.patterns))
+		})
+	}
+}
+
+func TestParser_Parse_InheritedDirectives(t *testing.T) {
+	const config = `ServerAliveInterval 60
+
+Host web1.prod
+    HostName 10.0.0.1
+    IdentityFile ~/.ssh/web
+
+Host db.prod
+    HostName 10.0.0.2
+    User postgres
+
+Host *.prod !db.prod
+    User deploy
+    IdentityFile ~/.ssh/prod
+
+Host *
+    User admin
+    ServerAliveInterval 30
+    ForwardAgent no
+
+Host local
+    ForwardAgent yes
+`
+	configPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+
+	parser := NewParser(NewFileLexer(configPath, &mocklogger.Logger{}), &mocklogger.Logger{})
+	hosts, err := parser.Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 3)
+
+	// Global options come first, multi-value options, like IdentityFile, are accumulated.
+	require.Equal(t, []model.InheritedDirective{
+		{
+			Directive:  model.Directive{Keyword: "ServerAliveInterval", Value: "60"},
+			SourcePath: configPath,
+			Line:       1,
+		},
+		{
+			Directive:  model.Directive{Keyword: "User", Value: "deploy"},
+			Pattern:    "*.prod !db.prod",
+			SourcePath: configPath,
+			Line:       11,
+		},
+		{
+			Directive:  model.Directive{Keyword: "IdentityFile", Value: "~/.ssh/prod"},
+			Pattern:    "*.prod !db.prod",
+			SourcePath: configPath,
+			Line:       11,
+		},
+		{
+			Directive:  model.Directive{Keyword: "ForwardAgent", Value: "no"},
+			Pattern:    "*",
+			SourcePath: configPath,
+			Line:       15,
+		},
+	}, hosts[0].Inherited)
+	// Host own fields are not changed.
+	require.Empty(t, hosts[0].LoginName)
+
+	// Negated pattern excludes the block, own value is obtained before "Host *".
+	require.Equal(t, "postgres", hosts[1].LoginName)
+	require.Equal(t, []string{"ServerAliveInterval", "ForwardAgent"}, inheritedKeywords(hosts[1]))
+
+	// "Host *" is declared earlier, so its value overrides the host own one.
+	require.Equal(t, "local", hosts[2].Title)
+	require.Equal(t, []string{"ServerAliveInterval", "User", "ForwardAgent"}, inheritedKeywords(hosts[2]))
+	require.True(t, hosts[2].Inherited[2].Overrides)
+	require.False(t, hosts[2].Inherited[1].Overrides)
+
+	// Wildcard blocks are listed separately, together with global options.
+	patterns := parser.Patterns()
+	require.Len(t, patterns, 3)
+	require.Equal(t, []string{"", "*.prod !db.prod", "*"}, []string{
+		patterns[0].Patterns, patterns[1].Patterns, patterns[2].Patterns,
+	})
+	require.Equal(t, []model.Directive{
+		{Keyword: "User", Value: "deploy"},
+		{Keyword: "IdentityFile", Value: "~/.ssh/prod"},
+	}, patterns[1].Directives)
+}
+
+func TestParser_Patterns_NoGlobalOptions(t *testing.T) {
+	lexer := &mockLexer{
+		tokens: []SSHToken{
+			{kind: tokenKind.Match, value: "all"},
+			{kind: tokenKind.User, value: "nobody", keyword: "User", rawValue: "nobody"},
+			{kind: tokenKind.Host, value: "web1"},
+			{kind: tokenKind.Hostname, value: "web1.com", keyword: "HostName", rawValue: "web1.com"},
+		},
+	}
+	parser := NewParser(lexer, &mocklogger.Logger{})
+	hosts, err := parser.Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+
+	// Options from "Match" blocks are not evaluated.
+	require.Empty(t, hosts[0].Inherited)
+	require.Empty(t, parser.Patterns())
+}
+
+func inheritedKeywords(host model.Host) []string {
+	keywords := make([]string, 0, len(host.Inherited))
+	for _, directive := range host.Inherited {
+		keywords = append(keywords, directive.Keyword)
+	}
+
+	return keywords
+}
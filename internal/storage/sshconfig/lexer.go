@@ -2,12 +2,11 @@ package sshconfig
 
 import (
 	"bufio"
-	"errors"
+	"fmt"
 	"net/url"
 	"os"
 	"path"
 	"path/filepath"
-	"regexp"
 	"strconv"
 	"strings"
 
@@ -21,9 +20,11 @@ const maxFileIncludeDepth = 16
 
 // Lexer is responsible for reading and tokenizing an SSH config file.
 type Lexer struct {
-	rawData    []byte
-	logger     iLogger
-	rootConfig configSource
+	rawData     []byte
+	localPaths  []string
+	diagnostics []Diagnostic
+	logger      iLogger
+	rootConfig  configSource
 }
 
 // NewFileLexer creates a new instance of Lexer for the given SSH config file path.
@@ -51,8 +52,24 @@ func (l *Lexer) GetRawData() []byte {
 	return l.rawData
 }
 
+// GetLocalPaths returns local files which were read during the last Tokenize call and folders which
+// are referenced by Include directives. The app watches these paths to detect ssh_config changes.
+func (l *Lexer) GetLocalPaths() []string {
+	return l.localPaths
+}
+
+// GetDiagnostics returns problems which were found during the last Tokenize call, for instance
+// values which were rejected or Include directives which did not match any file.
+func (l *Lexer) GetDiagnostics() []Diagnostic {
+	return l.diagnostics
+}
+
 // Tokenize reads the SSH config file and returns a slice of tokens representing the contents.
 func (l *Lexer) Tokenize() ([]SSHToken, error) {
+	// Tokenize can be called more than once, for instance when ssh_config is modified by the app.
+	l.rawData = []byte{}
+	l.localPaths = []string{}
+	l.diagnostics = []Diagnostic{}
 	tokens, err := l.loadFromDataSource(l.rootConfig, []SSHToken{}, 0)
 	if sshconfig.IsUserDefinedPath() && err != nil {
 		// That's a bit hacky. If user explicitly set ssh/config file path via env var or CLI flag
@@ -71,41 +88,54 @@ func (l *Lexer) loadFromDataSource(
 ) ([]SSHToken, error) {
 	currentDepth++
 	if currentDepth > maxFileIncludeDepth {
-		l.logger.Error("[SSHCONFIG] Max include depth reached")
+		l.report(src.includedAt, "Max include depth reached")
 
 		return children, nil
 	}
 
-	l.logger.Info("[SSHCONFIG] Load file: %s", src.value)
+	// URL may contain a password.
+	l.logger.Info("[SSHCONFIG] Load file: %s", utils.RedactURL(src.value))
+	if src.valueType == valueTypeFile {
+		// Even if the file cannot be opened, it should be watched, as it can be created later.
+		l.addLocalPath(src.value)
+	}
+
 	rdr, err := newReader(src.value, src.valueType)
 	if err != nil {
-		l.logger.Error("[SSHCONFIG] Error opening file %s: %+v", src.value, err)
+		// Root file problems relate to the file itself, included ones are reported at Include directive.
+		errorPosition := lo.Ternary(src.includedAt.source == "", position{source: src.value}, src.includedAt)
+		l.report(errorPosition, "Error opening file %s: %v", utils.RedactURL(src.value), err)
 		return nil, err
 	}
 
 	defer func() {
 		if closeErr := rdr.Close(); closeErr != nil {
-			l.logger.Error("[SSHCONFIG] Error closing file %s: %v", src.value, closeErr)
+			l.logger.Error("[SSHCONFIG] Error closing file %s: %v", utils.RedactURL(src.value), closeErr)
 		}
 	}()
 
 	scanner := bufio.NewScanner(rdr)
+	lineNumber := 0
 
 	for scanner.Scan() {
+		lineNumber++
 		line := scanner.Text()
 		if shouldSkipLine(line) {
 			continue
 		}
 
-		line = stripInlineComments(line)
+		line = strings.TrimRight(line, " \t")
 		token := l.readToken(line)
+		token.source = src.value
+		token.line = lineNumber
+		if !utils.StringEmpty(&token.problem) {
+			l.report(token.position(), "%s", token.problem)
+		}
 
 		if token.kind == tokenKind.IncludeFile {
-			for _, includeToken := range l.handleIncludeToken(token, src) {
-				children, err = l.loadFromDataSource(includeToken, children, currentDepth)
-				if err != nil {
-					return children, err
-				}
+			children, err = l.includeFiles(token, src, children, currentDepth)
+			if err != nil {
+				return children, err
 			}
 
 			continue
@@ -118,131 +148,135 @@ func (l *Lexer) loadFromDataSource(
 	}
 
 	if err = scanner.Err(); err != nil {
-		// Ideally, should add a line number which is failing to the error message
-		l.logger.Error("[SSHCONFIG] Error reading file %+v", err)
+		// The line which cannot be read follows the last one which was read successfully.
+		l.report(position{source: src.value, line: lineNumber + 1}, "Error reading file %v", err)
 	}
 
 	return children, err
 }
 
-func (l *Lexer) readToken(line string) SSHToken {
-	line = strings.TrimSpace(line)
-
-	var token SSHToken
-	switch {
-	case matchToken(line, "User"):
-		token = l.usernameToken(line)
-	case matchToken(line, "HostName"):
-		token = l.hostnameToken(line)
-	case matchToken(line, "Host"):
-		token = l.hostToken(line)
-	case matchToken(line, "Port"):
-		token = l.networkPortToken(line)
-	case matchToken(line, "Include"):
-		token = l.keyValuesToken(tokenKind.IncludeFile, line)
-	case matchToken(line, "IdentityFile"):
-		token = l.identityFileToken(line)
-	case matchToken(line, "# GG:GROUP"):
-		token = l.metaDataToken(tokenKind.Group, line)
-	case matchToken(line, "# GG:DESCRIPTION"):
-		token = l.metaDataToken(tokenKind.Description, line)
-	default:
-		token = SSHToken{kind: tokenKind.Unsupported}
-	}
-	return token
-}
-
-func shouldSkipLine(line string) bool {
-	trimmedLine := strings.TrimSpace(line)
-	if utils.StringEmpty(&trimmedLine) {
-		return true
+// includeFiles - loads files which are listed in "Include" directive, each argument is a path or a mask.
+func (l *Lexer) includeFiles(
+	token SSHToken,
+	parent configSource,
+	children []SSHToken,
+	currentDepth int,
+) ([]SSHToken, error) {
+	patterns, err := splitArguments(token.value)
+	if err != nil {
+		l.report(token.position(), "Cannot parse Include directive %q: %v", token.value, err)
+		return children, nil
 	}
 
-	if strings.HasPrefix(trimmedLine, "# GG:") {
-		// This is a metadata comments, which should be processed
-		return false
-	}
+	for _, pattern := range patterns {
+		// Environment variables and '%' tokens are expanded before the path is resolved, so that
+		// they can be used in local and remote includes, for instance "Include ${HOME}/.ssh/conf.d/*".
+		token.value, err = expandIncludePath(pattern)
+		if err != nil {
+			l.report(token.position(), "Cannot expand Include pattern %q: %v", pattern, err)
+			continue
+		}
 
-	if strings.HasPrefix(trimmedLine, "#") {
-		return true
+		for _, includeSource := range l.handleIncludeToken(token, parent) {
+			includeSource.includedAt = token.position()
+			children, err = l.loadFromDataSource(includeSource, children, currentDepth)
+			if err != nil {
+				return children, err
+			}
+		}
 	}
 
-	return false
+	return children, nil
 }
 
-func stripInlineComments(line string) string {
-	line = strings.TrimRight(line, " \t")
+// readToken - converts ssh_config line into a token. Keywords are case-insensitive, as in OpenSSH.
+func (l *Lexer) readToken(line string) SSHToken {
+	line = strings.TrimSpace(line)
 
-	if strings.Contains(line, "# GG:") {
-		return line
+	if strings.HasPrefix(line, metadataPrefix) {
+		return l.metaDataToken(line)
 	}
 
-	parts := strings.Split(line, "#")
-	if len(parts) > 0 {
-		return parts[0]
+	d, err := parseDirective(line)
+	if err != nil {
+		l.logger.Debug("[SSHCONFIG] Cannot parse line %q: %v", line, err)
+		return SSHToken{kind: tokenKind.Unsupported, problem: fmt.Sprintf("Cannot parse line: %v", err)}
 	}
 
-	return line
-}
+	var token SSHToken
+	switch strings.ToLower(d.keyword) {
+	case "user":
+		token = l.usernameToken(d)
+	case "hostname":
+		token = l.hostnameToken(d)
+	case "host":
+		token = l.keyValuesToken(tokenKind.Host, d, line)
+	case "match":
+		token = l.keyValuesToken(tokenKind.Match, d, line)
+	case "port":
+		token = l.networkPortToken(d)
+	case "include":
+		token = l.keyValuesToken(tokenKind.IncludeFile, d, line)
+	case "identityfile":
+		token = l.identityFileToken(d)
+	default:
+		token = SSHToken{kind: tokenKind.Directive}
+	}
 
-func matchToken(line, token string) bool {
-	// Remove leading and trailing whitespace: "  User root" -> "User root"
-	line = strings.TrimSpace(line)
-	if utils.StringEmpty(&line) {
-		return false
+	// Directives without arguments are not valid. Directives with invalid values are still kept,
+	// so that the user can see them, but they're not assigned to host fields.
+	if len(d.args) == 0 {
+		return SSHToken{kind: tokenKind.Unsupported, problem: fmt.Sprintf("Missing argument for %s", d.keyword)}
 	}
 
-	// If token prefix is longer than the line itself, it can't match.
-	if len(token) >= len(line) {
-		return false
+	if token.kind == tokenKind.Unsupported {
+		token = SSHToken{kind: tokenKind.Directive, problem: token.problem + ", the value is ignored"}
 	}
 
-	if !isTokenFollowedDelimiter(line, token) {
-		return false
+	token.keyword = d.keyword
+	token.rawValue = d.value(line)
+	if token.kind == tokenKind.Directive {
+		token.value = token.rawValue
 	}
 
-	return strings.HasPrefix(strings.ToLower(line), strings.ToLower(token))
+	return token
 }
 
-func isTokenFollowedDelimiter(line, token string) bool {
-	prefixLen := len(token)
-	delimiters := []byte{' ', '\t'}
+func shouldSkipLine(line string) bool {
+	trimmedLine := strings.TrimSpace(line)
+	if utils.StringEmpty(&trimmedLine) {
+		return true
+	}
 
-	// Should support metadata token which ends with space or colon.
-	// For instance "# GG:GROUP value" or "# GG:GROUP: value" are valid
-	if strings.HasPrefix(line, "# GG:") {
-		delimiters = append(delimiters, ':')
+	if strings.HasPrefix(trimmedLine, "# GG:") {
+		// This is a metadata comments, which should be processed
+		return false
 	}
 
-	// Check if token is followed one of the delimiters. For example,
-	// "User root" and "User\troot" are valid, but "User=root" is not.
-	_, found := lo.Find(delimiters, func(d byte) bool {
-		return line[prefixLen] == d
-	})
+	if strings.HasPrefix(trimmedLine, "#") {
+		return true
+	}
 
-	return found
+	return false
 }
 
-func (l *Lexer) hostToken(line string) SSHToken {
-	value, err := parseKeyValuesLine(line)
-	if err != nil {
-		return SSHToken{kind: tokenKind.Unsupported}
+// singleArgument - returns the only argument of the directive. As in OpenSSH, extra arguments are an error.
+func singleArgument(d directive) (string, error) {
+	if len(d.args) != 1 {
+		return "", fmt.Errorf("%s expects a single argument", d.keyword)
 	}
 
-	return SSHToken{
-		kind:  tokenKind.Host,
-		value: value,
-	}
+	return d.args[0], nil
 }
 
-func (l *Lexer) usernameToken(line string) SSHToken {
-	value, err := parseKeyValuesLine(line)
+func (l *Lexer) usernameToken(d directive) SSHToken {
+	value, err := singleArgument(d)
 	if err != nil {
-		return SSHToken{kind: tokenKind.Unsupported}
+		return SSHToken{kind: tokenKind.Unsupported, problem: err.Error()}
 	}
 
 	if !sshUsernameRegex.MatchString(value) {
-		return SSHToken{kind: tokenKind.Unsupported}
+		return SSHToken{kind: tokenKind.Unsupported, problem: fmt.Sprintf("Invalid user name %q", value)}
 	}
 
 	return SSHToken{
@@ -251,41 +285,36 @@ func (l *Lexer) usernameToken(line string) SSHToken {
 	}
 }
 
-const maxHostnameLength = 253
-
-func (l *Lexer) hostnameToken(line string) SSHToken {
-	value, err := parseKeyValuesLine(line)
+func (l *Lexer) hostnameToken(d directive) SSHToken {
+	value, err := singleArgument(d)
 	if err != nil {
-		return SSHToken{kind: tokenKind.Unsupported}
+		return SSHToken{kind: tokenKind.Unsupported, problem: err.Error()}
 	}
 
-	if len(value) > maxHostnameLength {
-		return SSHToken{kind: tokenKind.Unsupported}
+	if len(value) > utils.MaxHostnameLength {
+		return SSHToken{kind: tokenKind.Unsupported, problem: "Hostname is too long"}
 	}
 
-	if !hostnameRegex.MatchString(value) {
-		return SSHToken{kind: tokenKind.Unsupported}
+	if !utils.IsValidHostAddress(value) {
+		return SSHToken{kind: tokenKind.Unsupported, problem: fmt.Sprintf("Invalid hostname %q", value)}
 	}
 
+	// IPv6 address can be written in square brackets, ssh expects the address without them.
 	return SSHToken{
 		kind:  tokenKind.Hostname,
-		value: value,
+		value: utils.UnbracketAddress(value),
 	}
 }
 
-func (l *Lexer) networkPortToken(line string) SSHToken {
-	value, err := parseKeyValuesLine(line)
+func (l *Lexer) networkPortToken(d directive) SSHToken {
+	value, err := singleArgument(d)
 	if err != nil {
-		return SSHToken{kind: tokenKind.Unsupported}
+		return SSHToken{kind: tokenKind.Unsupported, problem: err.Error()}
 	}
 
 	networkPort, err := strconv.ParseInt(value, 10, 32)
-	if err != nil {
-		return SSHToken{kind: tokenKind.Unsupported}
-	}
-
-	if !isNetworkPortNumberValid(int(networkPort)) {
-		return SSHToken{kind: tokenKind.Unsupported}
+	if err != nil || !isNetworkPortNumberValid(int(networkPort)) {
+		return SSHToken{kind: tokenKind.Unsupported, problem: fmt.Sprintf("Invalid port %q", value)}
 	}
 
 	return SSHToken{
@@ -294,11 +323,10 @@ func (l *Lexer) networkPortToken(line string) SSHToken {
 	}
 }
 
-func (l *Lexer) identityFileToken(line string) SSHToken {
-	trimmedLine := strings.TrimSpace(line)
-	value, err := parseKeyValuesLine(trimmedLine)
+func (l *Lexer) identityFileToken(d directive) SSHToken {
+	value, err := singleArgument(d)
 	if err != nil {
-		return SSHToken{kind: tokenKind.Unsupported}
+		return SSHToken{kind: tokenKind.Unsupported, problem: err.Error()}
 	}
 
 	return SSHToken{
@@ -314,31 +342,40 @@ func (l *Lexer) handleIncludeToken(token SSHToken, parent configSource) []config
 		// If path starts from tilde, we load the included file from the local file system.
 		// This allows to set some user default values, even if config is stored remotely.
 		expandedPath := l.expandTildePath(token.value)
-		return l.includeLocalFileToken(expandedPath, parent)
+		return l.includeLocalFileToken(expandedPath, token, parent)
 	case parent.valueType == valueTypeURL:
-		return l.includeRemoteFileToken(token.value, parent)
+		return l.includeRemoteFileToken(token, parent)
 	default:
-		return l.includeLocalFileToken(token.value, parent)
+		return l.includeLocalFileToken(token.value, token, parent)
 	}
 }
 
-func (l *Lexer) includeLocalFileToken(localPath string, parent configSource) []configSource {
+func (l *Lexer) includeLocalFileToken(localPath string, token SSHToken, parent configSource) []configSource {
 	sources := []configSource{}
 
 	if !filepath.IsAbs(localPath) {
 		localPath = filepath.Join(filepath.Dir(parent.value), localPath)
 	}
 
-	// filepath.Glob does not support recursive globbing (**), like ./conf.d/**/*.conf,
-	// currently can only use simple mask like ./conf.d/config_*.conf.
-	matches, err := filepath.Glob(localPath)
+	// Watch the folder, so that the app can detect when files which match the pattern are added or removed.
+	if folder := filepath.Dir(localPath); !containsGlobPattern(folder) {
+		l.addLocalPath(folder)
+	}
+
+	// Pattern can contain "**" segments, which match nested folders, like ./conf.d/**/*.conf.
+	isRecursive := hasGlobStar(localPath)
+	matches, folders, err := globRecursive(localPath)
 	if err != nil {
-		l.logger.Error("[SSHCONFIG] Cannot process Include pattern %s: %v", localPath, err)
+		l.report(token.position(), "Cannot process Include pattern %s: %v", localPath, err)
 		return sources
 	}
 
+	for _, folder := range folders {
+		l.addLocalPath(folder)
+	}
+
 	if len(matches) == 0 {
-		l.logger.Error("[SSHCONFIG] No files match Include pattern: %s", localPath)
+		l.report(token.position(), "No files match Include pattern: %s", localPath)
 		return sources
 	}
 
@@ -346,17 +383,22 @@ func (l *Lexer) includeLocalFileToken(localPath string, parent configSource) []c
 		var info os.FileInfo
 		info, err = os.Stat(path)
 		if err != nil {
-			l.logger.Error("[SSHCONFIG] Cannot access file %s: %v", path, err)
+			l.report(token.position(), "Cannot access file %s: %v", path, err)
 			continue
 		}
 
 		if info.IsDir() {
-			l.logger.Error("[SSHCONFIG] Path is a directory: %s", path)
+			if isRecursive {
+				// "**" matches folders too, they are not included files.
+				continue
+			}
+
+			l.report(token.position(), "Path is a directory: %s", path)
 			continue
 		}
 
 		if !isTextFileMime(path) {
-			l.logger.Error("[SSHCONFIG] Not a text file: %s", path)
+			l.report(token.position(), "Not a text file: %s", path)
 			continue
 		}
 
@@ -369,6 +411,23 @@ func (l *Lexer) includeLocalFileToken(localPath string, parent configSource) []c
 	return sources
 }
 
+// report - logs the problem and records it, so that it can be displayed by "lint" command.
+func (l *Lexer) report(pos position, format string, args ...any) {
+	diagnostic := pos.diagnostic(Severity.Error, format, args...)
+	l.logger.Error("[SSHCONFIG] %s", diagnostic.Message)
+	l.diagnostics = append(l.diagnostics, diagnostic)
+}
+
+func (l *Lexer) addLocalPath(localPath string) {
+	if !lo.Contains(l.localPaths, localPath) {
+		l.localPaths = append(l.localPaths, localPath)
+	}
+}
+
+func containsGlobPattern(localPath string) bool {
+	return strings.ContainsAny(localPath, "*?[")
+}
+
 func (l *Lexer) expandTildePath(localPath string) string {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -393,7 +452,14 @@ func (l *Lexer) expandTildePath(localPath string) string {
 	return filepath.Join(home, rest)
 }
 
-func (l *Lexer) includeRemoteFileToken(remotePath string, parent configSource) []configSource {
+func (l *Lexer) includeRemoteFileToken(token SSHToken, parent configSource) []configSource {
+	remotePath := token.value
+	if containsGlobPattern(remotePath) {
+		// HTTP server does not provide a list of files, so patterns cannot be resolved.
+		l.report(token.position(), "Patterns are not supported in remote Include: %s", utils.RedactURL(remotePath))
+		return []configSource{}
+	}
+
 	if utils.IsSupportedURL(remotePath) {
 		// If remotePath is already a full URL, use it as is.
 		return []configSource{{
@@ -426,7 +492,7 @@ func (l *Lexer) includeRemoteFileToken(remotePath string, parent configSource) [
 	}
 
 	if err != nil {
-		l.logger.Error("[SSHCONFIG]: Cannot parse resource URL: %v", err)
+		l.report(token.position(), "Cannot parse resource URL: %v", err)
 		return []configSource{}
 	}
 
@@ -436,49 +502,33 @@ func (l *Lexer) includeRemoteFileToken(remotePath string, parent configSource) [
 	}}
 }
 
-func (l *Lexer) metaDataToken(kind tokenEnum, line string) SSHToken {
-	line = strings.TrimSpace(line)
-	line, tokenFound := strings.CutPrefix(line, "# GG:")
-	if !tokenFound {
-		return SSHToken{kind: tokenKind.Unsupported}
+func (l *Lexer) metaDataToken(line string) SSHToken {
+	// Metadata value is a free text, it's not split into arguments.
+	name, value, err := parseMetadata(line)
+	if err != nil {
+		return SSHToken{kind: tokenKind.Unsupported, problem: fmt.Sprintf("Cannot read metadata: %v", err)}
 	}
 
-	return l.keyValuesToken(kind, line)
+	return SSHToken{
+		kind:    tokenKind.Metadata,
+		metaTag: name,
+		value:   value,
+	}
 }
 
-func (l *Lexer) keyValuesToken(kind tokenEnum, line string) SSHToken {
-	value, err := parseKeyValuesLine(line)
-	if err != nil {
+// keyValuesToken - returns a token, which value contains all arguments as they're written in the line,
+// for instance "web1 web1.internal" or "host *.internal exec \"test -f /tmp/vpn\"".
+func (l *Lexer) keyValuesToken(kind tokenEnum, d directive, line string) SSHToken {
+	if len(d.args) == 0 {
 		return SSHToken{kind: tokenKind.Unsupported}
 	}
 
 	return SSHToken{
 		kind:  kind,
-		value: value,
+		value: d.value(line),
 	}
 }
 
 func startsWithTilde(s string) bool {
 	return strings.HasPrefix(s, "~/") || strings.HasPrefix(s, "~\\")
 }
-
-/*
-Regex to match exactly two or more words.
-
-"hello world",     // Valid.
-"  foo   bar  ",   // Valid.
-"oneword",         // Invalid.
-"three word test", // Valid.
-"",                // Invalid.
-*/
-var twoWordsRegex = regexp.MustCompile(`^(\S+)\s+(.+)$`)
-
-func parseKeyValuesLine(line string) (string, error) {
-	matches := twoWordsRegex.FindStringSubmatch(line)
-	// Ideally it should be a loop, not regex.
-	if len(matches) > 1 {
-		return matches[2], nil
-	}
-
-	return "", errors.New("not a key value string")
-}
@@ -57,6 +57,7 @@ func TestLexer_LoadFromDataSource(t *testing.T) {
 	const config = `
 Host test
     # Just a comment
+    # GG:ID mock_id
     # GG:GROUP mock_group
     # GG:DESCRIPTION mock_description
 		Unsupported
@@ -78,22 +79,26 @@ Host test
 
 	wantKinds := []tokenEnum{
 		tokenKind.Host,
-		tokenKind.Group,
-		tokenKind.Description,
+		tokenKind.Metadata,
+		tokenKind.Metadata,
+		tokenKind.Metadata,
 		tokenKind.Hostname,
 		tokenKind.User,
 		tokenKind.NetworkPort,
 		tokenKind.IdentityFile,
+		tokenKind.Directive,
 	}
 
 	wantValues := []string{
 		"test",
+		"mock_id",
 		"mock_group",
 		"mock_description",
 		"example.com",
 		"alice",
 		"2222",
 		"~/.ssh/id_rsa",
+		"+ssh-dss,ssh-rsa",
 	}
 
 	if len(tokens) != len(wantKinds) {
@@ -126,12 +131,135 @@ Host test
 	}
 	tokens, _ := lex.loadFromDataSource(rootConfig, nil, 0)
 
-	if len(tokens) != 1 {
-		t.Fatalf("expected 1 token, got %d", len(tokens))
+	if len(tokens) != 2 {
+		t.Fatalf("expected 2 tokens, got %d", len(tokens))
 	}
 	if tokens[0].kind != tokenKind.Host {
 		t.Errorf("expected Host token, got %v", tokens[0].kind)
 	}
+	// Directives which are not mapped to host fields are preserved as they're written in the file.
+	if tokens[1].kind != tokenKind.Directive || tokens[1].keyword != "UnknownKey" || tokens[1].value != "value" {
+		t.Errorf("expected Directive token 'UnknownKey value', got %v", tokens[1])
+	}
+}
+
+func TestLexer_LoadFromDataSource_Match(t *testing.T) {
+	const config = `
+Host web1 web1.internal
+    HostName 10.0.0.1
+Match host *.internal user root
+    Port 2222
+`
+	rootConfig := configSource{
+		value:     config,
+		valueType: valueTypeRaw,
+	}
+	lex := &Lexer{
+		rootConfig: rootConfig,
+		logger:     &mocklogger.Logger{},
+	}
+	tokens, _ := lex.loadFromDataSource(rootConfig, nil, 0)
+
+	require.Len(t, tokens, 4)
+	require.Equal(t, tokenKind.Host, tokens[0].kind)
+	require.Equal(t, "web1 web1.internal", tokens[0].value)
+	require.Equal(t, tokenKind.Match, tokens[2].kind)
+	require.Equal(t, "host *.internal user root", tokens[2].value)
+}
+
+func TestLexer_LoadFromDataSource_KeyValueSyntax(t *testing.T) {
+	const config = `
+host=test
+    HOSTNAME = example.com
+    user=alice
+    Port= 2222
+    IdentityFile "~/My Keys/id_ed25519" # comment
+    IdentityFile ~/.ssh/id_rsa ~/.ssh/id_dsa
+    User "unterminated
+`
+	rootConfig := configSource{
+		value:     config,
+		valueType: valueTypeRaw,
+	}
+	lex := &Lexer{
+		rootConfig: rootConfig,
+		logger:     &mocklogger.Logger{},
+	}
+	tokens, _ := lex.loadFromDataSource(rootConfig, nil, 0)
+
+	// Values are unquoted, raw values are kept as they're written in the file. IdentityFile with several
+	// arguments is not assigned to the host, and malformed lines are not supported.
+	want := []SSHToken{
+		{kind: tokenKind.Host, value: "test", line: 2, keyword: "host", rawValue: "test"},
+		{kind: tokenKind.Hostname, value: "example.com", line: 3, keyword: "HOSTNAME", rawValue: "example.com"},
+		{kind: tokenKind.User, value: "alice", line: 4, keyword: "user", rawValue: "alice"},
+		{kind: tokenKind.NetworkPort, value: "2222", line: 5, keyword: "Port", rawValue: "2222"},
+		{
+			kind:     tokenKind.IdentityFile,
+			value:    "~/My Keys/id_ed25519",
+			line:     6,
+			keyword:  "IdentityFile",
+			rawValue: `"~/My Keys/id_ed25519"`,
+		},
+		{
+			kind:     tokenKind.Directive,
+			value:    "~/.ssh/id_rsa ~/.ssh/id_dsa",
+			line:     7,
+			keyword:  "IdentityFile",
+			rawValue: "~/.ssh/id_rsa ~/.ssh/id_dsa",
+			problem:  "IdentityFile expects a single argument, the value is ignored",
+		},
+	}
+	for i := range want {
+		want[i].source = config
+	}
+	require.Equal(t, want, tokens)
+}
+
+func TestLexer_Tokenize_Diagnostics(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config")
+	binaryPath := filepath.Join(tmpDir, "binary")
+	loopPath := filepath.Join(tmpDir, "loop")
+	config := fmt.Sprintf(`Host test
+    User invalid!user
+    Port
+    IdentityFile "unterminated
+Include %s/conf.d/*.conf
+Include %s
+Include %s
+`, tmpDir, binaryPath, loopPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(config), 0o600))
+	require.NoError(t, os.WriteFile(binaryPath, []byte{0x00, 0x01, 0x02}, 0o600))
+	// The file includes itself.
+	require.NoError(t, os.WriteFile(loopPath, []byte("Include "+loopPath+"\n"), 0o600))
+
+	lex := NewFileLexer(configPath, &mocklogger.Logger{})
+	_, err := lex.Tokenize()
+	require.NoError(t, err)
+
+	messages := []string{}
+	for _, diagnostic := range lex.GetDiagnostics() {
+		require.Equal(t, Severity.Error, diagnostic.Severity)
+		messages = append(messages, fmt.Sprintf("%s:%d %s", filepath.Base(diagnostic.Source), diagnostic.Line,
+			diagnostic.Message))
+	}
+
+	require.Equal(t, []string{
+		`config:2 Invalid user name "invalid!user", the value is ignored`,
+		"config:3 Missing argument for Port",
+		"config:4 Cannot parse line: unterminated quoted string",
+		fmt.Sprintf("config:5 No files match Include pattern: %s/conf.d/*.conf", tmpDir),
+		fmt.Sprintf("config:6 Not a text file: %s", binaryPath),
+		// Depth overflow is reported at Include directive, which exceeds the limit.
+		"loop:1 Max include depth reached",
+	}, messages)
+
+	// Diagnostics are reset when the file is read again.
+	require.NoError(t, os.WriteFile(configPath, []byte("Host test\n"), 0o600))
+	_, err = lex.Tokenize()
+	require.NoError(t, err)
+	require.Empty(t, lex.GetDiagnostics())
 }
 
 func TestLexer_LoadFromDataSource_InvalidUser(t *testing.T) {
@@ -147,11 +275,42 @@ User invalid!user
 		logger:     &mocklogger.Logger{},
 	}
 	tokens, _ := lex.loadFromDataSource(rootConfig, nil, 0)
-	if len(tokens) != 0 {
-		t.Errorf("expected 0 tokens for invalid user, got %d", len(tokens))
+	// Invalid value is not assigned to the host, but the directive is preserved.
+	if len(tokens) != 1 || tokens[0].kind != tokenKind.Directive {
+		t.Errorf("expected a single Directive token for invalid user, got %v", tokens)
 	}
 }
 
+func TestLexer_LoadFromDataSource_IPv6(t *testing.T) {
+	const config = `
+HostName 2001:db8::10
+HostName [2001:db8::10]
+HostName fe80::1%eth0
+HostName 192.168.0.1
+HostName 2001:db8::10::1
+`
+	rootConfig := configSource{
+		value:     config,
+		valueType: valueTypeRaw,
+	}
+	lex := &Lexer{
+		rootConfig: rootConfig,
+		logger:     &mocklogger.Logger{},
+	}
+	tokens, _ := lex.loadFromDataSource(rootConfig, nil, 0)
+	require.Len(t, tokens, 5)
+
+	// Square brackets are removed, because ssh expects IPv6 address without them.
+	values := []string{}
+	for _, token := range tokens[:4] {
+		require.Equal(t, tokenKind.Hostname, token.kind)
+		values = append(values, token.value)
+	}
+	require.Equal(t, []string{"2001:db8::10", "2001:db8::10", "fe80::1%eth0", "192.168.0.1"}, values)
+	require.Equal(t, tokenKind.Directive, tokens[4].kind)
+	require.Equal(t, `Invalid hostname "2001:db8::10::1", the value is ignored`, tokens[4].problem)
+}
+
 func TestLexer_LoadFromDataSource_InvalidPort(t *testing.T) {
 	const config = `
 Port notaport
@@ -165,8 +324,9 @@ Port notaport
 		logger:     &mocklogger.Logger{},
 	}
 	tokens, _ := lex.loadFromDataSource(rootConfig, nil, 0)
-	if len(tokens) != 0 {
-		t.Errorf("expected 0 tokens for invalid port, got %d", len(tokens))
+	// Invalid value is not assigned to the host, but the directive is preserved.
+	if len(tokens) != 1 || tokens[0].kind != tokenKind.Directive {
+		t.Errorf("expected a single Directive token for invalid port, got %v", tokens)
 	}
 }
 
@@ -307,6 +467,27 @@ func TestLexer_LoadFromDataSource_IncludeFile_UsingMask(t *testing.T) {
 	require.Len(t, tokens, 2, "expected 2 tokens for included hosts")
 }
 
+func TestLexer_GetLocalPaths(t *testing.T) {
+	// Included files and folders which contain them should be watched, so that
+	// the app can reload hosts when a file is modified, added or removed. Files which
+	// do not exist yet are detected through the folder modification time.
+	tmpDir := t.TempDir()
+	confDir := filepath.Join(tmpDir, "conf.d")
+	require.NoError(t, os.Mkdir(confDir, 0o755))
+
+	parentConfig := filepath.Join(tmpDir, "config")
+	includedConfig := filepath.Join(confDir, "included.conf")
+	missingConfig := filepath.Join(tmpDir, "missing.conf")
+	content := fmt.Sprintf("Include conf.d/*.conf\nInclude %s\n", missingConfig)
+	require.NoError(t, os.WriteFile(parentConfig, []byte(content), 0o644))
+	require.NoError(t, os.WriteFile(includedConfig, []byte("Host mock-included-host\n"), 0o644))
+
+	lex := NewFileLexer(parentConfig, &mocklogger.Logger{})
+	_, err := lex.Tokenize()
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{parentConfig, confDir, includedConfig, tmpDir}, lex.GetLocalPaths())
+}
+
 func TestLexer_LoadFromDataSource_IncludeDepthLimit(t *testing.T) {
 	// Simulate include depth limit reached by recursive call
 	tmpDir := t.TempDir()
@@ -335,28 +516,6 @@ func TestLexer_LoadFromDataSource_IncludeDepthLimit(t *testing.T) {
 	require.Contains(t, logger.Logs, "[SSHCONFIG] Max include depth reached", "expected log about max include depth")
 }
 
-func Test_matchToken(t *testing.T) {
-	tests := []struct {
-		str   string
-		token string
-		want  bool
-	}{
-		{"Host test", "host", true},
-		{"HOST test", "host", true},
-		{"\tUser alice", "USER", true},
-		{" Port 22", "port", true},
-		{"\tIdentityFile foo", "identityfile", true},
-		{"\tSomethingElse", "host", false},
-		{"\t# GG:GROUP test", "# GG:GROUP", true},
-		{"\t# GG:GROUP: test", "# GG:GROUP", true},
-	}
-	for _, tt := range tests {
-		if got := matchToken(tt.str, tt.token); got != tt.want {
-			t.Errorf("hasPrefixIgnoreCase(%q, %q) = %v, want %v", tt.str, tt.token, got, tt.want)
-		}
-	}
-}
-
 func TestLexer_handleIncludeToken_localFile(t *testing.T) {
 	// Create the included file, this file will be read by handleIncludeToken.
 	tmpDir := t.TempDir()
@@ -495,12 +654,17 @@ func TestLexer_handleIncludeToken_remoteFile(t *testing.T) {
 
 func TestLexer_MetaDataToken(t *testing.T) {
 	lex := &Lexer{}
-	lines := []string{"# GG:GROUP mock_group", "# GG:GROUP: mock_group"}
+	lines := []string{"# GG:GROUP mock_group", "# GG:GROUP: mock_group", "# GG:group:mock_group"}
 	for _, line := range lines {
-		token := lex.metaDataToken(tokenKind.Group, line)
-		require.Equal(t, tokenKind.Group, token.kind, "wrong token kind")
+		token := lex.metaDataToken(line)
+		require.Equal(t, tokenKind.Metadata, token.kind, "wrong token kind")
+		require.Equal(t, "GROUP", token.metaTag, "wrong metadata tag")
 		require.Equal(t, "mock_group", token.value, "wrong token value")
 	}
+
+	token := lex.metaDataToken("# GG:GROUPS mock_group")
+	require.Equal(t, tokenKind.Unsupported, token.kind, "wrong token kind")
+	require.Equal(t, "Cannot read metadata: unknown metadata tag GG:GROUPS", token.problem)
 }
 
 func TestExpandTildePath(t *testing.T) {
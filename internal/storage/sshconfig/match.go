@@ -0,0 +1,150 @@
+package sshconfig
+
+import (
+	"slices"
+	"strings"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// Match criteria which require an argument. See "man ssh_config".
+var matchCriteriaWithArgument = []string{
+	"exec", "host", "localnetwork", "localuser", "originalhost", "sessiontype", "tagged", "user", "version",
+}
+
+// matchMightApply - returns false if "Match" criteria never match the host. The app cannot evaluate
+// criteria which depend on the environment, for instance "exec" or "localuser", they are considered
+// as matching. All criteria must match, as ssh does.
+func matchMightApply(criteria string, host model.Host) bool {
+	fields, err := splitArguments(criteria)
+	if err != nil {
+		return false
+	}
+
+	for i := 0; i < len(fields); i++ {
+		keyword := strings.ToLower(fields[i])
+		negated := strings.HasPrefix(keyword, "!")
+		keyword = strings.TrimPrefix(keyword, "!")
+
+		var argument string
+		if slices.Contains(matchCriteriaWithArgument, keyword) && i+1 < len(fields) {
+			i++
+			argument = fields[i]
+		}
+
+		matched, known := matchCriterion(keyword, argument, host)
+		if known && matched == negated {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchCriterion - returns the result of a single criterion, the second value is false if the
+// criterion cannot be evaluated.
+func matchCriterion(keyword, argument string, host model.Host) (bool, bool) {
+	switch keyword {
+	case "all":
+		return true, true
+	case "host":
+		hostname := host.Address
+		if utils.StringEmpty(&hostname) {
+			hostname = host.Title
+		}
+
+		return matchPatternList(hostname, argument), true
+	case "originalhost":
+		return matchPatternList(host.Title, argument), true
+	case "user":
+		if utils.StringEmpty(&host.LoginName) {
+			// Default user name depends on the environment.
+			return false, false
+		}
+
+		return matchPatternList(host.LoginName, argument), true
+	default:
+		return false, false
+	}
+}
+
+// matchPatternList - matches the value against comma separated list of patterns. The list matches if
+// any pattern matches and none of the negated patterns match, for instance "*.example.com,!db.example.com".
+func matchPatternList(value, patterns string) bool {
+	value = strings.ToLower(value)
+	matched := false
+	for _, pattern := range strings.Split(strings.ToLower(patterns), ",") {
+		negated := strings.HasPrefix(pattern, "!")
+		if !matchPattern(value, strings.TrimPrefix(pattern, "!")) {
+			continue
+		}
+
+		if negated {
+			return false
+		}
+		matched = true
+	}
+
+	return matched
+}
+
+// matchHostPatterns - returns true if "Host" block applies to the host alias. The block applies if any of
+// its patterns matches the alias and none of the negated patterns does, for instance "*.prod !db.prod".
+func matchHostPatterns(alias string, patterns []string) bool {
+	alias = strings.ToLower(alias)
+	matched := false
+	for _, pattern := range patterns {
+		negated := strings.HasPrefix(pattern, "!")
+		if !matchPattern(alias, strings.ToLower(strings.TrimPrefix(pattern, "!"))) {
+			continue
+		}
+
+		if negated {
+			return false
+		}
+		matched = true
+	}
+
+	return matched
+}
+
+// matchPattern - matches the value against ssh_config pattern, where '*' matches zero or more
+// characters and '?' matches exactly one character.
+func matchPattern(value, pattern string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			pattern = strings.TrimLeft(pattern, "*")
+			if pattern == "" {
+				return true
+			}
+
+			for i := range len(value) + 1 {
+				if matchPattern(value[i:], pattern) {
+					return true
+				}
+			}
+
+			return false
+		case '?':
+			if value == "" {
+				return false
+			}
+		default:
+			if value == "" || value[0] != pattern[0] {
+				return false
+			}
+		}
+
+		value = value[1:]
+		pattern = pattern[1:]
+	}
+
+	return value == ""
+}
+
+// isPattern - returns true if Host value is a pattern, rather than a host alias.
+func isPattern(value string) bool {
+	return strings.ContainsAny(value, "*?!")
+}
@@ -0,0 +1,53 @@
+package sshconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+)
+
+func Test_matchPattern(t *testing.T) {
+	require.True(t, matchPattern("web1.internal", "*.internal"))
+	require.True(t, matchPattern("web1", "web?"))
+	require.True(t, matchPattern("web1", "*"))
+	require.False(t, matchPattern("web10", "web?"))
+	require.False(t, matchPattern("db.internal", "web*"))
+}
+
+func Test_matchPatternList(t *testing.T) {
+	require.True(t, matchPatternList("web1.internal", "db*,*.internal"))
+	require.False(t, matchPatternList("db.internal", "*.internal,!db.internal"))
+	require.False(t, matchPatternList("web1", "!db"))
+}
+
+func Test_matchMightApply(t *testing.T) {
+	host := model.Host{Title: "web1", Address: "web1.internal", LoginName: "root"}
+
+	tests := []struct {
+		criteria string
+		want     bool
+	}{
+		{"all", true},
+		{"host *.internal", true},
+		{"host *.external", false},
+		{"originalhost web*", true},
+		{"!originalhost web*", false},
+		{"host *.internal user admin", false},
+		{"user root,admin", true},
+		// Criteria which depend on the environment might match.
+		{`exec "test -f /tmp/vpn"`, true},
+		{"localuser alice host *.internal", true},
+		{"localuser alice host *.external", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.criteria, func(t *testing.T) {
+			require.Equal(t, tt.want, matchMightApply(tt.criteria, host))
+		})
+	}
+
+	// Default user name depends on the environment.
+	require.True(t, matchMightApply("user admin", model.Host{Title: "web1"}))
+}
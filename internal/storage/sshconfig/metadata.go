@@ -0,0 +1,154 @@
+package sshconfig
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+)
+
+// metadataPrefix starts a comment, which contains goto specific host data, for instance
+// "# GG:GROUP Production". ssh ignores such comments, so they can be stored in ssh_config.
+const metadataPrefix = "# GG:"
+
+type metadataValueEnum int
+
+const (
+	// metadataValueRequired - the tag must have a value, for instance "# GG:GROUP Production".
+	metadataValueRequired metadataValueEnum = iota
+	// metadataValueOptional - the value can be empty, for instance an empty line in "# GG:NOTE".
+	metadataValueOptional
+	// metadataValueNone - the tag is a flag, for instance "# GG:PIN".
+	metadataValueNone
+)
+
+// metadataTag describes a '# GG:' tag and maps its value onto the host model. normalize validates
+// the value and converts it to the form, which is stored in the host model.
+type metadataTag struct {
+	value     metadataValueEnum
+	normalize func(value string) (string, error)
+	apply     func(host *model.Host, value string)
+}
+
+// metadataTags - all tags, which are recognized in ssh_config host blocks. Tags are case-insensitive.
+// See "docs/SSH_CONFIG.md" for the description.
+var metadataTags = map[string]metadataTag{
+	"ID": {
+		apply: func(host *model.Host, value string) { host.ID = value },
+	},
+	"GROUP": {
+		apply: func(host *model.Host, value string) { host.Group = value },
+	},
+	"DESCRIPTION": {
+		apply: func(host *model.Host, value string) { host.Description = value },
+	},
+	"TAGS": {
+		// Tags can be split into several lines, they're merged. Tags are case-insensitive, the first spelling is kept.
+		apply: func(host *model.Host, value string) {
+			for _, tag := range splitTags(value) {
+				if !host.HasTag(tag) {
+					host.Tags = append(host.Tags, tag)
+				}
+			}
+		},
+	},
+	"PIN": {
+		value: metadataValueNone,
+		apply: func(host *model.Host, _ string) { host.Pinned = true },
+	},
+	"HIDE": {
+		value: metadataValueNone,
+		apply: func(host *model.Host, _ string) { host.Hidden = true },
+	},
+	"COLOR": {
+		normalize: normalizeColor,
+		apply:     func(host *model.Host, value string) { host.Color = value },
+	},
+	"NOTE": {
+		// Every "# GG:NOTE" line is a line of the note.
+		value: metadataValueOptional,
+		apply: func(host *model.Host, value string) {
+			host.Note = strings.TrimPrefix(host.Note+"\n"+value, "\n")
+		},
+	},
+}
+
+// parseMetadata - splits '# GG:' comment into a tag name and a value. The name is separated from
+// the value by whitespace and/or a colon: "# GG:GROUP Production" and "# GG:GROUP: Production" are
+// equal. The name is converted to upper case.
+func parseMetadata(line string) (string, string, error) {
+	rest, found := strings.CutPrefix(strings.TrimSpace(line), metadataPrefix)
+	if !found {
+		return "", "", errors.New("not a metadata comment")
+	}
+
+	end := strings.IndexAny(rest, " \t:")
+	if end < 0 {
+		end = len(rest)
+	}
+
+	name := strings.ToUpper(rest[:end])
+	value := strings.TrimSpace(strings.TrimPrefix(rest[end:], ":"))
+	tag, known := metadataTags[name]
+	switch {
+	case !known:
+		return "", "", fmt.Errorf("unknown metadata tag GG:%s", rest[:end])
+	case tag.value == metadataValueRequired && value == "":
+		return "", "", fmt.Errorf("missing value for GG:%s", name)
+	case tag.value == metadataValueNone && value != "":
+		return "", "", fmt.Errorf("GG:%s does not accept a value", name)
+	}
+
+	if tag.normalize != nil {
+		var err error
+		if value, err = tag.normalize(value); err != nil {
+			return "", "", err
+		}
+	}
+
+	return name, value, nil
+}
+
+// splitTags - splits tags, which are separated by commas and/or whitespace: "prod, db" => ["prod", "db"].
+func splitTags(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// Color names, which can be used in "# GG:COLOR", and their ANSI codes.
+var colorNames = map[string]string{
+	"black":   "0",
+	"red":     "1",
+	"green":   "2",
+	"yellow":  "3",
+	"blue":    "4",
+	"magenta": "5",
+	"cyan":    "6",
+	"white":   "7",
+	"gray":    "8",
+	"grey":    "8",
+}
+
+var hexColorRe = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// normalizeColor - converts a color name, ANSI code (0-255) or hex value, like "#ff8700", to the form
+// which is understood by the UI: ANSI code or hex value.
+func normalizeColor(value string) (string, error) {
+	if code, found := colorNames[strings.ToLower(value)]; found {
+		return code, nil
+	}
+
+	if hexColorRe.MatchString(value) {
+		return strings.ToLower(value), nil
+	}
+
+	if code, err := strconv.Atoi(value); err == nil && code >= 0 && code <= 255 {
+		return strconv.Itoa(code), nil
+	}
+
+	return "", fmt.Errorf("invalid color %q", value)
+}
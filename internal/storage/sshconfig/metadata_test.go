@@ -0,0 +1,84 @@
+package sshconfig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func Test_parseMetadata(t *testing.T) {
+	tests := []struct {
+		line      string
+		wantName  string
+		wantValue string
+		wantError string
+	}{
+		{line: "# GG:GROUP Production", wantName: "GROUP", wantValue: "Production"},
+		{line: "# GG:group: Production", wantName: "GROUP", wantValue: "Production"},
+		{line: "  # GG:TAGS\tprod, db", wantName: "TAGS", wantValue: "prod, db"},
+		{line: "# GG:PIN", wantName: "PIN"},
+		{line: "# GG:HIDE", wantName: "HIDE"},
+		{line: "# GG:NOTE", wantName: "NOTE"},
+		{line: "# GG:NOTE: Restart only on weekends", wantName: "NOTE", wantValue: "Restart only on weekends"},
+		{line: "# GG:COLOR Red", wantName: "COLOR", wantValue: "1"},
+		{line: "# GG:COLOR #FF8700", wantName: "COLOR", wantValue: "#ff8700"},
+		{line: "# GG:COLOR 208", wantName: "COLOR", wantValue: "208"},
+		{line: "# GG:COLOR 256", wantError: `invalid color "256"`},
+		{line: "# GG:PIN yes", wantError: "GG:PIN does not accept a value"},
+		{line: "# GG:GROUP", wantError: "missing value for GG:GROUP"},
+		{line: "# GG:UNKNOWN value", wantError: "unknown metadata tag GG:UNKNOWN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			name, value, err := parseMetadata(tt.line)
+			if tt.wantError != "" {
+				require.EqualError(t, err, tt.wantError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.wantName, name)
+			require.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestParser_Parse_Metadata(t *testing.T) {
+	const config = `
+Host db1
+    # GG:TAGS prod, db
+    # GG:TAGS eu-west db Prod
+    # GG:PIN
+    # GG:COLOR yellow
+    # GG:NOTE Primary database.
+    # GG:NOTE
+    # GG:NOTE Restart only on weekends.
+    HostName db1.example.com
+
+Host bastion
+    # GG:HIDE
+`
+	lex := &Lexer{
+		rootConfig: configSource{value: config, valueType: valueTypeRaw},
+		logger:     &mocklogger.Logger{},
+	}
+
+	hosts, err := NewParser(lex, &mocklogger.Logger{}).Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+	require.Empty(t, lex.GetDiagnostics())
+
+	require.Equal(t, []string{"prod", "db", "eu-west"}, hosts[0].Tags)
+	require.True(t, hosts[0].Pinned)
+	require.False(t, hosts[0].Hidden)
+	require.Equal(t, "3", hosts[0].Color)
+	require.Equal(t, "Primary database.\n\nRestart only on weekends.", hosts[0].Note)
+	// Metadata is not an ssh option.
+	require.Len(t, hosts[0].Directives, 1)
+
+	require.True(t, hosts[1].Hidden)
+	require.False(t, hosts[1].Pinned)
+}
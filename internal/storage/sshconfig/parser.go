@@ -2,8 +2,11 @@ package sshconfig
 
 import (
 	"errors"
+	"slices"
 	"strings"
 
+	"github.com/samber/lo"
+
 	model "github.com/grafviktor/goto/internal/model/host"
 	"github.com/grafviktor/goto/internal/utils"
 )
@@ -17,7 +20,21 @@ type Parser struct {
 	lexer       lexer
 	currentHost *model.Host
 	foundHosts  []model.Host
-	logger      iLogger
+	// matchBlocks contains criteria of all "Match" blocks. inMatchBlock is true when the parser
+	// reads directives which belong to "Match" block, they must not be assigned to any host.
+	matchBlocks  []string
+	inMatchBlock bool
+	// knownAliases contains positions of "Host" lines, where aliases are declared, they're used to find
+	// duplicates. diagnostics contains problems found during the last Parse call.
+	knownAliases map[string]position
+	diagnostics  []Diagnostic
+	// blocks contains all "Host" blocks in the order they're declared, the first one contains global
+	// options, which are declared before the first "Host" line. currentBlock is an index of the block,
+	// which is being read, and hostBlocks contains indexes of foundHosts blocks.
+	blocks       []model.PatternBlock
+	currentBlock int
+	hostBlocks   []int
+	logger       iLogger
 }
 
 // NewParser constructs a new Parser instance with the provided lexer and logger.
@@ -42,21 +59,63 @@ func (p *Parser) Parse() ([]model.Host, error) {
 	}
 	p.currentHost = nil
 	p.foundHosts = nil
+	p.matchBlocks = nil
+	p.inMatchBlock = false
+	p.knownAliases = make(map[string]position)
+	p.diagnostics = []Diagnostic{}
+	p.blocks = []model.PatternBlock{{}}
+	p.currentBlock = 0
+	p.hostBlocks = nil
 
 	for _, token := range hostTokens {
+		if token.kind == tokenKind.Match {
+			// "Match" block ends the current host block.
+			p.appendLastHostIfValid()
+			p.currentHost = nil
+			p.inMatchBlock = true
+			p.matchBlocks = append(p.matchBlocks, token.value)
+			continue
+		}
+
 		if token.kind != tokenKind.Host && p.currentHost == nil {
-			// Something went wrong - the app assigns values to the current host before it is created.
-			p.logger.Error("[SSHCONFIG] Unexpected token %s with value %v before host declaration",
-				token.kind, token.value)
+			// Directives which are declared before the first host are global defaults.
+			if !p.inMatchBlock {
+				p.appendBlockDirective(token)
+			}
+
+			if !p.inMatchBlock && token.kind != tokenKind.Directive {
+				// Something went wrong - the app assigns values to the current host before it is created.
+				p.logger.Error("[SSHCONFIG] Unexpected token %s with value %v before host declaration",
+					token.kind, token.value)
+			}
 			continue
 		}
 
+		if token.kind != tokenKind.Host && !utils.StringEmpty(&token.keyword) {
+			p.currentHost.Directives = append(p.currentHost.Directives, model.Directive{
+				Keyword: token.keyword,
+				Value:   token.rawValue,
+			})
+			p.appendBlockDirective(token)
+		}
+
 		switch token.kind {
 		case tokenKind.Host:
 			// New host found, append current host if it is valid.
 			p.appendLastHostIfValid()
+			p.inMatchBlock = false
+			p.checkHostPatterns(token)
+			p.blocks = append(p.blocks, model.PatternBlock{
+				Patterns:   token.value,
+				SourcePath: token.source,
+				Line:       token.line,
+			})
+			p.currentBlock = len(p.blocks) - 1
+			title, aliases := hostPatterns(token.value)
 			p.currentHost = &model.Host{
-				Title: token.value,
+				Title:      title,
+				Aliases:    aliases,
+				SourcePath: token.source,
 			}
 		case tokenKind.Hostname:
 			p.currentHost.Address = token.value
@@ -66,45 +125,117 @@ func (p *Parser) Parse() ([]model.Host, error) {
 			p.currentHost.IdentityFilePath = token.value
 		case tokenKind.User:
 			p.currentHost.LoginName = token.value
-		case tokenKind.Group:
-			p.currentHost.Group = token.value
-		case tokenKind.Description:
-			p.currentHost.Description = token.value
+		case tokenKind.Metadata:
+			metadataTags[token.metaTag].apply(p.currentHost, token.value)
 		}
 	}
 
 	p.appendLastHostIfValid()
 	p.setDefaults()
+	p.setMatchCriteria()
+	p.setInheritedDirectives()
 
 	return p.foundHosts, nil
 }
 
+// Diagnostics returns problems which were found during the last Parse call. Problems which are found
+// by the lexer are not included.
+func (p *Parser) Diagnostics() []Diagnostic {
+	return p.diagnostics
+}
+
+// checkHostPatterns - reports aliases which are declared in several "Host" lines and blocks which are
+// not displayed in the host list, because they only contain wildcard and negated patterns.
+func (p *Parser) checkHostPatterns(token SSHToken) {
+	patterns, err := splitArguments(token.value)
+	if err != nil || len(patterns) == 0 {
+		return
+	}
+
+	if hostTitleIndex(patterns) < 0 {
+		// "Host *" is a common way to declare default options.
+		if token.value != "*" {
+			p.diagnostics = append(p.diagnostics, token.position().diagnostic(Severity.Warning,
+				"Host %s only contains patterns, it is not displayed in the host list", token.value))
+		}
+
+		return
+	}
+
+	for _, alias := range lo.Reject(patterns, func(pattern string, _ int) bool { return isPattern(pattern) }) {
+		if declared, found := p.knownAliases[alias]; found {
+			// It's valid in ssh_config, ssh uses the first obtained value of each option.
+			p.diagnostics = append(p.diagnostics, token.position().diagnostic(Severity.Warning,
+				"Host alias %q is already declared at %s:%d", alias, utils.RedactURL(declared.source), declared.line))
+			continue
+		}
+
+		p.knownAliases[alias] = token.position()
+	}
+}
+
+// Patterns returns wildcard "Host" blocks and global options, which were found during the last Parse call.
+// Global options are only returned if they're declared.
+func (p *Parser) Patterns() []model.PatternBlock {
+	return lo.Filter(p.blocks, func(block model.PatternBlock, i int) bool {
+		if i == 0 {
+			return len(block.Directives) > 0
+		}
+
+		// "Host *.prod web1" block is displayed in the host list as "web1", and also applies to other hosts.
+		patterns, err := splitArguments(block.Patterns)
+		return err == nil && lo.ContainsBy(patterns, func(pattern string) bool {
+			return isPattern(pattern) && !strings.HasPrefix(pattern, "!")
+		})
+	})
+}
+
 func (p *Parser) appendLastHostIfValid() {
 	if p.hostValid() {
 		p.foundHosts = append(p.foundHosts, *p.currentHost)
+		p.hostBlocks = append(p.hostBlocks, p.currentBlock)
 	}
 }
 
-func (p *Parser) hostValid() bool {
-	if p.currentHost == nil {
-		return false
+func (p *Parser) appendBlockDirective(token SSHToken) {
+	if utils.StringEmpty(&token.keyword) {
+		return
 	}
 
-	if strings.Contains(p.currentHost.Title, "*") {
-		return false
+	block := &p.blocks[p.currentBlock]
+	if p.currentBlock == 0 && len(block.Directives) == 0 {
+		// Global options do not have "Host" line, so the block points to the first option.
+		block.SourcePath, block.Line = token.source, token.line
 	}
 
-	if utils.StringEmpty(&p.currentHost.Title) && utils.StringEmpty(&p.currentHost.Address) {
+	block.Directives = append(block.Directives, model.Directive{
+		Keyword: token.keyword,
+		Value:   token.rawValue,
+	})
+}
+
+func (p *Parser) hostValid() bool {
+	if p.currentHost == nil {
 		return false
 	}
 
-	return true
+	// Blocks, which only contain patterns, for instance "Host *.prod", do not declare a host.
+	return !utils.StringEmpty(&p.currentHost.Title)
 }
 
 const putSSHConfigHostsIntoGroupName = "ssh_config"
 
 func (p *Parser) setDefaults() {
+	knownIDs := make(map[string]struct{}, len(p.foundHosts))
 	for i, host := range p.foundHosts {
+		// Hosts which do not have '# GG:ID' metadata, or have the same ID as another host, receive an ID
+		// derived from the source file and the alias. It does not change between application restarts,
+		// however changes when the host is renamed. That is why the ID is persisted when the host is saved.
+		if _, duplicate := knownIDs[host.ID]; duplicate || utils.StringEmpty(&host.ID) {
+			p.foundHosts[i].ID = model.DeriveID(host.SourcePath, host.Title)
+		}
+		knownIDs[p.foundHosts[i].ID] = struct{}{}
+
 		if utils.StringEmpty(&host.Group) {
 			p.foundHosts[i].Group = putSSHConfigHostsIntoGroupName
 		}
@@ -116,3 +247,38 @@ func (p *Parser) setDefaults() {
 		}
 	}
 }
+
+// hostPatterns - splits "Host" value, for instance "*.web web1 web1.internal", into a title, which is the
+// first alias, and other aliases. Wildcard and negated patterns are not aliases, they're skipped. The title
+// is empty if the value only contains patterns.
+func hostPatterns(value string) (string, []string) {
+	patterns, err := splitArguments(value)
+	if err != nil {
+		return "", nil
+	}
+
+	titleIndex := hostTitleIndex(patterns)
+	if titleIndex < 0 {
+		return "", nil
+	}
+
+	return patterns[titleIndex], lo.Reject(patterns[titleIndex+1:], func(pattern string, _ int) bool {
+		return isPattern(pattern)
+	})
+}
+
+// hostTitleIndex - returns the index of the first "Host" pattern, which is a host alias, or -1.
+func hostTitleIndex(patterns []string) int {
+	return slices.IndexFunc(patterns, func(pattern string) bool { return !isPattern(pattern) })
+}
+
+// setMatchCriteria - assigns criteria of "Match" blocks to the hosts, which they might apply to.
+func (p *Parser) setMatchCriteria() {
+	for i, host := range p.foundHosts {
+		for _, criteria := range p.matchBlocks {
+			if matchMightApply(criteria, host) {
+				p.foundHosts[i].MatchCriteria = append(p.foundHosts[i].MatchCriteria, criteria)
+			}
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	model "github.com/grafviktor/goto/internal/model/host"
 	"github.com/grafviktor/goto/internal/testutils/mocklogger"
 )
 
@@ -24,8 +25,8 @@ func TestParser_Parse_SingleHost(t *testing.T) {
 			{kind: tokenKind.User, value: "alice"},
 			{kind: tokenKind.NetworkPort, value: "2222"},
 			{kind: tokenKind.IdentityFile, value: "~/.ssh/id_rsa"},
-			{kind: tokenKind.Group, value: "devops"},
-			{kind: tokenKind.Description, value: "desc"},
+			{kind: tokenKind.Metadata, metaTag: "GROUP", value: "devops"},
+			{kind: tokenKind.Metadata, metaTag: "DESCRIPTION", value: "desc"},
 		},
 	}
 	parser := NewParser(lexer, &mocklogger.Logger{})
@@ -61,6 +62,31 @@ func TestParser_Parse_MultipleHosts(t *testing.T) {
 	require.Equal(t, "host2.com", hosts[1].Address)
 }
 
+func TestParser_Parse_HostID(t *testing.T) {
+	lexer := &mockLexer{
+		tokens: []SSHToken{
+			{kind: tokenKind.Host, value: "host1", source: "/ssh/config"},
+			{kind: tokenKind.Metadata, metaTag: "ID", value: "host1-id"},
+			{kind: tokenKind.Host, value: "host2", source: "/ssh/config"},
+			{kind: tokenKind.Host, value: "host3", source: "/ssh/config"},
+			// Duplicate ID, for instance, when a host block was copied by hand.
+			{kind: tokenKind.Metadata, metaTag: "ID", value: "host1-id"},
+		},
+	}
+	parser := NewParser(lexer, &mocklogger.Logger{})
+	hosts, err := parser.Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 3)
+	require.Equal(t, "host1-id", hosts[0].ID)
+	require.Equal(t, model.DeriveID("/ssh/config", "host2"), hosts[1].ID)
+	require.Equal(t, model.DeriveID("/ssh/config", "host3"), hosts[2].ID)
+
+	// Derived IDs must not change when the file is parsed again.
+	hosts, err = parser.Parse()
+	require.NoError(t, err)
+	require.Equal(t, model.DeriveID("/ssh/config", "host2"), hosts[1].ID)
+}
+
 func TestParser_Parse_InvalidHost(t *testing.T) {
 	lexer := &mockLexer{
 		tokens: []SSHToken{
@@ -138,3 +164,128 @@ func TestParser_Parse_OrphanSubOptionDoesNotPanic(t *testing.T) {
 	require.Equal(t, "good", hosts[0].Title)
 	require.Equal(t, "good.example.com", hosts[0].Address)
 }
+
+func TestParser_Parse_HostPatterns(t *testing.T) {
+	lexer := &mockLexer{
+		tokens: []SSHToken{
+			{kind: tokenKind.Host, value: "web1 web1.internal *.web !web2"},
+			{kind: tokenKind.Hostname, value: "10.0.0.1"},
+		},
+	}
+	parser := NewParser(lexer, &mocklogger.Logger{})
+	hosts, err := parser.Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	// The first pattern is a title, wildcard and negated patterns are not aliases.
+	require.Equal(t, "web1", hosts[0].Title)
+	require.Equal(t, []string{"web1.internal"}, hosts[0].Aliases)
+
+	// The title is the first alias, even if the block starts with a pattern.
+	lexer.tokens = []SSHToken{
+		{kind: tokenKind.Host, value: "*.prod !db.prod web1 *.web web1.internal"},
+		{kind: tokenKind.Hostname, value: "10.0.0.1"},
+		{kind: tokenKind.Host, value: "*.internal !web2"},
+		{kind: tokenKind.Hostname, value: "10.0.0.2"},
+	}
+	hosts, err = parser.Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, "web1", hosts[0].Title)
+	require.Equal(t, []string{"web1.internal"}, hosts[0].Aliases)
+	// Both blocks apply to other hosts, which match the patterns.
+	require.Len(t, parser.Patterns(), 2)
+}
+
+func TestParser_Parse_MatchBlock(t *testing.T) {
+	lexer := &mockLexer{
+		tokens: []SSHToken{
+			{kind: tokenKind.Host, value: "web1"},
+			{kind: tokenKind.Hostname, value: "web1.internal"},
+			{kind: tokenKind.Match, value: "host *.internal"},
+			{kind: tokenKind.User, value: "deploy"},
+			{kind: tokenKind.NetworkPort, value: "2222"},
+			{kind: tokenKind.Host, value: "db"},
+			{kind: tokenKind.Hostname, value: "db.external"},
+			{kind: tokenKind.Match, value: "all"},
+			{kind: tokenKind.IdentityFile, value: "~/.ssh/id_ed25519"},
+		},
+	}
+	parser := NewParser(lexer, &mocklogger.Logger{})
+	hosts, err := parser.Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 2)
+
+	// Directives which belong to "Match" block are not assigned to the host above it.
+	require.Empty(t, hosts[0].LoginName)
+	require.Empty(t, hosts[0].RemotePort)
+	require.Empty(t, hosts[1].IdentityFilePath)
+
+	require.Equal(t, []string{"host *.internal", "all"}, hosts[0].MatchCriteria)
+	require.Equal(t, []string{"all"}, hosts[1].MatchCriteria)
+}
+
+func TestParser_Parse_Directives(t *testing.T) {
+	lexer := &mockLexer{
+		tokens: []SSHToken{
+			{kind: tokenKind.Directive, value: "60", keyword: "ServerAliveInterval", rawValue: "60"},
+			{kind: tokenKind.Host, value: "web1", keyword: "Host", rawValue: "web1"},
+			{kind: tokenKind.Metadata, metaTag: "ID", value: "web1_id"},
+			{kind: tokenKind.Hostname, value: "10.0.0.1", keyword: "HostName", rawValue: "10.0.0.1"},
+			{kind: tokenKind.Directive, value: "bastion", keyword: "ProxyJump", rawValue: "bastion"},
+			{kind: tokenKind.IdentityFile, value: "~/My Keys/id", keyword: "IdentityFile", rawValue: `"~/My Keys/id"`},
+			{kind: tokenKind.Directive, value: "yes", keyword: "ForwardAgent", rawValue: "yes"},
+		},
+	}
+	logger := &mocklogger.Logger{}
+	parser := NewParser(lexer, logger)
+	hosts, err := parser.Parse()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+
+	// All directives are kept in the order they're declared, global defaults are not assigned to the host.
+	require.Equal(t, []model.Directive{
+		{Keyword: "HostName", Value: "10.0.0.1"},
+		{Keyword: "ProxyJump", Value: "bastion"},
+		{Keyword: "IdentityFile", Value: `"~/My Keys/id"`},
+		{Keyword: "ForwardAgent", Value: "yes"},
+	}, hosts[0].Directives)
+	require.Equal(t, "~/My Keys/id", hosts[0].IdentityFilePath)
+	require.Empty(t, logger.Logs)
+}
+
+func TestParser_Parse_Diagnostics(t *testing.T) {
+	lexer := &mockLexer{
+		tokens: []SSHToken{
+			{kind: tokenKind.Host, value: "*", source: "config", line: 1},
+			{kind: tokenKind.Host, value: "web1 web1.internal", source: "config", line: 3},
+			{kind: tokenKind.Host, value: "*.internal web2", source: "config", line: 5},
+			{kind: tokenKind.Host, value: "*.internal !web3", source: "config", line: 7},
+			{kind: tokenKind.Host, value: "web2 web1.internal", source: "included", line: 1},
+		},
+	}
+	parser := NewParser(lexer, &mocklogger.Logger{})
+	_, err := parser.Parse()
+	require.NoError(t, err)
+
+	// "Host *" is not reported, as it's a common way to declare defaults.
+	require.Equal(t, []Diagnostic{
+		{
+			Source:   "config",
+			Line:     7,
+			Severity: Severity.Warning,
+			Message:  "Host *.internal !web3 only contains patterns, it is not displayed in the host list",
+		},
+		{
+			Source:   "included",
+			Line:     1,
+			Severity: Severity.Warning,
+			Message:  `Host alias "web2" is already declared at config:5`,
+		},
+		{
+			Source:   "included",
+			Line:     1,
+			Severity: Severity.Warning,
+			Message:  `Host alias "web1.internal" is already declared at config:3`,
+		},
+	}, parser.Diagnostics())
+}
@@ -0,0 +1,155 @@
+package sshconfig
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	errNotDirective      = errors.New("not a key value string")
+	errUnterminatedQuote = errors.New("unterminated quoted string")
+)
+
+// directive is ssh_config line, which is split into a keyword and arguments using the same rules
+// as OpenSSH does, see readconf.c and argv_split in misc.c:
+//
+//	User root                        -> "User", ["root"]
+//	User=root                        -> "User", ["root"]
+//	IdentityFile "~/My Keys/id_rsa"  -> "IdentityFile", ["~/My Keys/id_rsa"]
+//	Host web1 web1.internal # web    -> "Host", ["web1", "web1.internal"]
+type directive struct {
+	keyword string
+	args    []string
+	// valueStart and valueEnd are the positions of the arguments in the line. Trailing comment
+	// is not a part of the value.
+	valueStart int
+	valueEnd   int
+}
+
+// value - returns the arguments as they're written in the line, including quotes.
+func (d directive) value(line string) string {
+	return line[d.valueStart:d.valueEnd]
+}
+
+// parseDirective - splits ssh_config line into a keyword and arguments. The keyword is separated from
+// the arguments by whitespace and/or a single '=' sign. Arguments are separated by whitespace and can be
+// enclosed in double or single quotes. '#' which starts an argument begins a comment.
+func parseDirective(line string) (directive, error) {
+	i := skipWhitespace(line, 0)
+	if i == len(line) || line[i] == '#' {
+		return directive{}, errNotDirective
+	}
+
+	keywordStart := i
+	for i < len(line) && !isWhitespace(line[i]) && line[i] != '=' {
+		i++
+	}
+
+	if i == keywordStart {
+		return directive{}, errNotDirective
+	}
+
+	d := directive{keyword: line[keywordStart:i]}
+	i = skipWhitespace(line, i)
+	if i < len(line) && line[i] == '=' {
+		i = skipWhitespace(line, i+1)
+	}
+
+	d.valueStart, d.valueEnd = i, i
+	for {
+		i = skipWhitespace(line, i)
+		if i == len(line) || line[i] == '#' {
+			return d, nil
+		}
+
+		var arg string
+		var err error
+		arg, i, err = readArgument(line, i)
+		if err != nil {
+			return directive{}, err
+		}
+
+		d.args = append(d.args, arg)
+		d.valueEnd = i
+	}
+}
+
+// readArgument - reads a single argument, which starts at position i, and returns the position
+// which follows the argument. A backslash escapes quotes, backslash itself, and a space outside
+// of quotes. Any other backslash is a part of the argument, so that Windows paths are preserved.
+func readArgument(line string, i int) (string, int, error) {
+	var arg strings.Builder
+	var quote byte
+	for ; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line) && isEscapable(line[i+1], quote):
+			i++
+			arg.WriteByte(line[i])
+		case quote == 0 && isWhitespace(c):
+			return arg.String(), i, nil
+		case quote == 0 && (c == '"' || c == '\''):
+			quote = c
+		case quote != 0 && c == quote:
+			quote = 0
+		default:
+			arg.WriteByte(c)
+		}
+	}
+
+	if quote != 0 {
+		return "", i, errUnterminatedQuote
+	}
+
+	return arg.String(), i, nil
+}
+
+func isEscapable(c, quote byte) bool {
+	return c == '"' || c == '\'' || c == '\\' || (quote == 0 && c == ' ')
+}
+
+func isWhitespace(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func skipWhitespace(line string, i int) int {
+	for i < len(line) && isWhitespace(line[i]) {
+		i++
+	}
+
+	return i
+}
+
+// splitArguments - splits ssh_config value, for instance "host *.internal exec \"test -f /tmp/vpn\"",
+// into arguments.
+func splitArguments(value string) ([]string, error) {
+	var args []string
+	for i := skipWhitespace(value, 0); i < len(value) && value[i] != '#'; i = skipWhitespace(value, i) {
+		var arg string
+		var err error
+		arg, i, err = readArgument(value, i)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	return args, nil
+}
+
+// quoteArgument - encloses the value in double quotes, if it contains characters which have special
+// meaning in ssh_config, so that the value is read back unchanged.
+func quoteArgument(value string) string {
+	// Two backslashes are read back as one, unless the value is quoted. Leading '=' is a separator.
+	needsQuotes := value == "" ||
+		strings.ContainsAny(value, " \t\"'#") ||
+		strings.Contains(value, `\\`) ||
+		strings.HasPrefix(value, "=")
+	if !needsQuotes {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + replacer.Replace(value) + `"`
+}
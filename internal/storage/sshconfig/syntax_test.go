@@ -0,0 +1,116 @@
+package sshconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func Test_parseDirective(t *testing.T) {
+	tests := []struct {
+		line      string
+		keyword   string
+		args      []string
+		value     string
+		wantError bool
+	}{
+		{line: "User root", keyword: "User", args: []string{"root"}, value: "root"},
+		{line: "  user\troot", keyword: "user", args: []string{"root"}, value: "root"},
+		{line: "User=root", keyword: "User", args: []string{"root"}, value: "root"},
+		{line: "User = root", keyword: "User", args: []string{"root"}, value: "root"},
+		{line: "User= root # comment", keyword: "User", args: []string{"root"}, value: "root"},
+		{line: `IdentityFile "~/My Keys/id_ed25519"`, keyword: "IdentityFile", args: []string{"~/My Keys/id_ed25519"}, value: `"~/My Keys/id_ed25519"`},
+		{line: `IdentityFile '~/My Keys/id_ed25519'`, keyword: "IdentityFile", args: []string{"~/My Keys/id_ed25519"}, value: `'~/My Keys/id_ed25519'`},
+		{line: `IdentityFile ~/My\ Keys/id_ed25519`, keyword: "IdentityFile", args: []string{"~/My Keys/id_ed25519"}, value: `~/My\ Keys/id_ed25519`},
+		{line: `IdentityFile "~/keys/#1"`, keyword: "IdentityFile", args: []string{"~/keys/#1"}, value: `"~/keys/#1"`},
+		{line: `IdentityFile "say \"hello\""`, keyword: "IdentityFile", args: []string{`say "hello"`}, value: `"say \"hello\""`},
+		// Unknown escapes are preserved, so that Windows paths can be used without quoting.
+		{line: `IdentityFile C:\Users\me\.ssh\id_rsa`, keyword: "IdentityFile", args: []string{`C:\Users\me\.ssh\id_rsa`}, value: `C:\Users\me\.ssh\id_rsa`},
+		{line: "Host web1 web1.internal # web", keyword: "Host", args: []string{"web1", "web1.internal"}, value: "web1 web1.internal"},
+		{line: "Host web#1", keyword: "Host", args: []string{"web#1"}, value: "web#1"},
+		{line: `Match host *.internal exec "test -f /tmp/vpn"`, keyword: "Match", args: []string{"host", "*.internal", "exec", "test -f /tmp/vpn"}, value: `host *.internal exec "test -f /tmp/vpn"`},
+		{line: `IdentityFile ""`, keyword: "IdentityFile", args: []string{""}, value: `""`},
+		{line: "Host", keyword: "Host"},
+		{line: "Host=", keyword: "Host"},
+		{line: `IdentityFile "~/My Keys/id_ed25519`, wantError: true},
+		{line: "# comment", wantError: true},
+		{line: "=root", wantError: true},
+		{line: "   ", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			d, err := parseDirective(tt.line)
+			if tt.wantError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.keyword, d.keyword)
+			require.Equal(t, tt.args, d.args)
+			require.Equal(t, tt.value, d.value(tt.line))
+		})
+	}
+}
+
+func Test_quoteArgument(t *testing.T) {
+	require.Equal(t, "~/.ssh/id_rsa", quoteArgument("~/.ssh/id_rsa"))
+	require.Equal(t, `C:\Users\me\.ssh\id_rsa`, quoteArgument(`C:\Users\me\.ssh\id_rsa`))
+	require.Equal(t, `"~/My Keys/id_ed25519"`, quoteArgument("~/My Keys/id_ed25519"))
+	require.Equal(t, `"\\\\server\\share\\id_rsa"`, quoteArgument(`\\server\share\id_rsa`))
+	require.Equal(t, `""`, quoteArgument(""))
+}
+
+func FuzzParseDirective(f *testing.F) {
+	f.Add("IdentityFile", "~/My Keys/id_ed25519")
+	f.Add("User", "root")
+	f.Add("Host", `web#1 "quoted"`)
+	f.Add("IdentityFile", `\\server\share\id_rsa`)
+	f.Add("IdentityFile", `C:\Users\me\`)
+
+	f.Fuzz(func(t *testing.T, keyword, value string) {
+		_, _ = parseDirective(keyword + " " + value)
+
+		// Values which are written by the app are read back unchanged.
+		if keyword == "" || strings.ContainsAny(keyword, " \t=#\"'\\\r\n") || strings.ContainsAny(value, "\r\n") {
+			return
+		}
+
+		d, err := parseDirective(keyword + " " + quoteArgument(value))
+		require.NoError(t, err)
+		require.Equal(t, keyword, d.keyword)
+		require.Equal(t, []string{value}, d.args)
+	})
+}
+
+// FuzzLexer_Tokenize - the lexer and the parser must never panic, whatever the file contains. Inputs which
+// caused panics before are stored in testdata/fuzz folder.
+func FuzzLexer_Tokenize(f *testing.F) {
+	f.Add("Host test\n  HostName example.com\n  User=root\n  Port = 22\n")
+	f.Add("Host\nHost=\nHost \"\nMatch\nMatch all\n  User root\n")
+	f.Add("Host a b\n  IdentityFile \"~/My Keys/id\" # comment\n  # GG:GROUP: group\n")
+	f.Add("Host a\n  IdentityFile \"unterminated\n  HostName \\\n  Port 99999999999\n")
+
+	f.Fuzz(func(t *testing.T, config string) {
+		if strings.Contains(strings.ToLower(config), "include") {
+			// Included files are read from the file system, which is not what the test is about.
+			t.Skip()
+		}
+
+		rootConfig := configSource{
+			value:     config,
+			valueType: valueTypeRaw,
+		}
+		lex := &Lexer{
+			rootConfig: rootConfig,
+			logger:     &mocklogger.Logger{},
+		}
+
+		parser := NewParser(lex, &mocklogger.Logger{})
+		_, _ = parser.Parse()
+	})
+}
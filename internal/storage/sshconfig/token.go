@@ -4,27 +4,43 @@ type tokenEnum string
 
 var tokenKind = struct {
 	Host         tokenEnum
+	Match        tokenEnum
+	Directive    tokenEnum
 	User         tokenEnum
 	Hostname     tokenEnum
 	NetworkPort  tokenEnum
 	Unsupported  tokenEnum
 	IncludeFile  tokenEnum
 	IdentityFile tokenEnum
-	Group        tokenEnum
-	Description  tokenEnum
+	Metadata     tokenEnum
 }{
 	Host:         "Host",
+	Match:        "Match",
+	Directive:    "Directive",
 	User:         "User",
 	Hostname:     "HostName",
 	NetworkPort:  "Port",
 	Unsupported:  "Unsupported",
 	IncludeFile:  "Include",
 	IdentityFile: "IdentityFile",
-	Group:        "Group",
-	Description:  "Description",
+	Metadata:     "Metadata",
 }
 
+// SSHToken - is a single line of ssh_config file. keyword and rawValue contain the directive as it's
+// written in the file, for instance "IdentityFile" and "\"~/My Keys/id_rsa\"", while value is the parsed
+// one. keyword and rawValue are empty for metadata tokens, metaTag contains '# GG:' tag name instead,
+// for instance "GROUP". problem describes why the line or its value is rejected by the lexer.
 type SSHToken struct {
-	value string
-	kind  tokenEnum
+	value    string
+	kind     tokenEnum
+	source   string // File path or URL where the token was found.
+	line     int
+	keyword  string
+	rawValue string
+	metaTag  string
+	problem  string
+}
+
+func (t SSHToken) position() position {
+	return position{source: t.source, line: t.line}
 }
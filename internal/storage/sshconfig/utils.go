@@ -32,23 +32,6 @@ type iLogger interface {
 // "user!",        // Invalid (special character).
 var sshUsernameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]{0,31}$`)
 
-/*
-Valid hostname regex (RFC 1035 + RFC 1123).
-
-"example.com",     // Valid.
-"sub.example.com", // Valid.
-"localhost",       // Valid (for local use).
-"123.example",     // Valid.
-"-invalid.com",    // Invalid (starts with `-`).
-"example-.com",    // Invalid (ends with `-`).
-"ex@mpl$.com",     // Invalid (special characters).
-"superlonglabelnamethatiswaytoolongtobevalid.example.com", // Invalid (over 63 chars per label).
-"toolong......................................................................................................com", // Invalid (over 253 chars).
-*/
-var hostnameRegex = regexp.MustCompile(
-	`^(?i:[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*)$`,
-)
-
 func isNetworkPortNumberValid(port int) bool {
 	return port >= 0 && port <= 65535
 }
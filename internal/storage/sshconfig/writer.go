@@ -0,0 +1,403 @@
+package sshconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/lo"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// ErrHostNotFound is returned when the writer cannot find a host declaration in ssh_config file.
+var ErrHostNotFound = errors.New("host declaration not found in ssh_config file")
+
+const defaultIndent = "  "
+
+// Writer modifies ssh_config files in place. It only touches the lines which it manages:
+// 'Host', 'HostName', 'User', 'Port', 'IdentityFile' and '# GG:' metadata. Comments,
+// unknown directives and the order of the lines are preserved.
+type Writer struct {
+	logger iLogger
+}
+
+// NewWriter constructs a new Writer instance.
+func NewWriter(log iLogger) *Writer {
+	return &Writer{logger: log}
+}
+
+// blockEntry describes a single line which the writer manages inside a host block.
+type blockEntry struct {
+	key    string
+	value  string
+	isMeta bool
+	// defaultValue is a value which is implied by ssh or by goto, when the line is absent.
+	// If there is no such line in the block, and the new value equals to default, the line
+	// won't be created.
+	defaultValue string
+	// merged - the metadata can be split into several lines, which are merged when the file is read, for
+	// instance "# GG:TAGS". The value is written to the first line, and the other lines are removed.
+	merged bool
+}
+
+func hostBlockEntries(host model.Host) []blockEntry {
+	return []blockEntry{
+		{key: "ID", value: host.ID, isMeta: true},
+		{key: "GROUP", value: host.Group, isMeta: true, defaultValue: putSSHConfigHostsIntoGroupName},
+		{key: "DESCRIPTION", value: host.Description, isMeta: true},
+		{key: "TAGS", value: strings.Join(host.Tags, ", "), isMeta: true, merged: true},
+		{key: "HostName", value: utils.UnbracketAddress(host.Address), defaultValue: host.Title},
+		{key: "User", value: host.LoginName},
+		{key: "Port", value: host.RemotePort},
+		{key: "IdentityFile", value: host.IdentityFilePath},
+	}
+}
+
+// ValidateHost checks that the host can be stored in ssh_config file and will be
+// read back by the Lexer without losing any values.
+func ValidateHost(host model.Host) error {
+	if utils.StringEmpty(&host.Title) {
+		return errors.New("title is required")
+	}
+
+	if strings.ContainsAny(host.Title, " \t*?!,#") {
+		return fmt.Errorf("%q cannot be used as ssh_config host alias", host.Title)
+	}
+
+	if host.Address != host.Title && !utils.IsValidHostAddress(host.Address) {
+		return fmt.Errorf("%q is not a valid hostname", host.Address)
+	}
+
+	if !utils.StringEmpty(&host.LoginName) && !sshUsernameRegex.MatchString(host.LoginName) {
+		return fmt.Errorf("%q is not a valid user name", host.LoginName)
+	}
+
+	if !utils.StringEmpty(&host.RemotePort) {
+		port, err := strconv.Atoi(host.RemotePort)
+		if err != nil || !isNetworkPortNumberValid(port) {
+			return fmt.Errorf("%q is not a valid network port", host.RemotePort)
+		}
+	}
+
+	if strings.ContainsAny(host.Group+host.Description, "\r\n") {
+		return errors.New("group and description must be single line values")
+	}
+
+	for _, tag := range host.Tags {
+		if tag == "" || strings.ContainsAny(tag, ", \t\r\n") {
+			return fmt.Errorf("tag %q cannot contain commas or spaces", tag)
+		}
+	}
+
+	return nil
+}
+
+// SaveHost updates the block declared as "Host <originalTitle>" in filePath. If originalTitle
+// is empty, a new block is appended to the end of the file.
+func (w *Writer) SaveHost(filePath, originalTitle string, host model.Host) error {
+	if err := ValidateHost(host); err != nil {
+		return err
+	}
+
+	lines, eol, mode, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+
+	if utils.StringEmpty(&originalTitle) {
+		w.logger.Info("[SSHCONFIG] Append host %q to file: %s", host.Title, filePath)
+		lines = appendHostBlock(lines, host)
+		return writeLines(filePath, lines, eol, mode)
+	}
+
+	start, _, found := findHostBlock(lines, originalTitle)
+	if !found {
+		w.logger.Error("[SSHCONFIG] Cannot find host %q in file: %s", originalTitle, filePath)
+		return ErrHostNotFound
+	}
+
+	w.logger.Info("[SSHCONFIG] Update host %q in file: %s", originalTitle, filePath)
+	// Only the title is replaced, other aliases and patterns are preserved: "Host *.prod web1 web1.internal".
+	d, err := parseDirective(lines[start])
+	if err != nil {
+		return err
+	}
+
+	d.args[hostTitleIndex(d.args)] = host.Title
+	lines[start] = replaceLineValue(lines[start], strings.Join(lo.Map(d.args, func(arg string, _ int) string {
+		return quoteArgument(arg)
+	}), " "))
+	for _, entry := range hostBlockEntries(host) {
+		lines = setBlockValue(lines, start, entry)
+	}
+
+	return writeLines(filePath, lines, eol, mode)
+}
+
+// DeleteHost removes the block declared as "Host <title>" from filePath.
+func (w *Writer) DeleteHost(filePath, title string) error {
+	lines, eol, mode, err := readLines(filePath)
+	if err != nil {
+		return err
+	}
+
+	start, end, found := findHostBlock(lines, title)
+	if !found {
+		w.logger.Error("[SSHCONFIG] Cannot find host %q in file: %s", title, filePath)
+		return ErrHostNotFound
+	}
+
+	w.logger.Info("[SSHCONFIG] Delete host %q from file: %s", title, filePath)
+	lines = append(lines[:start], lines[end:]...)
+	// Avoid leaving two blank lines in a row where the block used to be.
+	if start < len(lines) && isBlankLine(lines[start]) && (start == 0 || isBlankLine(lines[start-1])) {
+		lines = append(lines[:start], lines[start+1:]...)
+	}
+
+	return writeLines(filePath, lines, eol, mode)
+}
+
+func readLines(filePath string) ([]string, string, os.FileMode, error) {
+	if utils.IsSupportedURL(filePath) {
+		return nil, "", 0, fmt.Errorf("cannot modify remote file: %s", filePath)
+	}
+
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, "", 0, err
+	}
+
+	content := string(data)
+	eol := lo.Ternary(strings.Contains(content, "\r\n"), "\r\n", "\n")
+	content = strings.ReplaceAll(content, "\r\n", "\n")
+
+	return strings.Split(content, "\n"), eol, stat.Mode().Perm(), nil
+}
+
+// writeLines - replaces the file atomically, so that it's not truncated if the app crashes or the disk
+// is full. If the file is a symbolic link, for instance, to a dotfiles repository, the link target is
+// replaced, and the link is kept.
+func writeLines(filePath string, lines []string, eol string, mode os.FileMode) error {
+	targetPath, err := filepath.EvalSymlinks(filePath)
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(targetPath, []byte(strings.Join(lines, eol)), mode)
+}
+
+func appendHostBlock(lines []string, host model.Host) []string {
+	// Remove trailing blank lines, and then separate the new block with a single blank line.
+	for len(lines) > 0 && isBlankLine(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) > 0 {
+		lines = append(lines, "")
+	}
+
+	lines = append(lines, "Host "+quoteArgument(host.Title))
+	for _, entry := range hostBlockEntries(host) {
+		if utils.StringEmpty(&entry.value) || entry.value == entry.defaultValue {
+			continue
+		}
+
+		lines = append(lines, formatEntry(defaultIndent, entry))
+	}
+
+	// Keep the new line in the end of the file.
+	return append(lines, "")
+}
+
+// findHostBlock returns the index of "Host <title>" line and the index of the line which
+// follows the last line belonging to the host block. Comments and blank lines which trail
+// the block are not considered as a part of it. Title is the first alias of "Host" line, see hostPatterns.
+func findHostBlock(lines []string, title string) (int, int, bool) {
+	start := -1
+	for i, line := range lines {
+		keyword, value := splitLine(line)
+		if hostTitle, _ := hostPatterns(value); strings.EqualFold(keyword, "Host") && hostTitle == title {
+			start = i
+			break
+		}
+	}
+
+	if start < 0 {
+		return 0, 0, false
+	}
+
+	return start, blockEnd(lines, start), true
+}
+
+func blockEnd(lines []string, start int) int {
+	end := start + 1
+	for i := start + 1; i < len(lines); i++ {
+		keyword, _ := splitLine(lines[i])
+		if strings.EqualFold(keyword, "Host") || strings.EqualFold(keyword, "Match") {
+			break
+		}
+
+		if keyword != "" || metaKey(lines[i]) != "" {
+			end = i + 1
+		}
+	}
+
+	return end
+}
+
+func setBlockValue(lines []string, start int, entry blockEntry) []string {
+	if entry.merged {
+		lines = removeRepeatedMeta(lines, start, entry.key)
+	}
+
+	end := blockEnd(lines, start)
+	lineIndex := -1
+	insertAt := start + 1
+	for i := start + 1; i < end; i++ {
+		if entry.isMeta {
+			key := metaKey(lines[i])
+			if key != "" {
+				insertAt = i + 1
+			}
+
+			if strings.EqualFold(key, entry.key) {
+				lineIndex = i
+				break
+			}
+
+			continue
+		}
+
+		keyword, _ := splitLine(lines[i])
+		if keyword != "" || metaKey(lines[i]) != "" {
+			insertAt = i + 1
+		}
+
+		if strings.EqualFold(keyword, entry.key) {
+			lineIndex = i
+			break
+		}
+	}
+
+	switch {
+	case lineIndex >= 0 && utils.StringEmpty(&entry.value):
+		return append(lines[:lineIndex], lines[lineIndex+1:]...)
+	case lineIndex >= 0:
+		lines[lineIndex] = replaceLineValue(lines[lineIndex], entry.formatValue())
+		return lines
+	case utils.StringEmpty(&entry.value) || entry.value == entry.defaultValue:
+		return lines
+	default:
+		newLine := formatEntry(blockIndent(lines, start, end), entry)
+		return append(lines[:insertAt], append([]string{newLine}, lines[insertAt:]...)...)
+	}
+}
+
+// removeRepeatedMeta - removes all metadata lines with the key, except for the first one, from the block.
+func removeRepeatedMeta(lines []string, start int, key string) []string {
+	end := blockEnd(lines, start)
+	found := false
+	result := slices.Clone(lines[:start+1])
+	for i := start + 1; i < end; i++ {
+		isKey := strings.EqualFold(metaKey(lines[i]), key)
+		if isKey && found {
+			continue
+		}
+
+		found = found || isKey
+		result = append(result, lines[i])
+	}
+
+	return append(result, lines[end:]...)
+}
+
+func formatEntry(indent string, entry blockEntry) string {
+	if entry.isMeta {
+		return fmt.Sprintf("%s# GG:%s %s", indent, entry.key, entry.formatValue())
+	}
+
+	return fmt.Sprintf("%s%s %s", indent, entry.key, entry.formatValue())
+}
+
+// formatValue - returns the value as it should be written to the file. Metadata is a free text, while
+// directive values are quoted, if they contain spaces or other special characters.
+func (e blockEntry) formatValue() string {
+	if e.isMeta {
+		return e.value
+	}
+
+	return quoteArgument(e.value)
+}
+
+var indentRe = regexp.MustCompile(`^\s+`)
+
+// blockIndent returns indentation which is used by the first indented line of the block.
+func blockIndent(lines []string, start, end int) string {
+	for i := start + 1; i < end; i++ {
+		if indent := indentRe.FindString(lines[i]); indent != "" && !isBlankLine(lines[i]) {
+			return indent
+		}
+	}
+
+	return defaultIndent
+}
+
+// metaValueRe splits '# GG:' metadata line into a key and a value.
+//
+// "  # GG:GROUP: Production" -> "  # GG:GROUP:", " ", "Production".
+var metaValueRe = regexp.MustCompile(`^(\s*#\s*GG:\w+:?)(\s*)(.*)$`)
+
+// replaceLineValue replaces the value of the line, but keeps the key, the separator, indentation and
+// inline comments. Value must be quoted by the caller, if necessary.
+func replaceLineValue(line, value string) string {
+	if metaKey(line) != "" {
+		matches := metaValueRe.FindStringSubmatch(line)
+		return fmt.Sprintf("%s %s", matches[1], value)
+	}
+
+	d, err := parseDirective(line)
+	if err != nil || len(d.args) == 0 {
+		return fmt.Sprintf("%s %s", strings.TrimRight(line, " \t"), value)
+	}
+
+	return line[:d.valueStart] + value + line[d.valueEnd:]
+}
+
+// splitLine returns the keyword and the value of ssh_config directive as it's written in the line,
+// without inline comment. Comments, blank lines and malformed lines produce empty keyword.
+func splitLine(line string) (string, string) {
+	d, err := parseDirective(line)
+	if err != nil {
+		return "", ""
+	}
+
+	return d.keyword, d.value(line)
+}
+
+var metaKeyRe = regexp.MustCompile(`^\s*#\s*GG:(\w+)`)
+
+// metaKey returns the name of '# GG:' metadata line, for instance "GROUP" or an empty string.
+func metaKey(line string) string {
+	matches := metaKeyRe.FindStringSubmatch(line)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+
+	return ""
+}
+
+func isBlankLine(line string) bool {
+	return utils.StringEmpty(&line)
+}
@@ -0,0 +1,289 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	model "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+const writerTestConfig = `# Global comment
+Include ~/.ssh/config.d/*
+
+Host alpha
+    # GG:GROUP: Production
+    # Just a comment
+    HostName alpha.example.com # inline comment
+    User root
+    ForwardAgent yes
+
+# Comment which belongs to beta
+Host beta
+	HostName beta.example.com
+
+Host *
+    ServerAliveInterval 30
+`
+
+func writeTestConfig(t *testing.T, content string) string {
+	t.Helper()
+	filePath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.WriteFile(filePath, []byte(content), 0o600))
+
+	return filePath
+}
+
+func readTestConfig(t *testing.T, filePath string) string {
+	t.Helper()
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+
+	return string(data)
+}
+
+func TestWriter_SaveHost_UpdateExisting(t *testing.T) {
+	filePath := writeTestConfig(t, writerTestConfig)
+	w := NewWriter(&mocklogger.Logger{})
+
+	err := w.SaveHost(filePath, "alpha", model.Host{
+		Title:            "alpha2",
+		Address:          "10.0.0.1",
+		Group:            "Staging",
+		Description:      "Database",
+		LoginName:        "",
+		RemotePort:       "2222",
+		IdentityFilePath: "~/.ssh/id_ed25519",
+	})
+	require.NoError(t, err)
+
+	expected := `# Global comment
+Include ~/.ssh/config.d/*
+
+Host alpha2
+    # GG:GROUP: Staging
+    # GG:DESCRIPTION Database
+    # Just a comment
+    HostName 10.0.0.1 # inline comment
+    ForwardAgent yes
+    Port 2222
+    IdentityFile ~/.ssh/id_ed25519
+
+# Comment which belongs to beta
+Host beta
+	HostName beta.example.com
+
+Host *
+    ServerAliveInterval 30
+`
+	require.Equal(t, expected, readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_Aliases(t *testing.T) {
+	filePath := writeTestConfig(t, "Host web1 web1.internal # web server\n  HostName 10.0.0.1\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	// Host is found by the first pattern, and aliases are preserved when it's renamed.
+	err := w.SaveHost(filePath, "web1", model.Host{Title: "web2", Address: "10.0.0.2"})
+	require.NoError(t, err)
+	require.Equal(t, "Host web2 web1.internal # web server\n  HostName 10.0.0.2\n", readTestConfig(t, filePath))
+
+	require.NoError(t, w.DeleteHost(filePath, "web2"))
+	require.Empty(t, readTestConfig(t, filePath))
+
+	// Host is found by the first alias, patterns before it are preserved.
+	filePath = writeTestConfig(t, "Host *.prod\n  User root\n\nHost *.prod web1 web1.internal\n  HostName 10.0.0.1\n")
+	err = w.SaveHost(filePath, "web1", model.Host{Title: "web2", Address: "10.0.0.2"})
+	require.NoError(t, err)
+	require.Equal(t, "Host *.prod\n  User root\n\nHost *.prod web2 web1.internal\n  HostName 10.0.0.2\n",
+		readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_SymlinkAndPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require extra privileges on Windows")
+	}
+
+	targetPath := writeTestConfig(t, "Host alpha\n")
+	require.NoError(t, os.Chmod(targetPath, 0o640))
+	linkPath := filepath.Join(t.TempDir(), "config")
+	require.NoError(t, os.Symlink(targetPath, linkPath))
+
+	// The file is replaced, but the link and the file permissions are kept.
+	w := NewWriter(&mocklogger.Logger{})
+	require.NoError(t, w.SaveHost(linkPath, "alpha", model.Host{Title: "alpha", Address: "alpha", LoginName: "root"}))
+	require.Equal(t, "Host alpha\n  User root\n", readTestConfig(t, targetPath))
+
+	linkInfo, err := os.Lstat(linkPath)
+	require.NoError(t, err)
+	require.Equal(t, os.ModeSymlink, linkInfo.Mode().Type())
+	targetInfo, err := os.Stat(targetPath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o640), targetInfo.Mode().Perm())
+}
+
+func TestWriter_SaveHost_KeyValueSyntax(t *testing.T) {
+	filePath := writeTestConfig(t, "Host=alpha\n  User = root # admin\n  IdentityFile \"~/old key\"\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	// Separators and comments are preserved, values with spaces are quoted.
+	err := w.SaveHost(filePath, "alpha", model.Host{
+		Title:            "alpha",
+		Address:          "alpha",
+		LoginName:        "deploy",
+		IdentityFilePath: "~/My Keys/id_ed25519",
+	})
+	require.NoError(t, err)
+	require.Equal(t,
+		"Host=alpha\n  User = deploy # admin\n  IdentityFile \"~/My Keys/id_ed25519\"\n",
+		readTestConfig(t, filePath))
+
+	err = w.SaveHost(filePath, "", model.Host{Title: "beta", Address: "beta", IdentityFilePath: "~/My Keys/id_rsa"})
+	require.NoError(t, err)
+	require.Contains(t, readTestConfig(t, filePath), "Host beta\n  IdentityFile \"~/My Keys/id_rsa\"\n")
+}
+
+func TestWriter_SaveHost_DefaultValuesNotWritten(t *testing.T) {
+	filePath := writeTestConfig(t, "Host gamma\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	// HostName equals to the alias and group is the default one, nothing should be written.
+	err := w.SaveHost(filePath, "gamma", model.Host{
+		Title:   "gamma",
+		Address: "gamma",
+		Group:   putSSHConfigHostsIntoGroupName,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Host gamma\n", readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_Tags(t *testing.T) {
+	filePath := writeTestConfig(t, "Host alpha\n  # GG:TAGS prod, db\n  User root\n  # GG:TAGS eu-west\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	// Tags are merged when the file is read, so they're written to the first line.
+	err := w.SaveHost(filePath, "alpha", model.Host{Title: "alpha", Address: "alpha", LoginName: "root",
+		Tags: []string{"prod", "eu-west"}})
+	require.NoError(t, err)
+	require.Equal(t, "Host alpha\n  # GG:TAGS prod, eu-west\n  User root\n", readTestConfig(t, filePath))
+
+	err = w.SaveHost(filePath, "alpha", model.Host{Title: "alpha", Address: "alpha", LoginName: "root"})
+	require.NoError(t, err)
+	require.Equal(t, "Host alpha\n  User root\n", readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_AppendNew(t *testing.T) {
+	filePath := writeTestConfig(t, "Host alpha\n  HostName alpha.com\n\n\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	err := w.SaveHost(filePath, "", model.Host{
+		Title:     "beta",
+		Address:   "beta.com",
+		Group:     "Dev",
+		LoginName: "alice",
+	})
+	require.NoError(t, err)
+
+	expected := "Host alpha\n  HostName alpha.com\n\nHost beta\n  # GG:GROUP Dev\n  HostName beta.com\n  User alice\n"
+	require.Equal(t, expected, readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_IPv6(t *testing.T) {
+	filePath := writeTestConfig(t, "Host alpha\n  HostName alpha.com\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	// ssh expects IPv6 address without square brackets.
+	err := w.SaveHost(filePath, "alpha", model.Host{Title: "alpha", Address: "[2001:db8::10]"})
+	require.NoError(t, err)
+	require.Equal(t, "Host alpha\n  HostName 2001:db8::10\n", readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_ID(t *testing.T) {
+	filePath := writeTestConfig(t, "Host alpha\n  HostName alpha.com\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	// Host ID must be persisted, so that the host keeps the same ID after it is renamed.
+	err := w.SaveHost(filePath, "alpha", model.Host{ID: "alpha-id", Title: "beta", Address: "alpha.com"})
+	require.NoError(t, err)
+	require.Equal(t, "Host beta\n  # GG:ID alpha-id\n  HostName alpha.com\n", readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_CRLF(t *testing.T) {
+	filePath := writeTestConfig(t, "Host alpha\r\n  User root\r\n")
+	w := NewWriter(&mocklogger.Logger{})
+
+	err := w.SaveHost(filePath, "alpha", model.Host{Title: "alpha", Address: "alpha", LoginName: "bob"})
+	require.NoError(t, err)
+	require.Equal(t, "Host alpha\r\n  User bob\r\n", readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_NotFound(t *testing.T) {
+	filePath := writeTestConfig(t, writerTestConfig)
+	w := NewWriter(&mocklogger.Logger{})
+
+	err := w.SaveHost(filePath, "no_such_host", model.Host{Title: "x", Address: "x"})
+	require.ErrorIs(t, err, ErrHostNotFound)
+	require.Equal(t, writerTestConfig, readTestConfig(t, filePath))
+}
+
+func TestWriter_SaveHost_RemoteFile(t *testing.T) {
+	w := NewWriter(&mocklogger.Logger{})
+	err := w.SaveHost("https://example.com/config", "alpha", model.Host{Title: "alpha", Address: "alpha"})
+	require.Error(t, err)
+}
+
+func TestWriter_DeleteHost(t *testing.T) {
+	filePath := writeTestConfig(t, writerTestConfig)
+	w := NewWriter(&mocklogger.Logger{})
+
+	err := w.DeleteHost(filePath, "alpha")
+	require.NoError(t, err)
+
+	expected := `# Global comment
+Include ~/.ssh/config.d/*
+
+# Comment which belongs to beta
+Host beta
+	HostName beta.example.com
+
+Host *
+    ServerAliveInterval 30
+`
+	require.Equal(t, expected, readTestConfig(t, filePath))
+
+	err = w.DeleteHost(filePath, "alpha")
+	require.ErrorIs(t, err, ErrHostNotFound)
+}
+
+func TestValidateHost(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      model.Host
+		wantError bool
+	}{
+		{"Valid host", model.Host{Title: "alpha", Address: "alpha.com", LoginName: "root", RemotePort: "22"}, false},
+		{"Address equals to title", model.Host{Title: "alpha_1", Address: "alpha_1"}, false},
+		{"Empty title", model.Host{Title: "", Address: "alpha.com"}, true},
+		{"Title with spaces", model.Host{Title: "alpha (1)", Address: "alpha.com"}, true},
+		{"Title with wildcard", model.Host{Title: "alpha*", Address: "alpha.com"}, true},
+		{"IPv6 address", model.Host{Title: "alpha", Address: "[fe80::1%eth0]"}, false},
+		{"Invalid IPv6 address", model.Host{Title: "alpha", Address: "2001:db8::10::1"}, true},
+		{"Address with spaces", model.Host{Title: "alpha", Address: "alpha.com -p 22"}, true},
+		{"Invalid user", model.Host{Title: "alpha", Address: "alpha.com", LoginName: "user!"}, true},
+		{"Invalid port", model.Host{Title: "alpha", Address: "alpha.com", RemotePort: "port"}, true},
+		{"Multiline description", model.Host{Title: "alpha", Address: "alpha.com", Description: "a\nb"}, true},
+		{"Valid tags", model.Host{Title: "alpha", Address: "alpha.com", Tags: []string{"prod", "eu-west"}}, false},
+		{"Tag with spaces", model.Host{Title: "alpha", Address: "alpha.com", Tags: []string{"eu west"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHost(tt.host)
+			require.Equal(t, tt.wantError, err != nil, "unexpected result: %v", err)
+		})
+	}
+}
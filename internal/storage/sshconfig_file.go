@@ -3,8 +3,10 @@ package storage
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"slices"
+	"strings"
 
 	"github.com/samber/lo"
 
@@ -13,30 +15,45 @@ import (
 	sshConfigSettings "github.com/grafviktor/goto/internal/model/sshconfig"
 	"github.com/grafviktor/goto/internal/state"
 	"github.com/grafviktor/goto/internal/storage/sshconfig"
+	"github.com/grafviktor/goto/internal/utils"
 )
 
 var _ HostStorage = &SSHConfigFile{}
 
+// urlCacheFolder - a sub-folder of the application home, where copies of remote ssh_config files are stored.
+const urlCacheFolder = "cache"
+
 // ErrNotSupported - is an error which is returned when trying to save or
-// delete host in SSHConfigFile storage.
+// delete host which was loaded from a remote ssh_config file.
 var ErrNotSupported = errors.New("readonly storage, edit ssh config directly")
 
 type sshLexer interface {
 	Tokenize() ([]sshconfig.SSHToken, error)
 	GetRawData() []byte
+	GetLocalPaths() []string
 }
 
 type sshParser interface {
 	Parse() ([]model.Host, error)
+	Patterns() []model.PatternBlock
+}
+
+type sshWriter interface {
+	SaveHost(filePath, originalTitle string, host model.Host) error
+	DeleteHost(filePath, title string) error
 }
 
 // SSHConfigFile - is a storage which contains hosts loaded from SSH config file.
 type SSHConfigFile struct {
 	fileLexer     sshLexer
 	fileParser    sshParser
-	innerStorage  map[int]model.Host
+	fileWriter    sshWriter
+	innerStorage  map[string]model.Host
 	appState      *state.State
+	logger        iLogger
 	sshConfigCopy *os.File
+	// source is only set for additional ssh_config sources, which are readonly.
+	source string
 }
 
 // newSSHConfigStorage - constructs new SSHStorage.
@@ -50,61 +67,188 @@ func newSSHConfigStorage(
 	return &SSHConfigFile{
 		fileLexer:  lexer,
 		fileParser: parser,
+		fileWriter: sshconfig.NewWriter(logger),
 		appState:   st,
+		logger:     logger,
+	}
+}
+
+// newAdditionalSSHConfigStorage - constructs readonly SSHStorage for an additional ssh_config file or URL.
+func newAdditionalSSHConfigStorage(
+	_ context.Context,
+	st *state.State,
+	source string,
+	logger iLogger,
+) *SSHConfigFile {
+	lexer := sshconfig.NewFileLexer(source, logger)
+	parser := sshconfig.NewParser(lexer, logger)
+	return &SSHConfigFile{
+		fileLexer:  lexer,
+		fileParser: parser,
+		fileWriter: sshconfig.NewWriter(logger),
+		appState:   st,
+		logger:     logger,
+		source:     source,
 	}
 }
 
 // GetAll - returns all hosts.
 func (s *SSHConfigFile) GetAll() ([]model.Host, error) {
-	// Optimization - this is a readonly storage. Therefore, it's pointless to reload
-	// hosts from the file if they are already loaded. That especially increases
-	// the performance when the app read hosts from a remote location.
+	// Optimization - all changes made by the app are applied to both ssh_config file and
+	// innerStorage. Therefore, it's pointless to reload hosts from the file if they are
+	// already loaded. That especially increases the performance when the app read hosts
+	// from a remote location.
 	if s.innerStorage == nil {
 		hosts, err := s.fileParser.Parse()
-		if err != nil {
+		if err != nil && utils.StringEmpty(&s.source) {
 			return nil, err
+		} else if err != nil {
+			// Additional source should not prevent user from accessing other hosts.
+			s.logger.Error("[STORAGE] Cannot load hosts from ssh_config source %q: %v", s.source, err)
+			hosts = []model.Host{}
 		}
 
-		err = s.createTempSSHConfigCopy()
+		err = s.updateTempSSHConfigCopy()
 		if err != nil {
 			return nil, err
 		}
 
-		s.activateTempSSHConfig()
-
-		s.innerStorage = make(map[int]model.Host, len(hosts))
-		for i := range hosts {
-			// Make sure that not assigning '0' as host id, because '0' is empty host identifier.
-			// Consider to use '-1' for all new hostnames.
-			hosts[i].ID = i + 1
-			s.innerStorage[i+1] = hosts[i]
+		// Host IDs are assigned by the parser, either from '# GG:ID' metadata or derived from the host alias.
+		s.innerStorage = make(map[string]model.Host, len(hosts))
+		for _, host := range hosts {
+			// Storage type is required to check whether the host is readonly.
+			host.StorageType = s.Type()
+			host.SSHConfigSource = s.source
+			s.innerStorage[host.ID] = host
 		}
 	}
 
 	values := lo.Values(s.innerStorage)
 	// Map does not guarantee order, so we need to sort the collection.
 	slices.SortFunc(values, func(a, b model.Host) int {
-		if a.ID < b.ID {
-			return -1
-		}
-		return 1
+		return strings.Compare(a.Title, b.Title)
 	})
 	return values, nil
 }
 
 // Get - returns host by ID.
-func (s *SSHConfigFile) Get(hostID int) (model.Host, error) {
-	return s.innerStorage[hostID], nil
+func (s *SSHConfigFile) Get(hostID string) (model.Host, error) {
+	host, ok := s.innerStorage[hostID]
+	if !ok {
+		return model.Host{}, constant.ErrNotFound
+	}
+
+	return host, nil
 }
 
-// Save - throws not supported error.
+// Save - updates host declaration in ssh_config file or appends a new one. When the
+// host is new, it is appended to the file which is set in host.SourcePath, which is
+// the case when a host is cloned, otherwise it goes to the root ssh_config file.
 func (s *SSHConfigFile) Save(host model.Host) (model.Host, error) {
-	return host, ErrNotSupported
+	original, exists := s.innerStorage[host.ID]
+	if !exists {
+		return s.create(host)
+	}
+
+	if original.IsReadOnly() {
+		return host, ErrNotSupported
+	}
+
+	if original.Title != host.Title && s.titleExists(host.Title) {
+		return host, fmt.Errorf("host %q already exists in ssh_config", host.Title)
+	}
+
+	host.SourcePath = original.SourcePath
+	host.StorageType = s.Type()
+	err := s.fileWriter.SaveHost(original.SourcePath, original.Title, host)
+	if err != nil {
+		s.logger.Error("[STORAGE] Cannot save host %q to ssh_config: %v", host.Title, err)
+		return host, err
+	}
+
+	s.innerStorage[host.ID] = host
+	s.refreshTempSSHConfigCopy()
+
+	return host, nil
 }
 
-// Delete - throws not supported error.
-func (s *SSHConfigFile) Delete(_ int) error {
-	return ErrNotSupported
+func (s *SSHConfigFile) create(host model.Host) (model.Host, error) {
+	if utils.StringEmpty(&host.SourcePath) && s.appState != nil {
+		host.SourcePath = s.appState.SSHConfigPath
+	}
+
+	host.StorageType = s.Type()
+	host.SSHConfigSource = s.source
+	if host.IsReadOnly() {
+		return host, ErrNotSupported
+	}
+
+	if s.titleExists(host.Title) {
+		return host, fmt.Errorf("host %q already exists in ssh_config", host.Title)
+	}
+
+	if utils.StringEmpty(&host.ID) {
+		host.ID = model.NewID()
+	}
+
+	err := s.fileWriter.SaveHost(host.SourcePath, "", host)
+	if err != nil {
+		s.logger.Error("[STORAGE] Cannot append host %q to ssh_config: %v", host.Title, err)
+		return host, err
+	}
+
+	if s.innerStorage == nil {
+		s.innerStorage = make(map[string]model.Host)
+	}
+
+	s.innerStorage[host.ID] = host
+	s.refreshTempSSHConfigCopy()
+
+	return host, nil
+}
+
+// Delete - removes host declaration from ssh_config file.
+func (s *SSHConfigFile) Delete(hostID string) error {
+	host, exists := s.innerStorage[hostID]
+	if !exists {
+		return constant.ErrNotFound
+	}
+
+	if host.IsReadOnly() {
+		return ErrNotSupported
+	}
+
+	err := s.fileWriter.DeleteHost(host.SourcePath, host.Title)
+	if err != nil {
+		s.logger.Error("[STORAGE] Cannot delete host %q from ssh_config: %v", host.Title, err)
+		return err
+	}
+
+	delete(s.innerStorage, hostID)
+	s.refreshTempSSHConfigCopy()
+
+	return nil
+}
+
+func (s *SSHConfigFile) titleExists(title string) bool {
+	return lo.SomeBy(lo.Values(s.innerStorage), func(h model.Host) bool {
+		return h.Title == title
+	})
+}
+
+// Patterns - returns wildcard "Host" blocks and global options, which were read by the last GetAll call.
+func (s *SSHConfigFile) Patterns() []model.PatternBlock {
+	return s.fileParser.Patterns()
+}
+
+// WatchedFiles - returns local ssh_config files and folders referenced by Include directives.
+func (s *SSHConfigFile) WatchedFiles() []string {
+	return s.fileLexer.GetLocalPaths()
+}
+
+// Reload - drops loaded hosts, so that the next GetAll call parses ssh_config files again.
+func (s *SSHConfigFile) Reload() {
+	s.innerStorage = nil
 }
 
 // Type - returns storage type.
@@ -130,8 +274,39 @@ func (s *SSHConfigFile) createTempSSHConfigCopy() error {
 	return err
 }
 
+// refreshTempSSHConfigCopy - re-reads ssh_config files after modification, so that
+// ssh process receives the actual version of the file, see activateTempSSHConfig.
+func (s *SSHConfigFile) refreshTempSSHConfigCopy() {
+	if _, err := s.fileLexer.Tokenize(); err != nil {
+		s.logger.Error("[STORAGE] Cannot re-read ssh_config: %v", err)
+		return
+	}
+
+	if err := s.updateTempSSHConfigCopy(); err != nil {
+		s.logger.Error("[STORAGE] Cannot update ssh_config copy: %v", err)
+	}
+}
+
+// updateTempSSHConfigCopy - creates ssh_config copy on first call and overwrites it afterwards.
+func (s *SSHConfigFile) updateTempSSHConfigCopy() error {
+	if s.sshConfigCopy == nil {
+		if err := s.createTempSSHConfigCopy(); err != nil {
+			return err
+		}
+
+		s.activateTempSSHConfig()
+		return nil
+	}
+
+	return os.WriteFile(s.sshConfigCopy.Name(), s.fileLexer.GetRawData(), 0o600)
+}
+
 func (s *SSHConfigFile) activateTempSSHConfig() {
-	sshConfigSettings.SetPath(s.sshConfigCopy.Name())
+	if utils.StringEmpty(&s.source) {
+		sshConfigSettings.SetPath(s.sshConfigCopy.Name())
+	} else {
+		sshConfigSettings.SetPathForSource(s.source, s.sshConfigCopy.Name())
+	}
 }
 
 func (s *SSHConfigFile) Close() {
@@ -3,6 +3,8 @@ package storage
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -10,6 +12,7 @@ import (
 	"github.com/grafviktor/goto/internal/config"
 	"github.com/grafviktor/goto/internal/constant"
 	model "github.com/grafviktor/goto/internal/model/host"
+	sshConfigModel "github.com/grafviktor/goto/internal/model/sshconfig"
 	"github.com/grafviktor/goto/internal/state"
 	"github.com/grafviktor/goto/internal/storage/sshconfig"
 	"github.com/grafviktor/goto/internal/testutils/mocklogger"
@@ -25,15 +28,24 @@ func (m *mockSSHLexer) GetRawData() []byte {
 	return []byte{}
 }
 
+func (m *mockSSHLexer) GetLocalPaths() []string {
+	return []string{}
+}
+
 type mockSSHParser struct {
-	hosts []model.Host
-	err   error
+	hosts    []model.Host
+	patterns []model.PatternBlock
+	err      error
 }
 
 func (m *mockSSHParser) Parse() ([]model.Host, error) {
 	return m.hosts, m.err
 }
 
+func (m *mockSSHParser) Patterns() []model.PatternBlock {
+	return m.patterns
+}
+
 func TestNewSSHConfigStorageLocalFile(t *testing.T) {
 	st, err := state.Initialize(context.TODO(), &config.Configuration{}, &mocklogger.Logger{})
 	require.NoError(t, err)
@@ -46,8 +58,8 @@ func TestNewSSHConfigStorageLocalFile(t *testing.T) {
 
 func TestSSHConfigFile_GetAll(t *testing.T) {
 	mockHosts := []model.Host{
-		{Title: "host1", Address: "host1.com"},
-		{Title: "host2", Address: "host2.com"},
+		{ID: "id2", Title: "host2", Address: "host2.com"},
+		{ID: "id1", Title: "host1", Address: "host1.com"},
 	}
 
 	st, err := state.Initialize(
@@ -67,8 +79,8 @@ func TestSSHConfigFile_GetAll(t *testing.T) {
 	require.Len(t, hosts, 2)
 	require.Equal(t, "host1", hosts[0].Title)
 	require.Equal(t, "host2", hosts[1].Title)
-	require.Equal(t, 1, hosts[0].ID)
-	require.Equal(t, 2, hosts[1].ID)
+	require.Equal(t, "id1", hosts[0].ID)
+	require.Equal(t, "id2", hosts[1].ID)
 	// It's required for Windows to release the temp file, we're closing it in storage.Close().
 	s.Close()
 }
@@ -84,7 +96,7 @@ func TestSSHConfigFile_GetAll_Error(t *testing.T) {
 
 func TestSSHConfigFile_Get(t *testing.T) {
 	mockHosts := []model.Host{
-		{Title: "host1", Address: "host1.com"},
+		{ID: "1", Title: "host1", Address: "host1.com"},
 	}
 
 	st, err := state.Initialize(context.TODO(), &config.Configuration{}, &mocklogger.Logger{})
@@ -97,20 +109,132 @@ func TestSSHConfigFile_Get(t *testing.T) {
 	}
 
 	_, _ = s.GetAll()
-	h, err := s.Get(1)
+	h, err := s.Get("1")
 	require.NoError(t, err)
 	require.Equal(t, "host1", h.Title)
 	s.Close() // It's required for Windows to release the temp file
 }
 
-func TestSSHConfigFile_Save_Delete(t *testing.T) {
-	s := &SSHConfigFile{}
-	h, err := s.Save(model.Host{})
+type mockSSHWriter struct {
+	saved   []model.Host
+	deleted []string
+	err     error
+}
+
+func (m *mockSSHWriter) SaveHost(_, _ string, host model.Host) error {
+	m.saved = append(m.saved, host)
+	return m.err
+}
+
+func (m *mockSSHWriter) DeleteHost(_, title string) error {
+	m.deleted = append(m.deleted, title)
+	return m.err
+}
+
+func TestSSHConfigFile_Save_Delete_RemoteHost(t *testing.T) {
+	remoteHost := model.Host{
+		ID:          "1",
+		Title:       "remote",
+		SourcePath:  "https://example.com/ssh_config",
+		StorageType: constant.HostStorageType.SSHConfig,
+	}
+	s := &SSHConfigFile{
+		innerStorage: map[string]model.Host{"1": remoteHost},
+		fileWriter:   &mockSSHWriter{},
+		logger:       &mocklogger.Logger{},
+	}
+
+	h, err := s.Save(remoteHost)
 	require.ErrorIs(t, err, ErrNotSupported)
-	require.Equal(t, model.Host{}, h)
+	require.Equal(t, remoteHost, h)
+
+	err = s.Delete("1")
+	require.ErrorIs(t, err, ErrNotSupported)
+}
+
+func TestSSHConfigFile_Save_Delete_LocalHost(t *testing.T) {
+	localHost := model.Host{
+		ID:          "1",
+		Title:       "local",
+		SourcePath:  "/home/user/.ssh/config",
+		StorageType: constant.HostStorageType.SSHConfig,
+	}
+	writer := &mockSSHWriter{}
+	s := &SSHConfigFile{
+		innerStorage:  map[string]model.Host{"1": localHost},
+		fileLexer:     &mockSSHLexer{},
+		fileWriter:    writer,
+		logger:        &mocklogger.Logger{},
+		sshConfigCopy: nil,
+	}
+	defer s.Close()
+
+	// Update existing host
+	localHost.Address = "example.com"
+	h, err := s.Save(localHost)
+	require.NoError(t, err)
+	require.Equal(t, "example.com", h.Address)
+	require.Equal(t, "example.com", s.innerStorage["1"].Address)
+
+	// Clone host, cloned host should be appended to the same file
+	cloned := localHost.Clone()
+	cloned.Title = "local-1"
+	h, err = s.Save(cloned)
+	require.NoError(t, err)
+	require.NotEmpty(t, h.ID)
+	require.NotEqual(t, "1", h.ID)
+	require.Equal(t, "/home/user/.ssh/config", h.SourcePath)
+	require.Len(t, writer.saved, 2)
+
+	// Cannot create a host with duplicate alias
+	_, err = s.Save(cloned)
+	require.Error(t, err)
+
+	err = s.Delete("1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"local"}, writer.deleted)
+	require.NotContains(t, s.innerStorage, "1")
+
+	err = s.Delete("1")
+	require.ErrorIs(t, err, constant.ErrNotFound)
+}
 
-	err = s.Delete(1)
+func TestSSHConfigFile_AdditionalSource(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "work_config")
+	require.NoError(t, os.WriteFile(source, []byte("Host work\n  HostName work.example.com\n"), 0o600))
+
+	logger := &mocklogger.Logger{}
+	s := newAdditionalSSHConfigStorage(context.TODO(), &state.State{}, source, logger)
+	defer s.Close()
+
+	hosts, err := s.GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, source, hosts[0].SSHConfigSource)
+	require.True(t, hosts[0].IsReadOnly())
+	// ssh reads the copy of the source, which is created by the storage.
+	require.Equal(t, s.sshConfigCopy.Name(), sshConfigModel.PathForSource(source))
+
+	_, err = s.Save(hosts[0])
+	require.ErrorIs(t, err, ErrNotSupported)
+	_, err = s.Save(hosts[0].Clone())
 	require.ErrorIs(t, err, ErrNotSupported)
+	require.ErrorIs(t, s.Delete(hosts[0].ID), ErrNotSupported)
+}
+
+func TestSSHConfigFile_AdditionalSource_Error(t *testing.T) {
+	s := &SSHConfigFile{
+		fileLexer:  &mockSSHLexer{},
+		fileParser: &mockSSHParser{err: errors.New("network error")},
+		logger:     &mocklogger.Logger{},
+		source:     "https://example.com/ssh_config",
+	}
+	defer s.Close()
+
+	// Additional source does not prevent the app from loading other hosts.
+	hosts, err := s.GetAll()
+	require.NoError(t, err)
+	require.Empty(t, hosts)
 }
 
 func TestSSHConfigFile_Type(t *testing.T) {
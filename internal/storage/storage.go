@@ -2,19 +2,26 @@
 package storage
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"path"
 	"slices"
+	"time"
 
 	"github.com/samber/lo"
 
 	"github.com/grafviktor/goto/internal/constant"
 	model "github.com/grafviktor/goto/internal/model/host"
 	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/utils"
 )
 
 var (
-	_                      HostStorage = &combinedStorage{}
-	defaultHostStorageType             = constant.HostStorageType.YAMLFile
+	_ HostStorage     = &combinedStorage{}
+	_ Refreshable     = &combinedStorage{}
+	_ PatternLister   = &combinedStorage{}
+	_ WarningReporter = &combinedStorage{}
 )
 
 type iLogger interface {
@@ -26,136 +33,252 @@ type iLogger interface {
 // HostStorage defines CRUD operations for Host model.
 type HostStorage interface {
 	GetAll() ([]model.Host, error)
-	Get(hostID int) (model.Host, error)
+	Get(hostID string) (model.Host, error)
 	Save(model.Host) (model.Host, error)
-	Delete(id int) error
+	Delete(hostID string) error
 	Type() constant.HostStorageEnum
 	Close()
 }
 
-type hostStorageMapping struct {
-	storageType       constant.HostStorageEnum
-	combinedStorageID int
-	innerStorageID    int
+// Watchable - is implemented by storages which notify when hosts are modified outside of the app.
+type Watchable interface {
+	// Changes - returns a channel which receives a value when the storage files are modified.
+	Changes() <-chan struct{}
+}
+
+// InventoryLister - is implemented by storages which keep hosts in several YAML files.
+type InventoryLister interface {
+	// Inventories - returns paths of the host files. The default file, where new hosts are stored, comes first.
+	Inventories() []string
+}
+
+// PatternLister - is implemented by storages which load hosts from ssh_config files.
+type PatternLister interface {
+	// Patterns - returns wildcard "Host" blocks, which are not displayed in the host list, but their
+	// options apply to the hosts.
+	Patterns() []model.PatternBlock
+}
+
+// WarningReporter - is implemented by storages which skip hosts they cannot display.
+type WarningReporter interface {
+	// Warnings - returns problems found by the last GetAll call, for instance, hosts which were ignored.
+	Warnings() []string
+}
+
+// Refreshable - is implemented by storages which load hosts from remote locations.
+type Refreshable interface {
+	// Refresh - forces the storage to read all files again, including remote ones, on the next GetAll call.
+	Refresh()
+	// OfflineSince - returns the time when cached copies of remote files were fetched. The second value
+	// is false unless cached copies are used because the remote location is unreachable.
+	OfflineSince() (time.Time, bool)
+}
+
+// reloadable - is implemented by storages which are backed by local files.
+type reloadable interface {
+	WatchedFiles() []string
+	Reload()
+}
+
+// fileStorage - is implemented by storages which keep all hosts in a single file.
+type fileStorage interface {
+	FilePath() string
 }
 
 type combinedStorage struct {
-	hosts          map[int]model.Host
-	storages       map[constant.HostStorageEnum]HostStorage
+	hosts map[string]model.Host
+	// storages are kept in the order they were configured.
+	storages []HostStorage
+	// defaultStorage is used for new hosts, which do not belong to any storage yet.
+	defaultStorage HostStorage
 	logger         iLogger
-	nextID         int
-	hostStorageMap map[int]hostStorageMapping
+	// hostStorageMap is keyed by host ID and keeps the storage, where the host is stored.
+	hostStorageMap map[string]HostStorage
+	watcher        *fileWatcher
+	stopWatcher    context.CancelFunc
+	// forceReload is set by Refresh, and makes GetAll reload all storages.
+	forceReload bool
+	// warnings are collected by GetAll, see WarningReporter.
+	warnings []string
 }
 
 // Initialize - prepares inner storages and returns a common HostStorage interface to load and save hosts.
 func Initialize(ctx context.Context, st *state.State, logger iLogger) (HostStorage, error) {
-	storages := getStorages(ctx, st, logger)
+	storages, defaultStorage, err := getStorages(ctx, st, logger)
+	if err != nil {
+		return nil, err
+	}
 
+	watcherCtx, stopWatcher := context.WithCancel(ctx)
 	cs := combinedStorage{
 		storages:       storages,
-		hostStorageMap: make(map[int]hostStorageMapping),
-		hosts:          make(map[int]model.Host),
+		defaultStorage: defaultStorage,
+		hostStorageMap: make(map[string]HostStorage),
+		hosts:          make(map[string]model.Host),
 		logger:         logger,
+		watcher:        newFileWatcher(watchInterval, logger),
+		stopWatcher:    stopWatcher,
 	}
 
+	go cs.watcher.run(watcherCtx)
+
 	return &cs, nil
 }
 
+// getStorages - returns inner storages and the storage, where new hosts should be saved.
 func getStorages(
 	ctx context.Context,
 	st *state.State,
 	logger iLogger,
-) map[constant.HostStorageEnum]HostStorage {
-	storageMap := make(map[constant.HostStorageEnum]HostStorage)
-	yamlStorage := newYAMLStorage(ctx, st.AppHome, logger)
-	storageMap[yamlStorage.Type()] = yamlStorage
+) ([]HostStorage, HostStorage, error) {
+	var yamlStorage *yamlFile
+	if IsEncrypted(st.AppHome) {
+		logger.Info("[STORAGE] Load hosts from encrypted file")
+		key := EncryptionKey{Passphrase: st.Passphrase, KeyFile: st.KeyFile}
+		yamlStorage = newEncryptedYAMLStorage(ctx, st.AppHome, st.BackupCount, key, logger)
+	} else {
+		yamlStorage = newYAMLStorage(ctx, st.AppHome, st.BackupCount, logger)
+	}
+
+	storages := []HostStorage{yamlStorage}
+	inventories, err := getInventoryStorages(ctx, st, yamlStorage.FilePath(), logger)
+	if err != nil {
+		return nil, nil, err
+	}
+	storages = append(storages, inventories...)
+
+	defaultStorage, err := getDefaultStorage(ctx, st, storages, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !slices.Contains(storages, defaultStorage) {
+		storages = append(storages, defaultStorage)
+	}
 
 	sshConfigEnabled := st.SSHConfigEnabled
 	logger.Debug("[STORAGE] SSH config storage enable: '%t'", sshConfigEnabled)
 	if sshConfigEnabled {
+		// Remote ssh_config files are cached, so that hosts are available when the network is down.
+		utils.SetURLCacheFolder(path.Join(st.AppHome, urlCacheFolder))
+		utils.SetURLAccess(st.RemoteAccess)
 		logger.Info("[STORAGE] Load ssh hosts from ssh config file: %q", st.SSHConfigPath)
-		sshConfigStorage := newSSHConfigStorage(ctx, st, logger)
-		storageMap[sshConfigStorage.Type()] = sshConfigStorage
+		storages = append(storages, newSSHConfigStorage(ctx, st, logger))
+		for _, source := range lo.Uniq(st.SSHConfigSources) {
+			if source == st.SSHConfigPath {
+				continue
+			}
+
+			logger.Info("[STORAGE] Load ssh hosts from additional ssh config source: %q", source)
+			storages = append(storages, newAdditionalSSHConfigStorage(ctx, st, source, logger))
+		}
 	}
 
-	return storageMap
+	return storages, defaultStorage, nil
 }
 
 // Delete implements HostStorage.
-func (c *combinedStorage) Delete(hostID int) error {
+func (c *combinedStorage) Delete(hostID string) error {
 	storage := c.getHostOrDefaultStorage(c.hosts[hostID])
+	// Report changes made by other processes before the storage files are overwritten.
+	c.watcher.poll()
+	// Changes made by the app itself should not trigger host list reload.
+	c.watcher.pause()
+	defer func() { c.watcher.resume(c.watchedFiles()) }()
+	err := storage.Delete(hostID)
+	if err != nil {
+		return err
+	}
+
 	delete(c.hosts, hostID)
-	return storage.Delete(c.hostStorageMap[hostID].innerStorageID)
+	delete(c.hostStorageMap, hostID)
+	return nil
 }
 
 // Get implements HostStorage.
-func (c *combinedStorage) Get(hostID int) (model.Host, error) {
-	storage := c.getHostOrDefaultStorage(c.hosts[hostID])
-	storageID := c.hostStorageMap[hostID].innerStorageID
-	host, err := storage.Get(storageID)
+func (c *combinedStorage) Get(hostID string) (model.Host, error) {
+	storage, ok := c.hostStorageMap[hostID]
+	if !ok {
+		return model.Host{}, constant.ErrNotFound
+	}
+
+	host, err := storage.Get(hostID)
 	if err != nil {
 		return model.Host{}, err
 	}
 
 	host.StorageType = storage.Type()
-	// Re-assign host ID to the external value. Can use fromInnerStorageID(...),
-	// but that's not necessary because we already have the value.
-	host.ID = hostID
 	return host, nil
 }
 
-// GetAll implements HostStorage. Warning: this method rebuilds the IDs.
+// GetAll implements HostStorage.
 func (c *combinedStorage) GetAll() ([]model.Host, error) {
-	c.nextID = 0
-	storageTypes := lo.Keys(c.storages)
-	slices.Sort(storageTypes)
-	c.hosts = make(map[int]model.Host, 0)
-	for _, storageType := range storageTypes {
-		storageHosts, err := c.storages[storageType].GetAll()
+	// When the same host ID is used in several storages, the host from the storage which comes
+	// first is displayed. Storages are sorted by type, storages of the same type keep their order.
+	storages := slices.Clone(c.storages)
+	slices.SortStableFunc(storages, func(a, b HostStorage) int {
+		return cmp.Compare(a.Type(), b.Type())
+	})
+
+	c.hosts = make(map[string]model.Host, 0)
+	c.hostStorageMap = make(map[string]HostStorage, 0)
+	c.warnings = nil
+	reload := c.watcher.consumeChanges() || c.forceReload
+	c.forceReload = false
+	for _, storage := range storages {
+		if r, ok := storage.(reloadable); ok && reload {
+			c.logger.Info("[STORAGE] Reload %s storage", storage.Type())
+			r.Reload()
+		}
+
+		storageHosts, err := storage.GetAll()
 		if err != nil {
 			return nil, err
 		}
 
-		/*
-		 * This sorting is required to preserve ID order between application restarts.
-		 * If omit this, then almost every app restart loaded hosts will receive random IDs,
-		 * thus it won't be possible to focus on previously selected host in the UI.
-		 * In other words appState.Selected will point to different host with almost every restart.
-		 *
-		 * The reason why hosts come in different order is that the underlying storage contains
-		 * all hosts in a map, because ... storage there is a hack which indicates that
-		 * all new hosts IDs are equal to 0. Once this hack is removed, the sorting
-		 * will be removed as well.
-		 */
-		slices.SortFunc(storageHosts, func(a, b model.Host) int {
-			return lo.Ternary(a.Title < b.Title, -1, 1)
-		})
-
 		for i := range storageHosts {
-			storageHosts[i].StorageType = storageType
-			c.addHost(storageHosts[i], storageType)
+			storageHosts[i].StorageType = storage.Type()
+			c.addHost(storageHosts[i], storage)
 		}
 	}
 
+	c.watcher.watch(c.watchedFiles())
 	return lo.Values(c.hosts), nil
 }
 
-// Save implements HostStorage.
+// Save implements HostStorage. If the host source path points to another file than the one
+// where the host is stored, the host is moved to that file.
 func (c *combinedStorage) Save(host model.Host) (model.Host, error) {
 	storage := c.getHostOrDefaultStorage(host)
-	if isNewHost(host) {
-		var err error
-		host, err = storage.Save(host)
-		combinedStorageID := c.addHost(host, storage.Type())
-		host.ID = combinedStorageID
+	previousStorage, isMoved := c.hostStorageMap[host.ID]
+	isMoved = isMoved && previousStorage != storage
+	// Report changes made by other processes before the storage files are overwritten.
+	c.watcher.poll()
+	// Changes made by the app itself should not trigger host list reload.
+	c.watcher.pause()
+	defer func() { c.watcher.resume(c.watchedFiles()) }()
+	host, err := storage.Save(host)
+	if err != nil {
 		return host, err
 	}
 
-	mapping := c.hostStorageMap[host.ID]
-	host.ID = mapping.innerStorageID
-	host, err := storage.Save(host)
-	host.ID = mapping.combinedStorageID
-	return host, err
+	if isMoved {
+		c.logger.Info("[STORAGE] Move host %q to %q", host.Title, host.SourcePath)
+		if err = previousStorage.Delete(host.ID); err != nil {
+			// Do not leave two copies of the same host.
+			if rollbackErr := storage.Delete(host.ID); rollbackErr != nil {
+				c.logger.Error("[STORAGE] Cannot remove host %q from %q. %v", host.Title, host.SourcePath, rollbackErr)
+			}
+
+			return host, fmt.Errorf("cannot move host %q: %w", host.Title, err)
+		}
+
+		delete(c.hostStorageMap, host.ID)
+	}
+
+	host.StorageType = storage.Type()
+	c.addHost(host, storage)
+	return host, nil
 }
 
 // Type implements HostStorage.
@@ -163,35 +286,139 @@ func (c *combinedStorage) Type() constant.HostStorageEnum {
 	return constant.HostStorageType.Combined
 }
 
+// Inventories implements InventoryLister.
+func (c *combinedStorage) Inventories() []string {
+	files := []string{}
+	if f, ok := c.defaultStorage.(fileStorage); ok {
+		files = append(files, f.FilePath())
+	}
+
+	for _, storage := range c.storages {
+		if f, ok := storage.(fileStorage); ok && storage != c.defaultStorage {
+			files = append(files, f.FilePath())
+		}
+	}
+
+	return files
+}
+
+// Patterns implements PatternLister.
+func (c *combinedStorage) Patterns() []model.PatternBlock {
+	patterns := []model.PatternBlock{}
+	for _, storage := range c.storages {
+		if lister, ok := storage.(PatternLister); ok {
+			patterns = append(patterns, lister.Patterns()...)
+		}
+	}
+
+	return patterns
+}
+
 func (c *combinedStorage) getHostOrDefaultStorage(host model.Host) HostStorage {
-	storageType := c.hostStorageMap[host.ID].storageType
-	if storageType != "" {
-		return c.storages[storageType]
+	// The host is stored in the file which is selected by user.
+	if storage, ok := c.storageByFilePath(host.SourcePath); ok {
+		return storage
+	}
+
+	if storage, ok := c.hostStorageMap[host.ID]; ok {
+		return storage
+	}
+
+	// This is a new host. If it was cloned from an existing one, it should be stored
+	// in the same storage as the original host. For instance, cloned ssh_config host
+	// should be appended to the same ssh_config file.
+	for _, storage := range c.storages {
+		if storage.Type() == host.StorageType && sshConfigSource(storage) == host.SSHConfigSource {
+			return storage
+		}
+	}
+
+	return c.defaultStorage
+}
+
+// sshConfigSource - returns additional ssh_config source, which the storage is loaded from.
+func sshConfigSource(storage HostStorage) string {
+	if s, ok := storage.(*SSHConfigFile); ok {
+		return s.source
 	}
 
-	// Falling back to yaml file if storage is not set. This is a new host.
-	return c.storages[defaultHostStorageType]
+	return ""
 }
 
-func isNewHost(host model.Host) bool {
-	return host.ID == 0
+func (c *combinedStorage) storageByFilePath(filePath string) (HostStorage, bool) {
+	if utils.StringEmpty(&filePath) {
+		return nil, false
+	}
+
+	return lo.Find(c.storages, func(storage HostStorage) bool {
+		f, ok := storage.(fileStorage)
+		return ok && f.FilePath() == filePath
+	})
+}
+
+func (c *combinedStorage) addHost(host model.Host, storage HostStorage) {
+	if existing, ok := c.hostStorageMap[host.ID]; ok && existing != storage {
+		c.logger.Error("[STORAGE] Host id: %s is used in both %s and %s storages, host %q is ignored",
+			host.ID, existing.Type(), storage.Type(), host.Title)
+		// The host cannot be given another ID, as it would not be found in its own storage when it's saved.
+		c.warnings = append(c.warnings, fmt.Sprintf("host %q from %s is ignored, its id is used in %s",
+			host.Title, storageName(storage), storageName(existing)))
+		return
+	}
+
+	c.hostStorageMap[host.ID] = storage
+	c.hosts[host.ID] = host
 }
 
-func (c *combinedStorage) addHost(host model.Host, storageType constant.HostStorageEnum) int {
-	c.nextID++
-	c.hostStorageMap[c.nextID] = hostStorageMapping{
-		storageType:       storageType,
-		combinedStorageID: c.nextID,
-		innerStorageID:    host.ID,
+// storageName - returns the file path of the storage, or its type, if the storage is not a single file.
+func storageName(storage HostStorage) string {
+	if f, ok := storage.(fileStorage); ok {
+		return f.FilePath()
 	}
 
-	host.ID = c.nextID
-	c.hosts[c.nextID] = host
+	return string(storage.Type())
+}
+
+// Warnings implements WarningReporter.
+func (c *combinedStorage) Warnings() []string {
+	return c.warnings
+}
 
-	return c.nextID
+// Refresh implements Refreshable.
+func (c *combinedStorage) Refresh() {
+	c.forceReload = true
+}
+
+// OfflineSince implements Refreshable.
+func (c *combinedStorage) OfflineSince() (time.Time, bool) {
+	return utils.URLCacheOfflineSince()
+}
+
+// Changes implements Watchable.
+func (c *combinedStorage) Changes() <-chan struct{} {
+	if c.watcher == nil {
+		return nil
+	}
+
+	return c.watcher.changes
+}
+
+func (c *combinedStorage) watchedFiles() []string {
+	files := []string{}
+	for _, storage := range c.storages {
+		if r, ok := storage.(reloadable); ok {
+			files = append(files, r.WatchedFiles()...)
+		}
+	}
+
+	return files
 }
 
 func (c *combinedStorage) Close() {
+	if c.stopWatcher != nil {
+		c.stopWatcher()
+	}
+
 	for _, storage := range c.storages {
 		storage.Close()
 	}
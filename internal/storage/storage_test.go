@@ -3,8 +3,12 @@ package storage
 import (
 	"context"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafviktor/goto/internal/config"
@@ -45,9 +49,32 @@ func TestStorage_Initialize_SSHConfigDisabled(t *testing.T) {
 	storage.Close()
 }
 
+func TestStorage_Initialize_SSHConfigSources(t *testing.T) {
+	st := &state.State{
+		AppHome:          t.TempDir(),
+		SSHConfigEnabled: true,
+		SSHConfigPath:    "/home/user/.ssh/config",
+		// Main ssh_config file and duplicates are not loaded twice.
+		SSHConfigSources: []string{"https://example.com/ssh_config", "/home/user/.ssh/config", "https://example.com/ssh_config"},
+	}
+
+	storages, _, err := getStorages(context.TODO(), st, &mocklogger.Logger{})
+	require.NoError(t, err)
+	require.Len(t, storages, 3)
+	require.Empty(t, sshConfigSource(storages[1]))
+	require.Equal(t, "https://example.com/ssh_config", sshConfigSource(storages[2]))
+
+	cs := combinedStorage{storages: storages, hostStorageMap: make(map[string]HostStorage)}
+	// Cloned host is stored in the same source as the original one.
+	cloned := model.Host{StorageType: constant.HostStorageType.SSHConfig, SSHConfigSource: "https://example.com/ssh_config"}
+	require.Equal(t, storages[2], cs.getHostOrDefaultStorage(cloned))
+	cloned.SSHConfigSource = ""
+	require.Equal(t, storages[1], cs.getHostOrDefaultStorage(cloned))
+}
+
 type fakeHostStorage struct {
 	hosts   []model.Host
-	hostMap map[int]model.Host
+	hostMap map[string]model.Host
 	saveErr error
 	getErr  error
 	delErr  error
@@ -58,11 +85,14 @@ func (f *fakeHostStorage) GetAll() ([]model.Host, error) {
 	return f.hosts, nil
 }
 
-func (f *fakeHostStorage) Get(id int) (model.Host, error) {
+func (f *fakeHostStorage) Get(id string) (model.Host, error) {
 	if f.getErr != nil {
 		return model.Host{}, f.getErr
 	}
 	h, ok := f.hostMap[id]
+	if !ok {
+		h, ok = lo.Find(f.hosts, func(h model.Host) bool { return h.ID == id })
+	}
 	if !ok {
 		return model.Host{}, errors.New("not found")
 	}
@@ -73,9 +103,12 @@ func (f *fakeHostStorage) Save(h model.Host) (model.Host, error) {
 	if f.saveErr != nil {
 		return model.Host{}, f.saveErr
 	}
+	if h.ID == "" {
+		h.ID = model.NewID()
+	}
 	f.hosts = append(f.hosts, h)
 	if f.hostMap == nil {
-		f.hostMap = make(map[int]model.Host)
+		f.hostMap = make(map[string]model.Host)
 	}
 	f.hostMap[h.ID] = h
 	return h, nil
@@ -85,7 +118,7 @@ func (f *fakeHostStorage) Type() constant.HostStorageEnum {
 	return f.typ
 }
 
-func (f *fakeHostStorage) Delete(id int) error {
+func (f *fakeHostStorage) Delete(id string) error {
 	if f.delErr != nil {
 		return f.delErr
 	}
@@ -102,9 +135,8 @@ func TestCombinedStorage_GetAll(t *testing.T) {
 
 	cs := combinedStorage{
 		storages:       getMockStorages(context.TODO(), config.Configuration{}, logger),
-		hostStorageMap: make(map[int]hostStorageMapping),
-		hosts:          make(map[int]model.Host),
-		nextID:         0,
+		hostStorageMap: make(map[string]HostStorage),
+		hosts:          make(map[string]model.Host),
 		logger:         logger,
 	}
 
@@ -113,60 +145,74 @@ func TestCombinedStorage_GetAll(t *testing.T) {
 	require.Len(t, hosts, 3)
 }
 
+func TestCombinedStorage_Patterns(t *testing.T) {
+	yamlStorage := &fakeHostStorage{typ: constant.HostStorageType.YAMLFile}
+	sshStorage := &SSHConfigFile{
+		fileParser: &mockSSHParser{patterns: []model.PatternBlock{
+			{Patterns: "*", SourcePath: "config", Line: 1},
+			{Patterns: "*.prod !db.prod", SourcePath: "config", Line: 5},
+		}},
+	}
+	cs := combinedStorage{
+		storages: []HostStorage{yamlStorage, sshStorage},
+		logger:   &mocklogger.Logger{},
+	}
+
+	// YAML storage does not have patterns.
+	patterns := cs.Patterns()
+	require.Len(t, patterns, 2)
+	require.Equal(t, "*.prod !db.prod", patterns[1].Patterns)
+}
+
 func getMockStorages(
 	_ context.Context,
 	_ config.Configuration,
 	_ iLogger,
-) map[constant.HostStorageEnum]HostStorage {
+) []HostStorage {
 	// Setup fake storages
 	yamlStorage := &fakeHostStorage{
 		hosts: []model.Host{
-			{ID: 1, Title: "host1"},
-			{ID: 2, Title: "host2"},
+			{ID: "1", Title: "host1"},
+			{ID: "2", Title: "host2"},
 		},
 		typ: constant.HostStorageType.YAMLFile,
 	}
 	sshStorage := &fakeHostStorage{
 		hosts: []model.Host{
-			{ID: 1, Title: "sshhost"},
+			{ID: "3", Title: "sshhost"},
 		},
 		typ: constant.HostStorageType.SSHConfig,
 	}
 
-	return map[constant.HostStorageEnum]HostStorage{
-		constant.HostStorageType.YAMLFile:  yamlStorage,
-		constant.HostStorageType.SSHConfig: sshStorage,
-	}
+	return []HostStorage{yamlStorage, sshStorage}
 }
 
 func TestCombinedStorage_SaveAndGet(t *testing.T) {
+	yamlStorage := &fakeHostStorage{typ: constant.HostStorageType.YAMLFile, hostMap: make(map[string]model.Host)}
 	cs := &combinedStorage{
-		storages:       make(map[constant.HostStorageEnum]HostStorage),
-		hostStorageMap: make(map[int]hostStorageMapping),
-		hosts:          make(map[int]model.Host),
-		nextID:         0,
+		storages:       []HostStorage{yamlStorage},
+		defaultStorage: yamlStorage,
+		hostStorageMap: make(map[string]HostStorage),
+		hosts:          make(map[string]model.Host),
 	}
-	yamlStorage := &fakeHostStorage{typ: constant.HostStorageType.YAMLFile, hostMap: make(map[int]model.Host)}
-	cs.storages[constant.HostStorageType.YAMLFile] = yamlStorage
 
 	host := model.Host{Title: "test"}
 	saved, err := cs.Save(host)
 	require.NoError(t, err, "expected no error on save")
-	require.NotEqual(t, 0, saved.ID, "expected ID to be non-zero after save")
+	require.NotEmpty(t, saved.ID, "expected ID to be set after save")
 	got, err := cs.Get(saved.ID)
 	require.NoError(t, err)
 	require.Equal(t, "test", got.Title, "expected title 'test'")
 }
 
 func TestCombinedStorage_Delete(t *testing.T) {
+	yamlStorage := &fakeHostStorage{typ: constant.HostStorageType.YAMLFile, hostMap: make(map[string]model.Host)}
 	cs := &combinedStorage{
-		storages:       make(map[constant.HostStorageEnum]HostStorage),
-		hostStorageMap: make(map[int]hostStorageMapping),
-		hosts:          make(map[int]model.Host),
-		nextID:         0,
+		storages:       []HostStorage{yamlStorage},
+		defaultStorage: yamlStorage,
+		hostStorageMap: make(map[string]HostStorage),
+		hosts:          make(map[string]model.Host),
 	}
-	yamlStorage := &fakeHostStorage{typ: constant.HostStorageType.YAMLFile, hostMap: make(map[int]model.Host)}
-	cs.storages[constant.HostStorageType.YAMLFile] = yamlStorage
 
 	host := model.Host{Title: "test"}
 	saved, _ := cs.Save(host)
@@ -174,3 +220,103 @@ func TestCombinedStorage_Delete(t *testing.T) {
 	require.NoError(t, err, "expected no error on delete")
 	require.NotContains(t, cs.hosts, saved.ID, "host not deleted from combined storage")
 }
+
+func TestCombinedStorage_GetAll_KeepsIDs(t *testing.T) {
+	logger := &mocklogger.Logger{}
+	storages := getMockStorages(context.TODO(), config.Configuration{}, logger)
+	cs := combinedStorage{
+		storages:       storages,
+		hostStorageMap: make(map[string]HostStorage),
+		hosts:          make(map[string]model.Host),
+		logger:         logger,
+	}
+
+	// IDs are not rebuilt by the combined storage and do not depend on the order of the hosts.
+	hosts, err := cs.GetAll()
+	require.NoError(t, err)
+	ids := lo.Map(hosts, func(h model.Host, _ int) string { return h.ID })
+	require.ElementsMatch(t, []string{"1", "2", "3"}, ids)
+	require.Empty(t, cs.Warnings())
+
+	got, err := cs.Get("3")
+	require.NoError(t, err)
+	require.Equal(t, "sshhost", got.Title)
+	require.Equal(t, constant.HostStorageType.SSHConfig, got.StorageType)
+
+	// Host with the same ID in another storage is ignored. Storages are loaded in alphabetical order.
+	yamlStorage := storages[0].(*fakeHostStorage)
+	yamlStorage.hosts = append(yamlStorage.hosts, model.Host{ID: "3", Title: "duplicate"})
+	hosts, err = cs.GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 3)
+	require.Equal(t, "sshhost", cs.hosts["3"].Title)
+	// User is warned that the host is not displayed.
+	require.Len(t, cs.Warnings(), 1)
+	require.Contains(t, cs.Warnings()[0], `host "duplicate"`)
+
+	_, err = cs.Get("unknown")
+	require.ErrorIs(t, err, constant.ErrNotFound)
+}
+
+type reloadableHostStorage struct {
+	fakeHostStorage
+	files    []string
+	reloaded int
+}
+
+func (r *reloadableHostStorage) WatchedFiles() []string {
+	return r.files
+}
+
+func (r *reloadableHostStorage) Reload() {
+	r.reloaded++
+}
+
+func TestCombinedStorage_ReloadWhenFilesChange(t *testing.T) {
+	logger := &mocklogger.Logger{}
+	filePath := filepath.Join(t.TempDir(), "hosts.yaml")
+	require.NoError(t, os.WriteFile(filePath, []byte("- host:\n"), 0o600))
+
+	inner := &reloadableHostStorage{
+		fakeHostStorage: fakeHostStorage{typ: constant.HostStorageType.YAMLFile},
+		files:           []string{filePath},
+	}
+	cs := combinedStorage{
+		storages:       []HostStorage{inner},
+		defaultStorage: inner,
+		hosts:          make(map[string]model.Host),
+		hostStorageMap: make(map[string]HostStorage),
+		logger:         logger,
+		watcher:        newFileWatcher(time.Hour, logger),
+	}
+
+	_, err := cs.GetAll()
+	require.NoError(t, err)
+	require.Equal(t, 0, inner.reloaded)
+
+	// Changes made by the app should not trigger reload.
+	_, err = cs.Save(model.Host{Title: "new host"})
+	require.NoError(t, err)
+	cs.watcher.poll()
+	require.Empty(t, cs.Changes())
+
+	// The file is modified outside of the app.
+	require.NoError(t, os.WriteFile(filePath, []byte("- host:\n    title: test\n"), 0o600))
+	cs.watcher.poll()
+	require.Len(t, cs.Changes(), 1)
+
+	_, err = cs.GetAll()
+	require.NoError(t, err)
+	require.Equal(t, 1, inner.reloaded)
+
+	// Storage is not reloaded, if there were no changes since the last call.
+	_, err = cs.GetAll()
+	require.NoError(t, err)
+	require.Equal(t, 1, inner.reloaded)
+
+	// Unless the user asks to refresh the host list.
+	cs.Refresh()
+	_, err = cs.GetAll()
+	require.NoError(t, err)
+	require.Equal(t, 2, inner.reloaded)
+}
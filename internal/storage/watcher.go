@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"maps"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// watchInterval - how often the watched files are checked for changes.
+const watchInterval = time.Second
+
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+func readFileState(filePath string) fileState {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return fileState{}
+	}
+
+	return fileState{exists: true, modTime: info.ModTime(), size: info.Size()}
+}
+
+// fileWatcher - polls the files, and notifies when any of them is created, modified or removed.
+// Polling is used instead of OS notifications, because it works the same way on all platforms and
+// does not require extra dependencies. Watched folders are reported as changed when files are
+// added to or removed from them, because that changes folder modification time.
+type fileWatcher struct {
+	mu    sync.Mutex
+	files map[string]fileState
+	// paused is greater than zero while the app writes the files, see pause. generation changes every time
+	// the files are paused or re-armed, so that a poll, which started before, does not report the changes.
+	paused     int
+	generation int
+	changed    atomic.Bool
+	changes    chan struct{}
+	interval   time.Duration
+	logger     iLogger
+}
+
+func newFileWatcher(interval time.Duration, logger iLogger) *fileWatcher {
+	return &fileWatcher{
+		files:    make(map[string]fileState),
+		changes:  make(chan struct{}, 1),
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// watch - replaces the list of watched files and remembers their current state. Changes which
+// were made before this call are not reported.
+func (w *fileWatcher) watch(filePaths []string) {
+	if w == nil {
+		return
+	}
+
+	files := make(map[string]fileState, len(filePaths))
+	for _, filePath := range filePaths {
+		files[filePath] = readFileState(filePath)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.files = files
+	w.generation++
+}
+
+// pause - stops reporting changes, while the app writes the files. Otherwise, a poll, which runs in
+// background, reports the app's own write as an external change. Every pause call must be followed
+// by resume.
+func (w *fileWatcher) pause() {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused++
+	w.generation++
+}
+
+// resume - re-arms the watcher with the files, which are written by the app, and reports changes again.
+func (w *fileWatcher) resume(filePaths []string) {
+	if w == nil {
+		return
+	}
+
+	w.watch(filePaths)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.paused--
+}
+
+// consumeChanges - returns true if any of the files were modified since the last call.
+func (w *fileWatcher) consumeChanges() bool {
+	if w == nil {
+		return false
+	}
+
+	return w.changed.Swap(false)
+}
+
+func (w *fileWatcher) run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.poll()
+		}
+	}
+}
+
+func (w *fileWatcher) poll() {
+	if w == nil {
+		return
+	}
+
+	w.mu.Lock()
+	if w.paused > 0 {
+		w.mu.Unlock()
+		return
+	}
+
+	files := maps.Clone(w.files)
+	generation := w.generation
+	w.mu.Unlock()
+
+	changed := false
+	for filePath, previous := range files {
+		current := readFileState(filePath)
+		if current != previous {
+			w.logger.Info("[STORAGE] File changed: %q", filePath)
+			files[filePath] = current
+			changed = true
+		}
+	}
+
+	if !changed {
+		return
+	}
+
+	w.mu.Lock()
+	// The changes are not reported if the files were written by the app or the watcher was re-armed
+	// while they were checked.
+	if w.paused > 0 || w.generation != generation {
+		w.mu.Unlock()
+		return
+	}
+
+	w.files = files
+	w.mu.Unlock()
+
+	w.changed.Store(true)
+	select {
+	case w.changes <- struct{}{}:
+	default:
+		// Notification is already pending.
+	}
+}
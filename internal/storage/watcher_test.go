@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+func Test_fileWatcher_poll(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "hosts.yaml")
+	watcher := newFileWatcher(time.Hour, &mocklogger.Logger{})
+
+	// File does not exist yet.
+	watcher.watch([]string{filePath})
+	watcher.poll()
+	require.False(t, watcher.consumeChanges())
+
+	// File is created.
+	require.NoError(t, os.WriteFile(filePath, []byte("- host:\n"), 0o600))
+	watcher.poll()
+	require.True(t, watcher.consumeChanges())
+	require.False(t, watcher.consumeChanges(), "changes should be reported once")
+	require.Len(t, watcher.changes, 1)
+
+	// Nothing changed since the last poll.
+	watcher.poll()
+	require.False(t, watcher.consumeChanges())
+
+	// File is modified, but the change is made by the app and the watcher is re-armed.
+	require.NoError(t, os.WriteFile(filePath, []byte("- host:\n    title: test\n"), 0o600))
+	watcher.watch([]string{filePath})
+	watcher.poll()
+	require.False(t, watcher.consumeChanges())
+
+	// File is removed.
+	require.NoError(t, os.Remove(filePath))
+	watcher.poll()
+	require.True(t, watcher.consumeChanges())
+	// Notification channel never blocks, even if nobody reads it.
+	require.Len(t, watcher.changes, 1)
+}
+
+func Test_fileWatcher_pause(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "hosts.yaml")
+	watcher := newFileWatcher(time.Hour, &mocklogger.Logger{})
+	watcher.watch([]string{filePath})
+
+	// The app writes the file, polls which run at the same time do not report it.
+	watcher.pause()
+	require.NoError(t, os.WriteFile(filePath, []byte("- host:\n"), 0o600))
+	watcher.poll()
+	watcher.resume([]string{filePath})
+	watcher.poll()
+	require.False(t, watcher.consumeChanges())
+	require.Empty(t, watcher.changes)
+
+	// Changes made by other processes are reported again.
+	require.NoError(t, os.Remove(filePath))
+	watcher.poll()
+	require.True(t, watcher.consumeChanges())
+}
+
+func Test_fileWatcher_nil(t *testing.T) {
+	var watcher *fileWatcher
+	watcher.watch([]string{"hosts.yaml"})
+	watcher.pause()
+	watcher.resume([]string{"hosts.yaml"})
+	require.False(t, watcher.consumeChanges())
+}
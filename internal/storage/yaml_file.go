@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
 	"errors"
+	"fmt"
 	"os"
 	"path"
 	"slices"
@@ -12,103 +14,241 @@ import (
 
 	"github.com/grafviktor/goto/internal/constant"
 	model "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/utils"
 )
 
 var _ HostStorage = &yamlFile{}
 
-const (
-	hostsFile = "hosts.yaml"
-	// Yaml storage specific: if host has id which is equal to "0"
-	// that means that this host doesn't yet exist. It's a hack,
-	// but simplifies the application. That's why idEmpty = "0".
-	idEmpty = 0
-)
+// ErrConflict - is returned when a host was modified both by the app and by another process,
+// for instance, by another instance of the app which runs in a different terminal.
+var ErrConflict = errors.New("hosts file was modified by another process")
+
+const hostsFile = "hosts.yaml"
 
 // newYAMLStorage creates new YAML storage.
-func newYAMLStorage(_ context.Context, appFolder string, logger iLogger) *yamlFile {
+func newYAMLStorage(_ context.Context, appFolder string, backupCount int, logger iLogger) *yamlFile {
 	logger.Debug("[STORAGE] Init YAML storage. Config folder %q", appFolder)
 	fsDataPath := path.Join(appFolder, hostsFile)
 
 	return &yamlFile{
-		innerStorage: make(map[int]yamlHostWrapper),
+		innerStorage: make([]yamlHostWrapper, 0),
 		fsDataPath:   fsDataPath,
+		backupFolder: path.Join(appFolder, backupFolder),
+		backupCount:  backupCount,
 		logger:       logger,
 	}
 }
 
+// newInventoryStorage creates YAML storage which keeps hosts in an additional host file, for instance,
+// in a team inventory. Such files are often kept under version control, therefore they're not backed up.
+func newInventoryStorage(_ context.Context, filePath string, logger iLogger) *yamlFile {
+	logger.Debug("[STORAGE] Init YAML storage. Host file %q", filePath)
+
+	return &yamlFile{
+		innerStorage: make([]yamlHostWrapper, 0),
+		fsDataPath:   filePath,
+		logger:       logger,
+	}
+}
+
+// newEncryptedYAMLStorage creates YAML storage which keeps hosts in the encrypted file.
+func newEncryptedYAMLStorage(
+	ctx context.Context,
+	appFolder string,
+	backupCount int,
+	key EncryptionKey,
+	logger iLogger,
+) *yamlFile {
+	storage := newYAMLStorage(ctx, appFolder, backupCount, logger)
+	storage.fsDataPath = path.Join(appFolder, encryptedHostsFile)
+	storage.backupFolder = path.Join(appFolder, backupFolder, encryptedBackupFolder)
+	storage.cipher = newFileCipher(key)
+
+	return storage
+}
+
 type yamlFile struct {
-	innerStorage map[int]yamlHostWrapper
-	nextID       int
+	// innerStorage is a slice and not a map to keep hosts in the same order as they're stored in the file.
+	innerStorage []yamlHostWrapper
+	// checksum of the file when it was read or written by the app. If the file on disk has a
+	// different checksum, then it was modified by another process.
+	checksum     [sha256.Size]byte
 	fsDataPath   string
-	logger       iLogger
+	backupFolder string
+	backupCount  int
+	// cipher is only set when the file is encrypted.
+	cipher *fileCipher
+	logger iLogger
 }
 
 type yamlHostWrapper struct {
 	Host model.Host `yaml:"host"`
 }
 
-func (s *yamlFile) flushToDisk() error {
-	// map contains values in shuffled order
-	mapValues := lo.Values(s.innerStorage)
-	// sorting slice by index
-	slices.SortFunc(mapValues, func(a, b yamlHostWrapper) int {
-		if a.Host.ID < b.Host.ID {
-			return -1
+// update applies change to the hosts and writes them to disk. The file is locked while it's updated,
+// so that other instances of the app cannot modify it at the same time. If the file was modified by
+// another process after it had been read, the change is applied to the file contents instead of
+// innerStorage. That only fails if the same host was also modified by the other process.
+func (s *yamlFile) update(hostID string, isDelete bool, change func([]yamlHostWrapper) []yamlHostWrapper) error {
+	unlock, err := utils.LockFile(s.fsDataPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	hosts, err := s.mergeExternalChanges(hostID, isDelete)
+	if err != nil {
+		return err
+	}
+
+	hosts = change(hosts)
+	if err = s.flushToDisk(hosts); err != nil {
+		return err
+	}
+
+	s.innerStorage = hosts
+	return nil
+}
+
+// mergeExternalChanges returns hosts which should be modified. These are either hosts from innerStorage,
+// or, if the file was modified by another process, hosts from the file.
+func (s *yamlFile) mergeExternalChanges(hostID string, isDelete bool) ([]yamlHostWrapper, error) {
+	fileData, err := os.ReadFile(s.fsDataPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if sha256.Sum256(fileData) == s.checksum {
+		return slices.Clone(s.innerStorage), nil
+	}
+
+	s.logger.Info("[STORAGE] File %q was modified by another process, merge changes", s.fsDataPath)
+	fileHosts, err := s.unmarshalHosts(fileData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot merge changes made by another process: %w", err)
+	}
+
+	ourIndex := indexOfHost(s.innerStorage, hostID)
+	theirIndex := indexOfHost(fileHosts, hostID)
+	switch {
+	case ourIndex < 0 && theirIndex < 0:
+		// A new host.
+	case ourIndex >= 0 && theirIndex < 0 && isDelete:
+		// The host has already been deleted by another process.
+	case ourIndex >= 0 && theirIndex >= 0 && sameHost(s.innerStorage[ourIndex].Host, fileHosts[theirIndex].Host):
+		// The host was not modified by another process.
+	default:
+		title := hostID
+		if ourIndex >= 0 {
+			title = s.innerStorage[ourIndex].Host.Title
 		}
-		return 1
-	})
 
-	result, err := yaml.Marshal(mapValues)
+		s.logger.Error("[STORAGE] Host %q was modified by another process", title)
+		return nil, fmt.Errorf("%w, cannot save host %q", ErrConflict, title)
+	}
+
+	return fileHosts, nil
+}
+
+// sameHost compares host fields which are stored in the file.
+func sameHost(a, b model.Host) bool {
+	aData, aErr := yaml.Marshal(a)
+	bData, bErr := yaml.Marshal(b)
+	return aErr == nil && bErr == nil && slices.Equal(aData, bData)
+}
+
+// flushToDisk writes hosts to the file. The previous version of the file is copied to backup folder.
+// innerStorage must only be updated if this function succeeds, otherwise the app will display hosts
+// which do not exist in the file.
+func (s *yamlFile) flushToDisk(hosts []yamlHostWrapper) error {
+	result, err := yaml.Marshal(hosts)
 	if err != nil {
 		return err
 	}
 
-	err = os.WriteFile(s.fsDataPath, result, 0o600)
+	if s.cipher != nil {
+		if result, err = s.cipher.encrypt(result); err != nil {
+			return err
+		}
+	}
+
+	err = createBackup(s.fsDataPath, s.backupFolder, s.backupCount)
+	if err != nil {
+		// Do not prevent user from saving changes, if backup cannot be created.
+		s.logger.Error("[STORAGE] Cannot create backup of %q. %v", s.fsDataPath, err)
+	}
+
+	err = utils.WriteFileAtomic(s.fsDataPath, result, 0o600)
 	if err != nil {
-		panic(err)
+		return err
 	}
 
+	s.checksum = sha256.Sum256(result)
 	return nil
 }
 
+func indexOfHost(hosts []yamlHostWrapper, hostID string) int {
+	return slices.IndexFunc(hosts, func(wrapped yamlHostWrapper) bool {
+		return wrapped.Host.ID == hostID
+	})
+}
+
 func (s *yamlFile) Save(host model.Host) (model.Host, error) {
-	if host.ID == idEmpty {
-		s.logger.Debug("[STORAGE] Generate new id for new host with title: %s", host.Title)
-		s.nextID++
-		host.ID = s.nextID
+	host.SourcePath = s.fsDataPath
+	if utils.StringEmpty(&host.ID) {
+		host.ID = model.NewID()
+		s.logger.Debug("[STORAGE] Generate new id: %s for new host with title: %s", host.ID, host.Title)
 	}
 
-	s.logger.Info("[STORAGE] Save host with id: %d, title: %s", host.ID, host.Title)
-	s.innerStorage[host.ID] = yamlHostWrapper{host}
+	s.logger.Info("[STORAGE] Save host with id: %s, title: %s", host.ID, host.Title)
+	err := s.update(host.ID, false, func(hosts []yamlHostWrapper) []yamlHostWrapper {
+		if index := indexOfHost(hosts, host.ID); index >= 0 {
+			hosts[index] = yamlHostWrapper{host}
+			return hosts
+		}
 
-	err := s.flushToDisk()
+		return append(hosts, yamlHostWrapper{host})
+	})
 	if err != nil {
 		s.logger.Error("[STORAGE] Cannot flush database changes to disk. %v", err)
+		return host, fmt.Errorf("cannot save hosts file: %w", err)
 	}
 
-	return host, err
+	return host, nil
 }
 
-func (s *yamlFile) Delete(hostID int) error {
-	s.logger.Info("[STORAGE] Delete host with id: %d", hostID)
-	delete(s.innerStorage, hostID)
+func (s *yamlFile) Delete(hostID string) error {
+	s.logger.Info("[STORAGE] Delete host with id: %s", hostID)
+	if indexOfHost(s.innerStorage, hostID) < 0 {
+		s.logger.Error("[STORAGE] Host id: %s not found in the database", hostID)
+		return constant.ErrNotFound
+	}
 
-	err := s.flushToDisk()
+	err := s.update(hostID, true, func(hosts []yamlHostWrapper) []yamlHostWrapper {
+		if index := indexOfHost(hosts, hostID); index >= 0 {
+			return slices.Delete(hosts, index, index+1)
+		}
+
+		return hosts
+	})
 	if err != nil {
-		s.logger.Error("[STORAGE] Error deleting host id: %d from the database. %v", hostID, err)
+		s.logger.Error("[STORAGE] Error deleting host id: %s from the database. %v", hostID, err)
+		return fmt.Errorf("cannot save hosts file: %w", err)
 	}
-	return err
+
+	return nil
 }
 
 func (s *yamlFile) GetAll() ([]model.Host, error) {
 	// re-create innerStorage before reading file data
-	s.innerStorage = make(map[int]yamlHostWrapper)
+	s.innerStorage = make([]yamlHostWrapper, 0)
 	s.logger.Debug("[STORAGE] Read hosts from file: %q\n", s.fsDataPath)
 	fileData, err := os.ReadFile(s.fsDataPath)
 	if err != nil {
 		var pathErr *os.PathError
 		if errors.As(err, &pathErr) {
 			s.logger.Info("[STORAGE] Path not found: %s. Assuming it's not created yet", s.fsDataPath)
+			s.checksum = sha256.Sum256(nil)
 
 			return make([]model.Host, 0), nil
 		}
@@ -117,24 +257,26 @@ func (s *yamlFile) GetAll() ([]model.Host, error) {
 		return nil, err
 	}
 
-	var yamlHosts []yamlHostWrapper
 	s.logger.Debug("[STORAGE] Unmarshal hosts data from yaml storage")
-	err = yaml.Unmarshal(fileData, &yamlHosts)
+	yamlHosts, err := s.unmarshalHosts(fileData)
 	if err != nil {
 		s.logger.Error("[STORAGE] Could not unmarshal hosts data. %v", err)
 		return nil, err
 	}
 
-	s.nextID = idEmpty
-	for _, wrapped := range yamlHosts {
-		s.nextID++
-		wrapped.Host.ID = s.nextID
-
-		// Maintain an internal map which is keyed by int
-		s.innerStorage[s.nextID] = wrapped
+	s.innerStorage = yamlHosts
+	s.checksum = sha256.Sum256(fileData)
+	if s.assignMissingIDs() {
+		// Hosts created by older versions of the app do not have IDs. Persist generated
+		// values, so that the hosts keep the same IDs between application restarts.
+		s.logger.Info("[STORAGE] Write generated host ids to: %q", s.fsDataPath)
+		if err = s.flushGeneratedIDs(); err != nil {
+			s.logger.Error("[STORAGE] Cannot write generated host ids to disk. %v", err)
+		}
 	}
 
-	hosts := lo.MapToSlice(s.innerStorage, func(_ int, value yamlHostWrapper) model.Host {
+	hosts := lo.Map(s.innerStorage, func(value yamlHostWrapper, _ int) model.Host {
+		value.Host.SourcePath = s.fsDataPath
 		return value.Host
 	})
 
@@ -142,20 +284,107 @@ func (s *yamlFile) GetAll() ([]model.Host, error) {
 	return hosts, nil
 }
 
-func (s *yamlFile) Get(hostID int) (model.Host, error) {
-	s.logger.Debug("[STORAGE] Read host with id %d from the database", hostID)
-	found, ok := s.innerStorage[hostID]
+func (s *yamlFile) unmarshalHosts(fileData []byte) ([]yamlHostWrapper, error) {
+	var err error
+	if s.cipher != nil && len(fileData) > 0 {
+		if fileData, err = s.cipher.decrypt(fileData); err != nil {
+			return nil, err
+		}
+	}
+
+	var yamlHosts []yamlHostWrapper
+	err = yaml.Unmarshal(fileData, &yamlHosts)
+	return yamlHosts, err
+}
+
+// assignMissingIDs generates IDs for hosts which do not have them, or which have the same ID
+// as another host, which may happen when the file is edited by hand. Returns true if any of
+// the IDs were changed.
+func (s *yamlFile) assignMissingIDs() bool {
+	changed := false
+	knownIDs := make(map[string]struct{}, len(s.innerStorage))
+	for i := range s.innerStorage {
+		host := &s.innerStorage[i].Host
+		if _, duplicate := knownIDs[host.ID]; duplicate || utils.StringEmpty(&host.ID) {
+			host.ID = model.NewID()
+			s.logger.Debug("[STORAGE] Assign id: %s to host with title: %s", host.ID, host.Title)
+			changed = true
+		}
+
+		knownIDs[host.ID] = struct{}{}
+	}
 
-	if !ok {
-		s.logger.Debug("[STORAGE] Host id %d NOT found in the database", hostID)
+	return changed
+}
+
+// flushGeneratedIDs writes hosts with generated IDs to disk. If the file was modified by another
+// process after it had been read, IDs are generated for the file contents instead, so that the
+// changes made by the other process are not overwritten.
+func (s *yamlFile) flushGeneratedIDs() error {
+	unlock, err := utils.LockFile(s.fsDataPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	fileData, err := os.ReadFile(s.fsDataPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if sha256.Sum256(fileData) != s.checksum {
+		s.logger.Info("[STORAGE] File %q was modified by another process, generate host ids again", s.fsDataPath)
+		fileHosts, err := s.unmarshalHosts(fileData)
+		if err != nil {
+			return err
+		}
+
+		s.innerStorage = fileHosts
+		s.checksum = sha256.Sum256(fileData)
+		if !s.assignMissingIDs() {
+			// The other process has already written the IDs.
+			return nil
+		}
+	}
+
+	return s.flushToDisk(s.innerStorage)
+}
+
+func (s *yamlFile) Get(hostID string) (model.Host, error) {
+	s.logger.Debug("[STORAGE] Read host with id %s from the database", hostID)
+	index := indexOfHost(s.innerStorage, hostID)
+
+	if index < 0 {
+		s.logger.Debug("[STORAGE] Host id %s NOT found in the database", hostID)
 		return model.Host{}, constant.ErrNotFound
 	}
 
-	s.logger.Debug("[STORAGE] Host id %d found in the database", hostID)
-	return found.Host, nil
+	s.logger.Debug("[STORAGE] Host id %s found in the database", hostID)
+	host := s.innerStorage[index].Host
+	host.SourcePath = s.fsDataPath
+	return host, nil
+}
+
+// FilePath - returns path to the file where hosts are stored.
+func (s *yamlFile) FilePath() string {
+	return s.fsDataPath
+}
+
+// WatchedFiles - returns hosts file path, the file is watched for changes made outside of the app.
+func (s *yamlFile) WatchedFiles() []string {
+	return []string{s.fsDataPath}
+}
+
+// Reload - does nothing, because hosts file is read on every GetAll call.
+func (s *yamlFile) Reload() {
+	// nop
 }
 
 func (s *yamlFile) Type() constant.HostStorageEnum {
+	if s.cipher != nil {
+		return constant.HostStorageType.EncryptedYAMLFile
+	}
+
 	return constant.HostStorageType.YAMLFile
 }
 
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafviktor/goto/internal/constant"
@@ -20,7 +21,7 @@ func (l *testLogger) Error(_ string, _ ...any) {}
 
 func TestYAMLFile_SaveAndGetAll(t *testing.T) {
 	tmpDir := t.TempDir()
-	st := newYAMLStorage(context.TODO(), tmpDir, &testLogger{})
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
 
 	host1 := model.Host{Title: "host1", Address: "host1.com"}
 	host2 := model.Host{Title: "host2", Address: "host2.com"}
@@ -44,7 +45,7 @@ func TestYAMLFile_SaveAndGetAll(t *testing.T) {
 
 func TestYAMLFile_Get(t *testing.T) {
 	tmpDir := t.TempDir()
-	st := newYAMLStorage(context.TODO(), tmpDir, &testLogger{})
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
 
 	host := model.Host{Title: "host1", Address: "host1.com"}
 	saved, err := st.Save(host)
@@ -58,7 +59,7 @@ func TestYAMLFile_Get(t *testing.T) {
 
 func TestYAMLFile_Delete(t *testing.T) {
 	tmpDir := t.TempDir()
-	st := newYAMLStorage(context.TODO(), tmpDir, &testLogger{})
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
 
 	host := model.Host{Title: "host1", Address: "host1.com"}
 	saved, err := st.Save(host)
@@ -73,7 +74,7 @@ func TestYAMLFile_Delete(t *testing.T) {
 
 func TestYAMLFile_GetAll_EmptyFile(t *testing.T) {
 	tmpDir := t.TempDir()
-	st := newYAMLStorage(context.TODO(), tmpDir, &testLogger{})
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
 
 	hosts, err := st.GetAll()
 	require.NoError(t, err)
@@ -82,7 +83,7 @@ func TestYAMLFile_GetAll_EmptyFile(t *testing.T) {
 
 func TestYAMLFile_GetAll_InvalidYAML(t *testing.T) {
 	tmpDir := t.TempDir()
-	st := newYAMLStorage(context.TODO(), tmpDir, &testLogger{})
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
 
 	// Write invalid YAML to the file
 	err := os.WriteFile(filepath.Join(tmpDir, "hosts.yaml"), []byte("not: [valid"), 0o600)
@@ -93,7 +94,193 @@ func TestYAMLFile_GetAll_InvalidYAML(t *testing.T) {
 	require.Nil(t, hosts)
 }
 
+func TestYAMLFile_GetAll_AssignsMissingIDs(t *testing.T) {
+	tmpDir := t.TempDir()
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
+
+	// File created by an older version of the app, hosts do not have IDs. The last host
+	// has the same ID as the first one, which may happen when the file is edited by hand.
+	fileData := `- host:
+    title: host2
+    address: host2.com
+- host:
+    id: host1-id
+    title: host1
+    address: host1.com
+- host:
+    title: host3
+    address: host3.com
+- host:
+    id: host1-id
+    title: host4
+    address: host4.com
+`
+	err := os.WriteFile(filepath.Join(tmpDir, "hosts.yaml"), []byte(fileData), 0o600)
+	require.NoError(t, err)
+
+	hosts, err := st.GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 4)
+	// Order of the hosts is the same as in the file
+	require.Equal(t, []string{"host2", "host1", "host3", "host4"}, lo.Map(hosts, func(h model.Host, _ int) string {
+		return h.Title
+	}))
+	require.Equal(t, "host1-id", hosts[1].ID)
+	require.Len(t, lo.Uniq(lo.Map(hosts, func(h model.Host, _ int) string { return h.ID })), 4)
+	for _, host := range hosts {
+		require.NotEmpty(t, host.ID)
+	}
+
+	// Generated IDs are persisted and do not change when the file is read again
+	reloaded, err := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{}).GetAll()
+	require.NoError(t, err)
+	require.Equal(t, hosts, reloaded)
+}
+
+func TestYAMLFile_flushGeneratedIDs_MergesExternalChanges(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "hosts.yaml")
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
+	st.innerStorage = []yamlHostWrapper{{model.Host{Title: "host1", Address: "host1.com"}}}
+	require.True(t, st.assignMissingIDs())
+
+	// Another process adds a host after the file has been read, but before the IDs are written.
+	fileData := `- host:
+    title: host1
+    address: host1.com
+- host:
+    title: host2
+    address: host2.com
+`
+	require.NoError(t, os.WriteFile(filePath, []byte(fileData), 0o600))
+	require.NoError(t, st.flushGeneratedIDs())
+
+	hosts, err := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{}).GetAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"host1", "host2"}, lo.Map(hosts, func(h model.Host, _ int) string { return h.Title }))
+	require.Equal(t, lo.Map(st.innerStorage, func(h yamlHostWrapper, _ int) string { return h.Host.ID }),
+		lo.Map(hosts, func(h model.Host, _ int) string { return h.ID }))
+}
+
+func TestYAMLFile_Save_KeepsIDOnUpdate(t *testing.T) {
+	st := newYAMLStorage(context.TODO(), t.TempDir(), 0, &testLogger{})
+
+	saved, err := st.Save(model.Host{Title: "host1", Address: "host1.com"})
+	require.NoError(t, err)
+
+	saved.Title = "renamed"
+	updated, err := st.Save(saved)
+	require.NoError(t, err)
+	require.Equal(t, saved.ID, updated.ID)
+
+	hosts, err := st.GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, saved.ID, hosts[0].ID)
+	require.Equal(t, "renamed", hosts[0].Title)
+}
+
+func TestYAMLFile_Save_WriteError(t *testing.T) {
+	tmpDir := t.TempDir()
+	st := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
+	saved, err := st.Save(model.Host{Title: "host1", Address: "host1.com"})
+	require.NoError(t, err)
+
+	// Make the folder path invalid, so that the file cannot be written.
+	st.fsDataPath = filepath.Join(tmpDir, hostsFile, hostsFile)
+
+	_, err = st.Save(model.Host{Title: "host2", Address: "host2.com"})
+	require.Error(t, err)
+	err = st.Delete(saved.ID)
+	require.Error(t, err)
+
+	// In-memory storage should not be modified, if changes cannot be written to disk.
+	require.Len(t, st.innerStorage, 1)
+	require.Equal(t, "host1", st.innerStorage[0].Host.Title)
+}
+
+func TestYAMLFile_Save_CreatesBackup(t *testing.T) {
+	tmpDir := t.TempDir()
+	st := newYAMLStorage(context.TODO(), tmpDir, 1, &testLogger{})
+
+	_, err := st.Save(model.Host{Title: "host1", Address: "host1.com"})
+	require.NoError(t, err)
+	backups, err := ListBackups(tmpDir)
+	require.NoError(t, err)
+	require.Empty(t, backups, "there was no file to back up")
+
+	_, err = st.Save(model.Host{Title: "host2", Address: "host2.com"})
+	require.NoError(t, err)
+	backups, err = ListBackups(tmpDir)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+
+	data, err := os.ReadFile(backups[0].Path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "host1")
+	require.NotContains(t, string(data), "host2")
+}
+
+func TestYAMLFile_Save_MergesExternalChanges(t *testing.T) {
+	// Two instances of the app work with the same file.
+	tmpDir := t.TempDir()
+	st1 := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
+	st2 := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
+	_, err := st1.GetAll()
+	require.NoError(t, err)
+	_, err = st2.GetAll()
+	require.NoError(t, err)
+
+	host1, err := st1.Save(model.Host{Title: "host1", Address: "host1.com"})
+	require.NoError(t, err)
+	host2, err := st2.Save(model.Host{Title: "host2", Address: "host2.com"})
+	require.NoError(t, err)
+
+	// Changes made by the first instance are not lost.
+	hosts, err := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{}).GetAll()
+	require.NoError(t, err)
+	require.Equal(t, []string{"host1", "host2"}, lo.Map(hosts, func(h model.Host, _ int) string { return h.Title }))
+	require.Len(t, st2.innerStorage, 2)
+
+	// Host which was already deleted by another instance can be deleted again.
+	err = st2.Delete(host1.ID)
+	require.NoError(t, err)
+	err = st1.Delete(host1.ID)
+	require.NoError(t, err)
+
+	hosts, err = newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{}).GetAll()
+	require.NoError(t, err)
+	require.Len(t, hosts, 1)
+	require.Equal(t, host2.ID, hosts[0].ID)
+}
+
+func TestYAMLFile_Save_Conflict(t *testing.T) {
+	tmpDir := t.TempDir()
+	st1 := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
+	host, err := st1.Save(model.Host{Title: "host1", Address: "host1.com"})
+	require.NoError(t, err)
+
+	st2 := newYAMLStorage(context.TODO(), tmpDir, 0, &testLogger{})
+	_, err = st2.GetAll()
+	require.NoError(t, err)
+
+	host.Address = "first.host1.com"
+	_, err = st1.Save(host)
+	require.NoError(t, err)
+
+	// The same host was modified by both instances.
+	host.Address = "second.host1.com"
+	_, err = st2.Save(host)
+	require.ErrorIs(t, err, ErrConflict)
+	err = st2.Delete(host.ID)
+	require.ErrorIs(t, err, ErrConflict)
+
+	hosts, err := st1.GetAll()
+	require.NoError(t, err)
+	require.Equal(t, "first.host1.com", hosts[0].Address)
+}
+
 func TestYAMLFile_Type(t *testing.T) {
-	st := newYAMLStorage(context.TODO(), t.TempDir(), &testLogger{})
+	st := newYAMLStorage(context.TODO(), t.TempDir(), 0, &testLogger{})
 	require.Equal(t, constant.HostStorageType.YAMLFile, st.Type())
 }
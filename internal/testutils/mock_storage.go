@@ -22,12 +22,9 @@ type MockStorage struct {
 // return an error.
 func NewMockStorage(shouldFail bool) *MockStorage {
 	hosts := []host.Host{
-		// Yaml storage specific: if host has id which is equal to "0"
-		// that means that this host doesn't yet exist. It's a hack,
-		// but simplifies the application. That's why we count hosts from "1"
-		host.NewHost(1, "Mock Host 1", "", "localhost", "root", "id_rsa", "2222"),
-		host.NewHost(2, "Mock Host 2", "", "localhost", "root", "id_rsa", "2222"),
-		host.NewHost(3, "Mock Host 3", "", "localhost", "root", "id_rsa", "2222"),
+		host.NewHost("1", "Mock Host 1", "", "localhost", "root", "id_rsa", "2222"),
+		host.NewHost("2", "Mock Host 2", "", "localhost", "root", "id_rsa", "2222"),
+		host.NewHost("3", "Mock Host 3", "", "localhost", "root", "id_rsa", "2222"),
 	}
 
 	for i := range hosts {
@@ -42,12 +39,12 @@ func NewMockStorage(shouldFail bool) *MockStorage {
 }
 
 // Delete implements storage.HostStorage.
-func (ms *MockStorage) Delete(id int) error {
+func (ms *MockStorage) Delete(id string) error {
 	if ms.shouldFail {
 		return errors.New("mock error")
 	}
 
-	_, id, found := lo.FindIndexOf(ms.Hosts, func(h host.Host) bool {
+	_, index, found := lo.FindIndexOf(ms.Hosts, func(h host.Host) bool {
 		return h.ID == id
 	})
 
@@ -55,18 +52,26 @@ func (ms *MockStorage) Delete(id int) error {
 		return errors.New("host not found")
 	}
 
-	ms.Hosts = append(ms.Hosts[:id], ms.Hosts[id+1:]...)
+	ms.Hosts = append(ms.Hosts[:index], ms.Hosts[index+1:]...)
 
 	return nil
 }
 
 // Get implements storage.HostStorage.
-func (ms *MockStorage) Get(hostID int) (host.Host, error) {
+func (ms *MockStorage) Get(hostID string) (host.Host, error) {
 	if ms.shouldFail {
 		return host.Host{}, errors.New("mock error")
 	}
 
-	return ms.Hosts[hostID], nil
+	found, ok := lo.Find(ms.Hosts, func(h host.Host) bool {
+		return h.ID == hostID
+	})
+
+	if !ok {
+		return host.Host{}, constant.ErrNotFound
+	}
+
+	return found, nil
 }
 
 // GetAll implements storage.HostStorage.
@@ -84,6 +89,11 @@ func (ms *MockStorage) Save(m host.Host) (host.Host, error) {
 		return m, errors.New("mock error")
 	}
 
+	if m.ID == "" {
+		// Mimic real storages, which assign an ID when a new host is saved.
+		m.ID = fmt.Sprint(len(ms.Hosts) + 1)
+	}
+
 	ms.Hosts = append(ms.Hosts, m)
 
 	return m, nil
@@ -2,9 +2,17 @@
 package testutils_test
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"os"
+	"path/filepath"
 	"reflect"
+	"testing"
 
 	tea "charm.land/bubbletea/v2"
+	"filippo.io/age"
+	"golang.org/x/crypto/ssh"
 )
 
 // CmdToMessage - should only be used in unit tests.
@@ -35,3 +43,52 @@ func CmdToMessage(cmd tea.Cmd, messages *[]tea.Msg) {
 		*messages = append(*messages, message)
 	}
 }
+
+// WriteSSHKey - generates ed25519 ssh private key, which is protected with the passphrase if it's not empty.
+// Returns the key file path and the key, so that the key can be re-encoded.
+func WriteSSHKey(t *testing.T, passphrase string) (string, ed25519.PrivateKey) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "id_ed25519")
+	RewriteSSHKey(t, keyFile, key, passphrase)
+	return keyFile, key
+}
+
+// RewriteSSHKey - writes ssh private key to the file, protected with the passphrase if it's not empty.
+func RewriteSSHKey(t *testing.T, keyFile string, key ed25519.PrivateKey, passphrase string) {
+	t.Helper()
+	var block *pem.Block
+	var err error
+	if passphrase == "" {
+		block, err = ssh.MarshalPrivateKey(key, "goto@test")
+	} else {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(key, "goto@test", []byte(passphrase))
+	}
+	if err == nil {
+		err = os.WriteFile(keyFile, pem.EncodeToMemory(block), 0o600)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// WriteAgeIdentity - generates age identity file, the same way as age-keygen does.
+func WriteAgeIdentity(t *testing.T) string {
+	t.Helper()
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "key.txt")
+	content := "# public key: " + identity.Recipient().String() + "\n" + identity.String() + "\n"
+	if err = os.WriteFile(keyFile, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return keyFile
+}
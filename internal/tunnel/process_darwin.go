@@ -0,0 +1,25 @@
+//go:build darwin
+
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+// processStartTime - returns the process start time with microsecond precision.
+func processStartTime(pid int) (string, error) {
+	info, err := unix.SysctlKinfoProc("kern.proc.pid", pid)
+	if err != nil {
+		return "", err
+	}
+
+	if int(info.Proc.P_pid) != pid {
+		return "", errors.New("process not found")
+	}
+
+	startTime := info.Proc.P_starttime
+	return fmt.Sprintf("%d.%06d", startTime.Sec, startTime.Usec), nil
+}
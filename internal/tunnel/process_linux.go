@@ -0,0 +1,37 @@
+//go:build linux
+
+package tunnel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// startTimeField - is the index of 'starttime' in /proc/<pid>/stat, counting from the field which follows
+// the process name, see proc(5).
+const startTimeField = 22 - 3
+
+// processStartTime - returns the process start time in clock ticks after boot, prefixed with the boot ID,
+// so that a process with the same PID and start time after a reboot is not mistaken for the tunnel.
+func processStartTime(pid int) (string, error) {
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return "", err
+	}
+
+	// Process name can contain spaces and parentheses, so the fields are counted after the last ')'.
+	fields := strings.Fields(string(stat[bytes.LastIndexByte(stat, ')')+1:]))
+	if len(fields) <= startTimeField {
+		return "", errors.New("cannot read process start time")
+	}
+
+	bootID, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(bootID)) + "/" + fields[startTimeField], nil
+}
@@ -0,0 +1,37 @@
+//go:build !windows
+
+package tunnel
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// detach - starts the process in a new session, so that it does not receive signals sent to the app
+// and its terminal.
+func detach(process *exec.Cmd) {
+	process.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+}
+
+func isProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	// Signal 0 only checks that the process exists. EPERM means that it's owned by another user.
+	err := unix.Kill(pid, 0)
+	return err == nil || errors.Is(err, unix.EPERM)
+}
+
+func killProcess(pid int) error {
+	err := unix.Kill(pid, unix.SIGTERM)
+	if errors.Is(err, unix.ESRCH) {
+		// The process has ended already.
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,11 @@
+//go:build !windows && !linux && !darwin
+
+package tunnel
+
+import "errors"
+
+// processStartTime - is not supported on this platform. Tunnels, which are started by another instance
+// of the app, are not displayed, so that an unrelated process with the same PID is never stopped.
+func processStartTime(_ int) (string, error) {
+	return "", errors.New("process start time is not supported on this platform")
+}
@@ -0,0 +1,67 @@
+//go:build windows
+
+package tunnel
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive - is the exit code of a process, which is running, see GetExitCodeProcess.
+const stillActive = 259
+
+// detach - starts the process without a console, so that it keeps running when the app's console is closed.
+func detach(process *exec.Cmd) {
+	process.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: windows.CREATE_NEW_PROCESS_GROUP | windows.DETACHED_PROCESS,
+	}
+}
+
+func isProcessRunning(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer windows.CloseHandle(handle) //nolint:errcheck // The handle is only used for reading
+
+	var exitCode uint32
+	if err = windows.GetExitCodeProcess(handle, &exitCode); err != nil {
+		return false
+	}
+
+	return exitCode == stillActive
+}
+
+// processStartTime - returns the process creation time.
+func processStartTime(pid int) (string, error) {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return "", err
+	}
+	defer windows.CloseHandle(handle) //nolint:errcheck // The handle is only used for reading
+
+	var creationTime, exitTime, kernelTime, userTime windows.Filetime
+	if err = windows.GetProcessTimes(handle, &creationTime, &exitTime, &kernelTime, &userTime); err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(creationTime.Nanoseconds(), 10), nil
+}
+
+func killProcess(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		// The process has ended already.
+		return nil
+	}
+
+	return process.Kill()
+}
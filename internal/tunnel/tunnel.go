@@ -0,0 +1,322 @@
+// Package tunnel starts port forwardings in background ssh processes and keeps track of them.
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+const tunnelsFile = "tunnels.yaml"
+
+var (
+	// ErrAlreadyRunning - is returned when the forwarding of the host is already started.
+	ErrAlreadyRunning = errors.New("tunnel is already running")
+	// ErrNotRunning - is returned when the tunnel is stopped already.
+	ErrNotRunning = errors.New("tunnel is not running")
+)
+
+type iLogger interface {
+	Debug(format string, args ...any)
+	Info(format string, args ...any)
+	Error(format string, args ...any)
+}
+
+// Tunnel is a port forwarding, which runs in background 'ssh -N' process. Host title and forwarding
+// are copied, so that the tunnel can be displayed and stopped even if the host is changed or deleted.
+type Tunnel struct {
+	HostID    string       `yaml:"host_id"`
+	HostTitle string       `yaml:"host_title"`
+	Forward   host.Forward `yaml:"forward"`
+	PID       int          `yaml:"pid"`
+	// ProcessStart identifies the process together with PID, see processStartTime. PIDs are reused after
+	// a reboot or when they wrap around, so a process with the same PID can be a different one.
+	ProcessStart string    `yaml:"process_start"`
+	StartedAt    time.Time `yaml:"started_at"`
+	// LogFile contains ssh output, it's removed when the tunnel ends.
+	LogFile string `yaml:"log_file"`
+}
+
+// ID - returns tunnel identifier. A host cannot run two forwardings with the same name.
+func ID(hostID, forwardName string) string {
+	return hostID + "/" + forwardName
+}
+
+// ID - returns tunnel identifier.
+func (t Tunnel) ID() string {
+	return ID(t.HostID, t.Forward.Name)
+}
+
+// Exit - is sent when a tunnel, which is started by the app, ends without being stopped. For instance,
+// when ssh cannot connect to the host or listen to the port. Output contains ssh error messages.
+type Exit struct {
+	Tunnel Tunnel
+	Err    error
+	Output string
+}
+
+// Manager - starts and stops tunnels. Running tunnels are stored in a file, so that they are displayed
+// again, when the app restarts or runs in another terminal. Tunnels, whose processes have ended, are
+// removed from the file every time it's read. The file is locked while it's read and written, so that
+// several instances of the app can start and stop tunnels at the same time.
+type Manager struct {
+	mu       sync.Mutex
+	filePath string
+	// processes are started by this instance of the app, they're keyed by tunnel ID.
+	processes map[string]*exec.Cmd
+	exits     chan Exit
+	logger    iLogger
+}
+
+// NewManager - creates tunnel manager, which keeps running tunnels in the app home folder.
+func NewManager(appHome string, log iLogger) *Manager {
+	return &Manager{
+		filePath:  path.Join(appHome, tunnelsFile),
+		processes: make(map[string]*exec.Cmd),
+		exits:     make(chan Exit, 16), //nolint:mnd // Buffer is only required if the UI is busy
+		logger:    log,
+	}
+}
+
+// Exits - returns a channel which receives a value when a tunnel ends unexpectedly.
+func (m *Manager) Exits() <-chan Exit {
+	return m.exits
+}
+
+// Running - returns running tunnels, including the ones which are started by another instance of the app.
+func (m *Manager) Running() ([]Tunnel, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return m.read()
+}
+
+// Start - starts the forwarding of the host in background. The host jump chain must be resolved.
+func (m *Manager) Start(h host.Host, forward host.Forward) (Tunnel, error) {
+	unlock, err := m.lock()
+	if err != nil {
+		return Tunnel{}, err
+	}
+	defer unlock()
+
+	tunnels, err := m.read()
+	if err != nil {
+		return Tunnel{}, err
+	}
+
+	tunnel := Tunnel{HostID: h.ID, HostTitle: h.Title, Forward: forward}
+	if slices.ContainsFunc(tunnels, func(t Tunnel) bool { return t.ID() == tunnel.ID() }) {
+		return Tunnel{}, fmt.Errorf("%w: %s", ErrAlreadyRunning, forward.Name)
+	}
+
+	// The output is written to a file rather than to the app, because the tunnel keeps running when the app is closed.
+	logFile, err := os.CreateTemp("", "goto-tunnel-*.log")
+	if err != nil {
+		return Tunnel{}, err
+	}
+	defer logFile.Close()
+
+	// The process should not be wrapped into 'cmd /c' on Windows, otherwise it cannot be stopped.
+	command := strings.TrimPrefix(h.CmdSSHTunnel(forward), "cmd /c ")
+	process := utils.BuildProcess(command)
+	process.Stdout = logFile
+	process.Stderr = logFile
+	detach(process)
+	m.logger.Info("[EXEC] Start tunnel: '%s'", process.String())
+	if err = process.Start(); err != nil {
+		_ = os.Remove(logFile.Name())
+		return Tunnel{}, err
+	}
+
+	tunnel.PID = process.Process.Pid
+	tunnel.ProcessStart, err = processStartTime(tunnel.PID)
+	if err != nil {
+		// Other instances of the app won't display the tunnel, but it still can be stopped by this one.
+		m.logger.Error("[EXEC] Cannot read start time of tunnel process %d. %v", tunnel.PID, err)
+	}
+	tunnel.StartedAt = time.Now()
+	tunnel.LogFile = logFile.Name()
+	if err = m.write(append(tunnels, tunnel)); err != nil {
+		_ = process.Process.Kill()
+		return Tunnel{}, err
+	}
+
+	m.processes[tunnel.ID()] = process
+	go m.wait(tunnel, process)
+
+	return tunnel, nil
+}
+
+// Stop - ends the tunnel process, even if it's started by another instance of the app.
+func (m *Manager) Stop(tunnelID string) error {
+	unlock, err := m.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tunnels, err := m.read()
+	if err != nil {
+		return err
+	}
+
+	index := slices.IndexFunc(tunnels, func(t Tunnel) bool { return t.ID() == tunnelID })
+	if index < 0 {
+		return fmt.Errorf("%w: %s", ErrNotRunning, tunnelID)
+	}
+
+	m.logger.Info("[EXEC] Stop tunnel %q, pid: %d", tunnelID, tunnels[index].PID)
+	if process, ok := m.processes[tunnelID]; ok {
+		// The process is removed first, so that its exit is not reported.
+		delete(m.processes, tunnelID)
+		err = process.Process.Kill()
+	} else if m.isTunnelProcess(tunnels[index]) {
+		err = killProcess(tunnels[index].PID)
+	} else {
+		// The process has ended, and its PID is taken by another process after read() had checked it.
+		m.logger.Info("[EXEC] Tunnel %q has ended already, pid %d is not signalled", tunnelID, tunnels[index].PID)
+		removeLogFile(tunnels[index])
+		if err = m.write(slices.Delete(tunnels, index, index+1)); err != nil {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s", ErrNotRunning, tunnelID)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	removeLogFile(tunnels[index])
+	return m.write(slices.Delete(tunnels, index, index+1))
+}
+
+func (m *Manager) wait(tunnel Tunnel, process *exec.Cmd) {
+	err := process.Wait()
+
+	unlock, lockErr := m.lock()
+	if lockErr != nil {
+		m.logger.Error("[EXEC] Cannot lock %q. %v", m.filePath, lockErr)
+		return
+	}
+	defer unlock()
+
+	if m.processes[tunnel.ID()] != process {
+		// The tunnel is stopped by user.
+		return
+	}
+
+	delete(m.processes, tunnel.ID())
+	output, _ := os.ReadFile(tunnel.LogFile)
+	processOutput := strings.TrimSpace(string(output))
+	m.logger.Error("[EXEC] Tunnel %q ended. %v %s", tunnel.ID(), err, processOutput)
+
+	// Processes, which have ended, are removed from the file when it's read.
+	if _, readErr := m.read(); readErr != nil {
+		m.logger.Error("[EXEC] Cannot update %q. %v", m.filePath, readErr)
+	}
+
+	select {
+	case m.exits <- Exit{Tunnel: tunnel, Err: err, Output: processOutput}:
+	default:
+		m.logger.Debug("[EXEC] Tunnel exit is not reported, nobody listens")
+	}
+}
+
+// read - returns running tunnels from the file. Tunnels, whose processes have ended, are removed from the file.
+func (m *Manager) read() ([]Tunnel, error) {
+	data, err := os.ReadFile(m.filePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Tunnel{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	tunnels := []Tunnel{}
+	if err = yaml.Unmarshal(data, &tunnels); err != nil {
+		return nil, fmt.Errorf("cannot read %q: %w", m.filePath, err)
+	}
+
+	running := slices.DeleteFunc(slices.Clone(tunnels), func(t Tunnel) bool {
+		_, startedHere := m.processes[t.ID()]
+		if startedHere || m.isTunnelProcess(t) {
+			return false
+		}
+
+		removeLogFile(t)
+		return true
+	})
+
+	if len(running) != len(tunnels) {
+		m.logger.Info("[EXEC] Remove %d tunnels which have ended", len(tunnels)-len(running))
+		if err = m.write(running); err != nil {
+			return nil, err
+		}
+	}
+
+	return running, nil
+}
+
+// isTunnelProcess - returns true if the tunnel process is running. The process is only considered to be
+// the tunnel if it has the same start time, otherwise the PID was reused by an unrelated process.
+func (m *Manager) isTunnelProcess(tunnel Tunnel) bool {
+	if !isProcessRunning(tunnel.PID) {
+		return false
+	}
+
+	startTime, err := processStartTime(tunnel.PID)
+	if err != nil || utils.StringEmpty(&tunnel.ProcessStart) || startTime != tunnel.ProcessStart {
+		m.logger.Info("[EXEC] Process %d is not tunnel %q, it's started at %q, not at %q. %v",
+			tunnel.PID, tunnel.ID(), startTime, tunnel.ProcessStart, err)
+		return false
+	}
+
+	return true
+}
+
+// lock - prevents other goroutines and other instances of the app from changing the file. Returns
+// a function which releases the lock.
+func (m *Manager) lock() (func(), error) {
+	m.mu.Lock()
+	unlockFile, err := utils.LockFile(m.filePath)
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+
+	return func() {
+		unlockFile()
+		m.mu.Unlock()
+	}, nil
+}
+
+func removeLogFile(tunnel Tunnel) {
+	if !utils.StringEmpty(&tunnel.LogFile) {
+		_ = os.Remove(tunnel.LogFile)
+	}
+}
+
+func (m *Manager) write(tunnels []Tunnel) error {
+	data, err := yaml.Marshal(tunnels)
+	if err != nil {
+		return err
+	}
+
+	return utils.WriteFileAtomic(m.filePath, data, 0o600)
+}
@@ -0,0 +1,149 @@
+package tunnel
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+)
+
+// fakeSSH - replaces ssh with a shell script, so that tunnels do not connect anywhere.
+func fakeSSH(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on Windows")
+	}
+
+	binDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "ssh"), []byte("#!/bin/sh\n"+script+"\n"), 0o700))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+var (
+	mockHost    = host.Host{ID: "1", Title: "db-gateway", Address: "localhost"}
+	mockForward = host.Forward{Name: "db", Type: host.ForwardTypes.Local, Spec: "5432:db.internal:5432"}
+)
+
+func TestManager_StartStop(t *testing.T) {
+	fakeSSH(t, "exec sleep 10")
+	appHome := t.TempDir()
+	manager := NewManager(appHome, &mocklogger.Logger{})
+
+	tunnel, err := manager.Start(mockHost, mockForward)
+	require.NoError(t, err)
+	require.Equal(t, "1/db", tunnel.ID())
+	require.Positive(t, tunnel.PID)
+	require.FileExists(t, tunnel.LogFile)
+
+	_, err = manager.Start(mockHost, mockForward)
+	require.ErrorIs(t, err, ErrAlreadyRunning)
+
+	// Tunnels are displayed again when the app restarts.
+	restarted := NewManager(appHome, &mocklogger.Logger{})
+	tunnels, err := restarted.Running()
+	require.NoError(t, err)
+	require.Len(t, tunnels, 1)
+	require.Equal(t, "db-gateway", tunnels[0].HostTitle)
+	require.Equal(t, mockForward, tunnels[0].Forward)
+
+	require.NoError(t, manager.Stop(tunnel.ID()))
+	require.ErrorIs(t, manager.Stop(tunnel.ID()), ErrNotRunning)
+	require.NoFileExists(t, tunnel.LogFile)
+	tunnels, err = restarted.Running()
+	require.NoError(t, err)
+	require.Empty(t, tunnels)
+}
+
+func TestManager_StopStartedByAnotherInstance(t *testing.T) {
+	fakeSSH(t, "exec sleep 10")
+	appHome := t.TempDir()
+	manager := NewManager(appHome, &mocklogger.Logger{})
+	tunnel, err := manager.Start(mockHost, mockForward)
+	require.NoError(t, err)
+
+	restarted := NewManager(appHome, &mocklogger.Logger{})
+	require.NoError(t, restarted.Stop(tunnel.ID()))
+
+	// The instance, which started the tunnel, reports that it has ended.
+	select {
+	case exit := <-manager.Exits():
+		require.Equal(t, tunnel.ID(), exit.Tunnel.ID())
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "tunnel exit is not reported")
+	}
+}
+
+func TestManager_TunnelEnds(t *testing.T) {
+	fakeSSH(t, "echo 'Permission denied (publickey).' >&2; exit 255")
+	manager := NewManager(t.TempDir(), &mocklogger.Logger{})
+	tunnel, err := manager.Start(mockHost, mockForward)
+	require.NoError(t, err)
+
+	select {
+	case exit := <-manager.Exits():
+		require.Equal(t, tunnel.ID(), exit.Tunnel.ID())
+		require.Error(t, exit.Err)
+		require.Equal(t, "Permission denied (publickey).", exit.Output)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "tunnel exit is not reported")
+	}
+
+	tunnels, err := manager.Running()
+	require.NoError(t, err)
+	require.Empty(t, tunnels)
+	require.NoFileExists(t, tunnel.LogFile)
+}
+
+func TestManager_ReusedPID(t *testing.T) {
+	manager := NewManager(t.TempDir(), &mocklogger.Logger{})
+	// The tunnel has ended, and its PID is taken by the test process.
+	stale := Tunnel{HostID: mockHost.ID, Forward: mockForward, PID: os.Getpid(), ProcessStart: "stale"}
+	require.NoError(t, manager.write([]Tunnel{stale}))
+
+	require.ErrorIs(t, manager.Stop(stale.ID()), ErrNotRunning)
+	tunnels, err := manager.Running()
+	require.NoError(t, err)
+	require.Empty(t, tunnels)
+
+	// Tunnels, which are written by older versions of the app, cannot be identified either.
+	stale.ProcessStart = ""
+	require.NoError(t, manager.write([]Tunnel{stale}))
+	tunnels, err = manager.Running()
+	require.NoError(t, err)
+	require.Empty(t, tunnels)
+}
+
+func TestManager_StartFromSeveralInstances(t *testing.T) {
+	fakeSSH(t, "exec sleep 10")
+	appHome := t.TempDir()
+	managers := []*Manager{NewManager(appHome, &mocklogger.Logger{}), NewManager(appHome, &mocklogger.Logger{})}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(managers))
+	for i, manager := range managers {
+		wg.Go(func() {
+			forward := mockForward
+			forward.Name = fmt.Sprintf("db%d", i)
+			_, errs[i] = manager.Start(mockHost, forward)
+		})
+	}
+	wg.Wait()
+	require.NoError(t, errors.Join(errs...))
+
+	// Every instance reads the file after another one has written it, so no tunnel is lost.
+	tunnels, err := managers[0].Running()
+	require.NoError(t, err)
+	require.Len(t, tunnels, 2)
+	for _, tunnel := range tunnels {
+		require.NoError(t, managers[0].Stop(tunnel.ID()))
+	}
+}
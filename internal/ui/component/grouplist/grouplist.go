@@ -169,7 +169,8 @@ func (m *Model) loadItems() tea.Cmd {
 	// Create a list of unique groups.
 	groupList := []string{}
 	lo.ForEach(hosts, func(h host.Host, _ int) {
-		if strings.TrimSpace(h.Group) != "" {
+		// Hidden hosts are not displayed, their groups would be empty.
+		if strings.TrimSpace(h.Group) != "" && !h.Hidden {
 			_, found := lo.Find(groupList, func(g string) bool {
 				return strings.EqualFold(g, h.Group)
 			})
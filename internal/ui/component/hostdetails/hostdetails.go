@@ -0,0 +1,156 @@
+// Package hostdetails implements the view, which displays effective host configuration, printed by 'ssh -G'.
+package hostdetails
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"charm.land/bubbles/v2/help"
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/viewport"
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+
+	hostModel "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+type iLogger interface {
+	Debug(format string, args ...any)
+}
+
+const loadingText = "Loading, please wait..."
+
+// Model - displays all settings, which ssh uses to connect to the host. The content is scrollable.
+type Model struct {
+	host     hostModel.Host
+	appState *state.State
+	viewport viewport.Model
+	ready    bool
+	help     help.Model
+	keyMap   keyMap
+	logger   iLogger
+	styles   styles
+	// loadError is set when 'ssh -G' fails, it's displayed instead of the settings.
+	loadError string
+}
+
+// New - creates host details view. The configuration is taken from the host, and it's updated
+// when 'ssh -G' output for the host is loaded.
+func New(_ context.Context, host hostModel.Host, appState *state.State, log iLogger) *Model {
+	m := Model{
+		host:     host,
+		appState: appState,
+		help:     help.New(),
+		keyMap:   keys,
+		logger:   log,
+		styles:   defaultStyles(),
+	}
+
+	m.help.Styles = m.styles.help
+
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.updateViewPort(msg)
+	case tea.KeyPressMsg:
+		if key.Matches(msg, m.keyMap.Close) {
+			m.logger.Debug("[UI] Close host details for host id: %v", m.host.ID)
+			return m, message.TeaCmd(message.ViewHostDetailsClose{})
+		}
+
+		m.viewport, cmd = m.viewport.Update(msg)
+	case message.HostSSHConfigLoadComplete:
+		if msg.HostID == m.host.ID {
+			m.host.SSHHostConfig = &msg.Config
+			m.loadError = ""
+			m.viewport.SetContent(m.contentView())
+		}
+	case message.HostSSHConfigLoadFailed:
+		if msg.HostID == m.host.ID {
+			m.logger.Debug("[UI] Cannot load host details for host id: %v", m.host.ID)
+			m.loadError = msg.Err
+			m.viewport.SetContent(m.contentView())
+		}
+	}
+
+	return m, cmd
+}
+
+func (m *Model) View() tea.View {
+	if !m.ready {
+		// Create viewport, ideally this call should be located in init function,
+		// but this function does not trigger for child components
+		m.updateViewPort(nil)
+	}
+
+	viewContent := fmt.Sprintf("%s\n%s\n%s", m.headerView(), m.viewport.View(), m.helpView())
+	return tea.NewView(viewContent)
+}
+
+func (m *Model) updateViewPort(msg tea.Msg) {
+	headerHeight := lipgloss.Height(m.headerView())
+	helpMenuHeight := lipgloss.Height(m.helpView())
+
+	if !m.ready {
+		m.ready = true
+		m.viewport = viewport.New(
+			viewport.WithWidth(m.appState.Width),
+			viewport.WithHeight(m.appState.Height-headerHeight-helpMenuHeight))
+		m.viewport.SetContent(m.contentView())
+	} else if resizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.viewport.SetWidth(resizeMsg.Width)
+		m.viewport.SetHeight(resizeMsg.Height - headerHeight - helpMenuHeight)
+		m.logger.Debug("[UI] Set host details viewport size: %d %d", m.viewport.Width(), m.viewport.Height())
+	}
+}
+
+// contentView - displays settings in the order ssh prints them. Settings, which have several values,
+// are displayed on several lines, the key is only displayed once.
+func (m *Model) contentView() string {
+	if m.loadError != "" {
+		return m.styles.componentMargins.Render(
+			m.styles.textReadonly.Render("Cannot load host configuration.\n" + m.loadError))
+	}
+
+	if m.host.SSHHostConfig == nil || len(m.host.SSHHostConfig.Settings) == 0 {
+		return m.styles.componentMargins.Render(m.styles.textReadonly.Render(loadingText))
+	}
+
+	keyWidth := 0
+	for _, setting := range m.host.SSHHostConfig.Settings {
+		keyWidth = max(keyWidth, len(setting.Key))
+	}
+
+	lines := []string{}
+	for _, setting := range m.host.SSHHostConfig.Settings {
+		for i, value := range setting.Values {
+			key := ""
+			if i == 0 {
+				key = setting.Key
+			}
+
+			lines = append(lines, fmt.Sprintf("%s  %s",
+				m.styles.settingKey.Render(fmt.Sprintf("%-*s", keyWidth, key)), value))
+		}
+	}
+
+	return m.styles.componentMargins.Render(strings.Join(lines, "\n"))
+}
+
+func (m *Model) headerView() string {
+	return m.styles.title.Render("ssh -G " + m.host.Title)
+}
+
+func (m *Model) helpView() string {
+	return m.styles.keyMap.Render(m.help.View(m.keyMap))
+}
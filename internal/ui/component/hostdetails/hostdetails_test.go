@@ -0,0 +1,80 @@
+package hostdetails
+
+import (
+	"context"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/require"
+
+	hostModel "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/model/sshconfig"
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+	"github.com/grafviktor/goto/internal/ui/message"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+func TestUpdate_HostSSHConfigLoadComplete(t *testing.T) {
+	host := hostModel.Host{ID: "1", Title: "web1", SSHHostConfig: sshconfig.StubConfig()}
+	model := New(context.TODO(), host, &state.State{Width: 80, Height: 40}, &mocklogger.Logger{})
+	require.Contains(t, utils.StripStyles(model.View().Content), loadingText)
+	require.Contains(t, utils.StripStyles(model.View().Content), "ssh -G web1")
+
+	// Config of another host is ignored.
+	config := sshconfig.Parse("user admin\nidentityfile ~/.ssh/id_rsa\nidentityfile ~/.ssh/id_ed25519\nport 22\n")
+	model.Update(message.HostSSHConfigLoadComplete{HostID: "2", Config: *config})
+	require.Contains(t, utils.StripStyles(model.contentView()), loadingText)
+
+	model.Update(message.HostSSHConfigLoadComplete{HostID: "1", Config: *config})
+	view := utils.StripStyles(model.contentView())
+	require.NotContains(t, view, loadingText)
+	// Keys are aligned, multi-valued settings are displayed on several lines.
+	require.Contains(t, view, "user          admin")
+	require.Contains(t, view, "identityfile  ~/.ssh/id_rsa")
+	require.Contains(t, view, "              ~/.ssh/id_ed25519")
+	require.Contains(t, view, "port          22")
+}
+
+func TestUpdate_HostSSHConfigLoadFailed(t *testing.T) {
+	host := hostModel.Host{ID: "1", Title: "web1", SSHHostConfig: sshconfig.StubConfig()}
+	model := New(context.TODO(), host, &state.State{Width: 80, Height: 40}, &mocklogger.Logger{})
+
+	// Error of another host is ignored.
+	model.Update(message.HostSSHConfigLoadFailed{HostID: "2", Err: "Error:   bad configuration option"})
+	require.Contains(t, utils.StripStyles(model.contentView()), loadingText)
+
+	model.Update(message.HostSSHConfigLoadFailed{HostID: "1", Err: "Error:   bad configuration option"})
+	view := utils.StripStyles(model.contentView())
+	require.NotContains(t, view, loadingText)
+	require.Contains(t, view, "Cannot load host configuration.")
+	require.Contains(t, view, "bad configuration option")
+
+	// The error is cleared when the config is loaded.
+	config := sshconfig.Parse("user admin\n")
+	model.Update(message.HostSSHConfigLoadComplete{HostID: "1", Config: *config})
+	view = utils.StripStyles(model.contentView())
+	require.NotContains(t, view, "bad configuration option")
+	require.Contains(t, view, "user  admin")
+}
+
+func TestUpdate_Close(t *testing.T) {
+	model := New(context.TODO(), hostModel.Host{ID: "1"}, &state.State{}, &mocklogger.Logger{})
+
+	_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	require.IsType(t, message.ViewHostDetailsClose{}, cmd())
+
+	_, cmd = model.Update(tea.KeyPressMsg{Code: 'q', Text: "q"})
+	require.IsType(t, message.ViewHostDetailsClose{}, cmd())
+}
+
+func TestUpdate_Scroll(t *testing.T) {
+	config := sshconfig.Parse("user admin\nport 22\nhostname 10.0.0.1\nloglevel INFO\ntcpkeepalive yes\n")
+	host := hostModel.Host{ID: "1", Title: "web1", SSHHostConfig: config}
+	model := New(context.TODO(), host, &state.State{Width: 80, Height: 12}, &mocklogger.Logger{})
+	model.View()
+	require.Equal(t, 0, model.viewport.YOffset())
+
+	model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, 1, model.viewport.YOffset())
+}
@@ -0,0 +1,34 @@
+package hostdetails
+
+import (
+	"charm.land/bubbles/v2/key"
+)
+
+type keyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Close key.Binding
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.Up, k.Down, k.Close}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	return nil
+}
+
+var keys = keyMap{
+	Up: key.NewBinding(
+		key.WithKeys("up", "k", "pgup"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "j", "pgdown"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "q"),
+		key.WithHelp("esc", "close"),
+	),
+}
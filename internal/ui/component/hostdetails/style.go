@@ -0,0 +1,30 @@
+package hostdetails
+
+import (
+	"charm.land/bubbles/v2/help"
+	"charm.land/lipgloss/v2"
+
+	"github.com/grafviktor/goto/internal/ui/theme"
+)
+
+type styles struct {
+	componentMargins lipgloss.Style
+	keyMap           lipgloss.Style
+	settingKey       lipgloss.Style
+	title            lipgloss.Style
+	textReadonly     lipgloss.Style
+	help             help.Styles
+}
+
+func defaultStyles() styles {
+	themeSettings := theme.Get().Styles
+
+	return styles{
+		componentMargins: lipgloss.NewStyle().Margin(1, 2),
+		help:             themeSettings.ListHelp,
+		keyMap:           themeSettings.EditForm.KeyMap,
+		settingKey:       themeSettings.EditForm.SelectedTitle,
+		textReadonly:     themeSettings.EditForm.TextReadonly,
+		title:            themeSettings.EditForm.Title,
+	}
+}
@@ -0,0 +1,73 @@
+package hostedit
+
+import (
+	"fmt"
+	"strings"
+
+	hostModel "github.com/grafviktor/goto/internal/model/host"
+)
+
+// forwardTypeLetters - port forwarding types are written the same way as ssh options: "L", "R" and "D".
+var forwardTypeLetters = map[string]hostModel.ForwardType{
+	"L": hostModel.ForwardTypes.Local,
+	"R": hostModel.ForwardTypes.Remote,
+	"D": hostModel.ForwardTypes.Dynamic,
+}
+
+// parseForwards - reads port forwardings from the text, which is written as "name type spec" and separated
+// by commas. For instance: "db L 5432:db.internal:5432, socks D 1080".
+func parseForwards(value string) ([]hostModel.Forward, error) {
+	forwards := []hostModel.Forward{}
+	names := map[string]bool{}
+	for entry := range strings.SplitSeq(value, ",") {
+		fields := strings.Fields(entry)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) != 3 { //nolint:mnd // name, type and spec
+			return nil, fmt.Errorf("forward %q must be written as 'name L|R|D spec'", strings.TrimSpace(entry))
+		}
+
+		forwardType, ok := forwardTypeLetters[strings.ToUpper(fields[1])]
+		if !ok {
+			return nil, fmt.Errorf("forward %q has unknown type %q, use L, R or D", fields[0], fields[1])
+		}
+
+		forward := hostModel.Forward{Name: fields[0], Type: forwardType, Spec: fields[2]}
+		if err := forward.Validate(); err != nil {
+			return nil, err
+		}
+
+		if names[forward.Name] {
+			return nil, fmt.Errorf("forward name %q is used more than once", forward.Name)
+		}
+
+		names[forward.Name] = true
+		forwards = append(forwards, forward)
+	}
+
+	return forwards, nil
+}
+
+// formatForwards - writes port forwardings the same way as parseForwards reads them.
+func formatForwards(forwards []hostModel.Forward) string {
+	entries := make([]string, 0, len(forwards))
+	for _, forward := range forwards {
+		letter := "L"
+		for l, forwardType := range forwardTypeLetters {
+			if forwardType == forward.Type {
+				letter = l
+			}
+		}
+
+		entries = append(entries, fmt.Sprintf("%s %s %s", forward.Name, letter, forward.Spec))
+	}
+
+	return strings.Join(entries, ", ")
+}
+
+func forwardsValidator(s string) error {
+	_, err := parseForwards(s)
+	return err
+}
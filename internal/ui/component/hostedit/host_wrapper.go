@@ -1,10 +1,17 @@
 package hostedit
 
 import (
+	"strings"
+
 	model "github.com/grafviktor/goto/internal/model/host"
 )
 
-type hostModelWrapper struct{ *model.Host }
+type hostModelWrapper struct {
+	*model.Host
+	// typedValues keep values of list inputs, such as port forwardings, as user typed them, even if
+	// they cannot be parsed yet. They're keyed by input index.
+	typedValues map[int]string
+}
 
 func (m *hostModelWrapper) getHostAttributeValueByIndex(inputType int) string {
 	switch inputType {
@@ -16,12 +23,36 @@ func (m *hostModelWrapper) getHostAttributeValueByIndex(inputType int) string {
 		return m.Group
 	case inputDescription:
 		return m.Description
+	case inputTags:
+		if value, ok := m.typedValues[inputType]; ok {
+			return value
+		}
+
+		return formatTags(m.Tags)
 	case inputLogin:
 		return m.LoginName
 	case inputNetworkPort:
 		return m.RemotePort
 	case inputIdentityFile:
 		return m.IdentityFilePath
+	case inputRemoteCommand:
+		return m.RemoteCommand
+	case inputRequestTTY:
+		return m.RequestTTY
+	case inputSSHOptions:
+		if value, ok := m.typedValues[inputType]; ok {
+			return value
+		}
+
+		return formatSSHOptions(m.SSHOptions)
+	case inputForwards:
+		if value, ok := m.typedValues[inputType]; ok {
+			return value
+		}
+
+		return formatForwards(m.Forwards)
+	case inputFile:
+		return m.SourcePath
 	default:
 		return ""
 	}
@@ -37,12 +68,20 @@ func (m *hostModelWrapper) getSSHConfigValueByIndex(inputType int) string {
 		return m.Group
 	case inputDescription:
 		return m.Description
+	case inputTags:
+		return formatTags(m.Tags)
 	case inputLogin:
 		return m.SSHHostConfig.User
 	case inputNetworkPort:
 		return m.SSHHostConfig.Port
 	case inputIdentityFile:
 		return m.SSHHostConfig.IdentityFile
+	case inputJumpHosts:
+		return m.SSHHostConfig.Value("proxyjump")
+	case inputRemoteCommand:
+		return m.SSHHostConfig.Value("remotecommand")
+	case inputRequestTTY:
+		return m.SSHHostConfig.Value("requesttty")
 	default:
 		return ""
 	}
@@ -58,17 +97,38 @@ func (m *hostModelWrapper) setHostAttributeByIndex(inputType int, value string)
 		m.Group = value
 	case inputDescription:
 		m.Description = value
+	case inputTags:
+		m.typedValues[inputType] = value
+		m.Tags = parseTags(value)
 	case inputLogin:
 		m.LoginName = value
 	case inputNetworkPort:
 		m.RemotePort = value
 	case inputIdentityFile:
 		m.IdentityFilePath = value
+	case inputRemoteCommand:
+		m.RemoteCommand = strings.TrimSpace(value)
+	case inputRequestTTY:
+		m.RequestTTY = value
+	case inputSSHOptions:
+		m.typedValues[inputType] = value
+		// Invalid options are not saved, see sshOptionsValidator.
+		if options, err := parseSSHOptions(value); err == nil {
+			m.SSHOptions = options
+		}
+	case inputForwards:
+		m.typedValues[inputType] = value
+		// Invalid forwardings are not saved, see forwardsValidator.
+		if forwards, err := parseForwards(value); err == nil {
+			m.Forwards = forwards
+		}
+	case inputFile:
+		m.SourcePath = value
 	}
 }
 
 func wrap(host *model.Host) hostModelWrapper {
-	return hostModelWrapper{Host: host}
+	return hostModelWrapper{Host: host, typedValues: make(map[int]string)}
 }
 
 func (m *hostModelWrapper) unwrap() model.Host {
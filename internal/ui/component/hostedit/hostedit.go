@@ -2,9 +2,11 @@
 package hostedit
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -17,10 +19,13 @@ import (
 	"charm.land/lipgloss/v2"
 	"github.com/samber/lo"
 
+	"github.com/grafviktor/goto/internal/constant"
 	hostModel "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/model/sshcommand"
 	"github.com/grafviktor/goto/internal/model/sshconfig"
 	"github.com/grafviktor/goto/internal/state"
 	"github.com/grafviktor/goto/internal/storage"
+	sshConfigStorage "github.com/grafviktor/goto/internal/storage/sshconfig"
 	"github.com/grafviktor/goto/internal/ui/component/input"
 	"github.com/grafviktor/goto/internal/ui/message"
 	"github.com/grafviktor/goto/internal/utils"
@@ -48,9 +53,16 @@ const (
 	inputAddress
 	inputDescription
 	inputGroup
+	inputTags
 	inputLogin
 	inputNetworkPort
 	inputIdentityFile
+	inputJumpHosts
+	inputSSHOptions
+	inputRemoteCommand
+	inputRequestTTY
+	inputForwards
+	inputFile
 )
 
 type itemID struct{}
@@ -76,6 +88,22 @@ func notEmptyValidator(s string) error {
 	return nil
 }
 
+// hostAddressValidator - accepts a host name, IPv4 or IPv6 address. IPv6 address can be enclosed in
+// square brackets and contain a zone ID. Values with spaces or '@' are custom ssh commands, see
+// Host.IsUserDefinedSSHCommand, they're not validated.
+func hostAddressValidator(s string) error {
+	if err := notEmptyValidator(s); err != nil {
+		return err
+	}
+
+	value := strings.TrimSpace(s)
+	if strings.ContainsAny(value, " @") || utils.IsValidHostAddress(value) {
+		return nil
+	}
+
+	return errors.New("host must be a host name, IPv4 or IPv6 address")
+}
+
 func networkPortValidator(s string) error {
 	if utils.StringEmpty(&s) {
 		return nil
@@ -91,21 +119,30 @@ func networkPortValidator(s string) error {
 }
 
 func getKeyMap(host hostModel.Host, focusedInput int) keyMap {
+	keys.SelectJumpHost.SetEnabled(focusedInput == inputJumpHosts && !host.IsReadOnly())
+	keys.AddJumpHost.SetEnabled(focusedInput == inputJumpHosts && !host.IsReadOnly())
+	keys.RemoveJumpHost.SetEnabled(focusedInput == inputJumpHosts && !host.IsReadOnly())
+	keys.SelectTTYMode.SetEnabled(focusedInput == inputRequestTTY && !host.IsReadOnly())
+	keys.CompleteTag.SetEnabled(focusedInput == inputTags && !host.IsReadOnly())
+
 	switch {
 	case host.IsReadOnly():
 		keys.CopyInputValue.SetEnabled(false)
+		keys.SelectFile.SetEnabled(false)
 		keys.Save.SetEnabled(false)
 		keys.Up.SetEnabled(false)
 		keys.Down.SetEnabled(false)
 		keys.Discard.SetHelp("esc", "close")
 	case focusedInput == inputTitle || focusedInput == inputAddress:
 		keys.CopyInputValue.SetEnabled(true)
+		keys.SelectFile.SetEnabled(false)
 		keys.Save.SetEnabled(true)
 		keys.Up.SetEnabled(true)
 		keys.Down.SetEnabled(true)
 		keys.Discard.SetHelp("esc", "discard")
 	default:
 		keys.CopyInputValue.SetEnabled(false)
+		keys.SelectFile.SetEnabled(focusedInput == inputFile)
 		keys.Save.SetEnabled(true)
 		keys.Up.SetEnabled(true)
 		keys.Down.SetEnabled(true)
@@ -122,6 +159,9 @@ type EditModel struct {
 	host         hostModelWrapper
 	hostStorage  storage.HostStorage
 	inputs       []input.Input
+	inventories  []string
+	jumpHosts    []hostModel.Host
+	knownTags    []string
 	isNewHost    bool
 	keyMap       keyMap
 	logger       iLogger
@@ -136,18 +176,24 @@ type EditModel struct {
 func New(ctx context.Context, storage storage.HostStorage, state *state.State, log iLogger) *EditModel {
 	initialFocusedInput := inputTitle
 
-	// If we can't cast host id to int, that means we're adding a new host. Ignore the error
-	hostID, _ := ctx.Value(ItemID).(int)
+	// If we can't cast host id to string, that means we're adding a new host. Ignore the error
+	hostID, _ := ctx.Value(ItemID).(string)
 	host, hostNotFoundErr := storage.Get(hostID)
+	inventories := listInventories(storage)
 	if hostNotFoundErr != nil {
 		// Logger should notify that this is a new host
 		host = hostModel.Host{Group: state.Group}
+		if len(inventories) > 0 {
+			// New hosts are saved to the default file, which comes first.
+			host.SourcePath = inventories[0]
+		}
 	}
 	host.SSHHostConfig = sshconfig.StubConfig()
 
 	m := EditModel{
-		inputs:       make([]input.Input, 7), //nolint:mnd // Quantity of input components is 7
+		inputs:       make([]input.Input, 14), //nolint:mnd // Quantity of input components is 14
 		hostStorage:  storage,
+		inventories:  inventories,
 		host:         wrap(&host),
 		help:         help.New(),
 		keyMap:       getKeyMap(host, initialFocusedInput),
@@ -176,7 +222,7 @@ func New(ctx context.Context, storage storage.HostStorage, state *state.State, l
 			t.SetLabel("Host")
 			t.CharLimit = 128
 			t.SetValue(host.Address)
-			t.Validate = notEmptyValidator
+			t.Validate = hostAddressValidator
 			t.Tooltip = "ssh"
 		case inputDescription:
 			t.SetLabel("Description")
@@ -186,6 +232,12 @@ func New(ctx context.Context, storage storage.HostStorage, state *state.State, l
 			t.SetLabel("Group")
 			t.CharLimit = 512
 			t.SetValue(host.Group)
+		case inputTags:
+			t.SetLabel("Tags")
+			t.CharLimit = 512
+			t.SetValue(formatTags(host.Tags))
+			t.ShowSuggestions = true
+			t.KeyMap.AcceptSuggestion = tagCompletionKey
 		case inputLogin:
 			t.SetLabel("Login")
 			t.CharLimit = 128
@@ -199,6 +251,30 @@ func New(ctx context.Context, storage storage.HostStorage, state *state.State, l
 			t.SetLabel("Identity File")
 			t.CharLimit = 512
 			t.SetValue(host.IdentityFilePath)
+		case inputJumpHosts:
+			t.SetLabel("Jump Hosts")
+			t.Validate = m.validateJumpHosts
+		case inputSSHOptions:
+			t.SetLabel("SSH Options")
+			t.CharLimit = 1024
+			t.SetValue(formatSSHOptions(host.SSHOptions))
+			t.Validate = sshOptionsValidator
+		case inputRemoteCommand:
+			t.SetLabel("Remote Command")
+			t.CharLimit = 512
+			t.SetValue(host.RemoteCommand)
+			t.Validate = sshcommand.ValidateRemoteCommand
+		case inputRequestTTY:
+			t.SetLabel("Request TTY")
+			t.SetValue(host.RequestTTY)
+		case inputForwards:
+			t.SetLabel("Port Forwards")
+			t.CharLimit = 1024
+			t.SetValue(formatForwards(host.Forwards))
+			t.Validate = forwardsValidator
+		case inputFile:
+			t.SetLabel("File")
+			t.SetValue(host.SourcePath)
 		}
 
 		m.inputs[i] = t
@@ -212,6 +288,14 @@ func New(ctx context.Context, storage storage.HostStorage, state *state.State, l
 	return &m
 }
 
+func listInventories(hostStorage storage.HostStorage) []string {
+	if lister, ok := hostStorage.(storage.InventoryLister); ok {
+		return lister.Inventories()
+	}
+
+	return []string{}
+}
+
 func (m *EditModel) Init() tea.Cmd { return nil }
 
 func (m *EditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -223,13 +307,13 @@ func (m *EditModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateViewPort(msg)
 	case tea.KeyPressMsg:
 		cmd = m.handleKeyboardEvent(msg)
-		m.viewport.SetContent(m.inputsView())
+		m.viewport.SetContent(m.contentView())
 	case debouncedMessage:
 		cmd = m.handleDebouncedMessage(msg)
 	case message.HostSSHConfigLoadComplete:
 		m.host.SSHHostConfig = &msg.Config
 		m.updateInputFields()
-		m.viewport.SetContent(m.inputsView())
+		m.viewport.SetContent(m.contentView())
 	case message.HideUINotification:
 		if msg.ComponentName == "hostedit" {
 			m.logger.Debug("[UI] Hide notification message")
@@ -265,15 +349,34 @@ func (m *EditModel) handleKeyboardEvent(msg tea.KeyPressMsg) tea.Cmd {
 		return message.TeaCmd(message.ViewHostEditClose{})
 	case m.host.IsReadOnly():
 		m.logger.Debug("[UI] Received a key event. Cannot modify a readonly host.")
-		return m.displayNotificationMsg("host loaded from SSH config is readonly")
+		return m.displayNotificationMsg("host loaded from remote SSH config is readonly")
 	case key.Matches(msg, m.keyMap.Save):
 		m.logger.Info("[UI] Save changes for host id: %v", m.host.ID)
 		return m.save(msg)
 	case key.Matches(msg, m.keyMap.CopyInputValue):
 		m.handleCopyInputValueShortcut()
 		return nil
+	case key.Matches(msg, m.keyMap.SelectFile):
+		m.selectNextInventory(msg)
+		return nil
+	case key.Matches(msg, m.keyMap.SelectJumpHost):
+		m.selectNextJumpHost(msg)
+		return nil
+	case key.Matches(msg, m.keyMap.AddJumpHost):
+		m.addJumpHost()
+		return nil
+	case key.Matches(msg, m.keyMap.RemoveJumpHost):
+		m.removeJumpHost()
+		return nil
+	case key.Matches(msg, m.keyMap.SelectTTYMode):
+		m.selectNextTTYMode(msg)
+		return nil
 	case key.Matches(msg, m.keyMap.Down) || key.Matches(msg, m.keyMap.Up):
 		return m.inputFocusChange(msg)
+	case lo.Contains([]int{inputFile, inputJumpHosts, inputRequestTTY}, m.focusedInput):
+		// The file can only be selected from the list of inventories, jump hosts from the list of hosts,
+		// and tty mode from the list of RequestTTY values.
+		return nil
 	default:
 		// Handle all other key events
 		cmd := m.focusedInputProcessKeyEvent(msg)
@@ -333,9 +436,25 @@ func (m *EditModel) save(_ tea.Msg) tea.Cmd {
 		}
 	}
 
+	if m.host.StorageType == constant.HostStorageType.SSHConfig {
+		// Validate the host before closing the form, otherwise user loses all changes.
+		if err := sshConfigStorage.ValidateHost(m.host.unwrap()); err != nil {
+			m.logger.Info("[UI] Cannot save host with id %v. Reason: %s", m.host.ID, err.Error())
+			m.title = err.Error()
+
+			return nil
+		}
+	}
+
 	var cmd tea.Cmd
 	host, err := m.hostStorage.Save(m.host.unwrap())
-	if err != nil {
+	if errors.Is(err, storage.ErrConflict) {
+		// Keep the form open, otherwise user loses all changes.
+		m.logger.Info("[UI] Cannot save host with id %v. Reason: %s", m.host.ID, err.Error())
+		m.title = err.Error()
+
+		return nil
+	} else if err != nil {
 		m.logger.Error("[UI] Cannot save host with id %v. Reason: %s", m.host.ID, err.Error())
 		cmd = message.TeaCmd(message.ErrorOccurred{Err: err})
 	} else {
@@ -425,6 +544,19 @@ func (m *EditModel) focusedInputProcessKeyEvent(msg tea.Msg) tea.Cmd {
 		}
 	}
 
+	if m.focusedInput == inputTags {
+		m.updateTagSuggestions()
+	}
+
+	// SSH options, such as "User=deploy", change the effective config, which is displayed in placeholders.
+	if m.focusedInput == inputSSHOptions && previousValue != m.inputs[inputSSHOptions].Value() &&
+		m.inputs[inputSSHOptions].Validate(m.inputs[inputSSHOptions].Value()) == nil {
+		cmd = message.TeaCmd(debouncedMessage{
+			wrappedMsg:  message.RunProcessSSHLoadConfig{Host: *m.host.Host},
+			debounceTag: m.debounceTag,
+		})
+	}
+
 	return cmd
 }
 
@@ -437,7 +569,7 @@ func (m *EditModel) updateViewPort(msg tea.Msg) {
 		m.viewport = viewport.New(
 			viewport.WithWidth(m.appState.Width),
 			viewport.WithHeight(m.appState.Height-headerHeight-helpMenuHeight))
-		m.viewport.SetContent(m.inputsView())
+		m.viewport.SetContent(m.contentView())
 	} else if resizeMsg, ok := msg.(tea.WindowSizeMsg); ok {
 		m.viewport.SetWidth(resizeMsg.Width)
 		m.viewport.SetHeight(resizeMsg.Height - headerHeight - helpMenuHeight)
@@ -449,37 +581,35 @@ func (m *EditModel) inputFocusChange(msg tea.Msg) tea.Cmd {
 	var cmds []tea.Cmd
 	keyMsg, _ := msg.(tea.KeyPressMsg)
 
-	enabledInputs := lo.Filter(m.inputs, func(i input.Input, _ int) bool {
-		return i.Enabled()
-	})
-
-	minFocusIndex := 0
-	// maxFocusIndex is equal to number of inputs minus the number
-	// of disabled inputs. This works based on an assumption that
-	// all disabled inputs will be in the bottom of the hostlist.
-	maxFocusIndex := len(enabledInputs) - 1
 	inputHeight := 0
-
-	if len(m.inputs) > 0 {
+	if visibleInputs := m.visibleInputs(); visibleInputs > 0 {
 		// Control viewport manually because height of input element is greater than one
 		// therefore, we need to scroll several lines at once instead of just a single line.
 		// Normally we don't need to handle scroll events, other than forward app messages to
 		// the viewport: m.viewport, cmd = m.viewport.Update(msg)
-		inputHeight = lipgloss.Height(m.inputsView()) / len(m.inputs)
+		inputHeight = lipgloss.Height(m.inputsView()) / visibleInputs
 	}
 
-	// Update index of the focused element
-	if key.Matches(keyMsg, m.keyMap.Up) && m.focusedInput > minFocusIndex { //nolint:gocritic // readable without switch
-		m.focusedInput--
-		m.viewport.ScrollUp(inputHeight)
-	} else if key.Matches(keyMsg, m.keyMap.Down) && m.focusedInput < maxFocusIndex {
-		m.focusedInput++
-		m.viewport.ScrollDown(inputHeight)
-	} else {
+	step := lo.Ternary(key.Matches(keyMsg, m.keyMap.Up), -1, 1)
+	// Disabled inputs cannot be focused, skip them.
+	nextFocusIndex := m.focusedInput + step
+	for nextFocusIndex >= 0 && nextFocusIndex < len(m.inputs) && !m.inputs[nextFocusIndex].Enabled() {
+		nextFocusIndex += step
+	}
+
+	if nextFocusIndex < 0 || nextFocusIndex >= len(m.inputs) {
 		m.logger.Debug("[UI] Reached first or last selectable input field: %d", m.focusedInput)
 		return nil
 	}
 
+	// Update index of the focused element
+	if step < 0 {
+		m.viewport.ScrollUp(inputHeight * (m.focusedInput - nextFocusIndex))
+	} else {
+		m.viewport.ScrollDown(inputHeight * (nextFocusIndex - m.focusedInput))
+	}
+	m.focusedInput = nextFocusIndex
+
 	// Should be extracted to "Validate" function
 	for i := range m.inputs {
 		if m.inputs[i].Validate != nil {
@@ -504,6 +634,221 @@ func (m *EditModel) inputFocusChange(msg tea.Msg) tea.Cmd {
 	return tea.Batch(cmds...)
 }
 
+// selectNextInventory - moves the host to the previous or to the next host file from the list.
+func (m *EditModel) selectNextInventory(msg tea.KeyPressMsg) {
+	if len(m.inventories) == 0 {
+		return
+	}
+
+	step := lo.Ternary(msg.String() == "left", -1, 1)
+	index := slices.Index(m.inventories, m.host.SourcePath)
+	index = (index + step + len(m.inventories)) % len(m.inventories)
+	m.inputs[inputFile].SetValue(m.inventories[index])
+	m.host.SourcePath = m.inventories[index]
+	m.logger.Debug("[UI] Select host file: %q", m.host.SourcePath)
+}
+
+// selectNextTTYMode - changes RequestTTY setting to the previous or to the next value. Empty value is ssh default.
+func (m *EditModel) selectNextTTYMode(msg tea.KeyPressMsg) {
+	modes := append([]string{""}, hostModel.RequestTTYModes...)
+	step := lo.Ternary(msg.String() == "left", -1, 1)
+	index := max(slices.Index(modes, m.host.RequestTTY), 0)
+	index = (index + step + len(modes)) % len(modes)
+	m.inputs[inputRequestTTY].SetValue(modes[index])
+	m.host.RequestTTY = modes[index]
+	m.logger.Debug("[UI] Select tty mode: %q", m.host.RequestTTY)
+}
+
+// tagCompletionKey - accepts the tag, which is suggested by the tags input. Tab key cannot be used for that,
+// it moves focus to the next input.
+var tagCompletionKey = key.NewBinding(key.WithKeys("right"))
+
+// tagCandidates - returns tags of all hosts, which are suggested when user types a tag. The tags are loaded
+// once, when the user types a tag for the first time.
+func (m *EditModel) tagCandidates() []string {
+	if m.knownTags != nil {
+		return m.knownTags
+	}
+
+	hosts, err := m.hostStorage.GetAll()
+	if err != nil {
+		m.logger.Error("[UI] Cannot load tags. %v", err)
+		return nil
+	}
+
+	m.knownTags = hostModel.AllTags(hosts)
+	return m.knownTags
+}
+
+// updateTagSuggestions - suggests tags, which start with the last typed word and are not added to the host yet.
+// Suggestions contain the whole input value, because the input completes the value, not a single word.
+func (m *EditModel) updateTagSuggestions() {
+	value := m.inputs[inputTags].Value()
+	wordStart := strings.LastIndexAny(value, tagSeparators) + 1
+	prefix, word := value[:wordStart], value[wordStart:]
+	if word == "" {
+		m.inputs[inputTags].SetSuggestions(nil)
+		return
+	}
+
+	added := parseTags(prefix)
+	suggestions := lo.FilterMap(m.tagCandidates(), func(tag string, _ int) (string, bool) {
+		return prefix + tag, !containsTag(added, tag)
+	})
+	m.inputs[inputTags].SetSuggestions(suggestions)
+}
+
+// isYAMLHost - returns true if the host is stored in a YAML file, or it's a new host.
+func (m *EditModel) isYAMLHost() bool {
+	return lo.Contains([]constant.HostStorageEnum{
+		"", // New host
+		constant.HostStorageType.YAMLFile,
+		constant.HostStorageType.EncryptedYAMLFile,
+	}, m.host.StorageType)
+}
+
+// isInventorySelectable - returns true if the host can be moved to another file.
+func (m *EditModel) isInventorySelectable() bool {
+	return m.isYAMLHost() && len(m.inventories) > 1
+}
+
+// isJumpHostSelectable - returns true if jump hosts can be selected for the host. Hosts from ssh_config
+// declare jump hosts with ProxyJump option, and custom ssh commands contain all options in the command.
+func (m *EditModel) isJumpHostSelectable() bool {
+	return m.isYAMLHost() && !m.host.IsUserDefinedSSHCommand()
+}
+
+// jumpHostCandidates - returns hosts, which can be selected as jump hosts, sorted by title. The hosts are
+// loaded once, when the user selects a jump host for the first time.
+func (m *EditModel) jumpHostCandidates() []hostModel.Host {
+	if m.jumpHosts != nil {
+		return m.jumpHosts
+	}
+
+	hosts, err := m.hostStorage.GetAll()
+	if err != nil {
+		m.logger.Error("[UI] Cannot load jump hosts. %v", err)
+		return nil
+	}
+
+	// The host itself and custom ssh commands cannot be jump hosts.
+	m.jumpHosts = lo.Filter(hosts, func(h hostModel.Host, _ int) bool {
+		return (m.isNewHost || h.ID != m.host.ID) && !h.IsUserDefinedSSHCommand()
+	})
+	slices.SortFunc(m.jumpHosts, func(a, b hostModel.Host) int {
+		return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	})
+
+	return m.jumpHosts
+}
+
+// selectNextJumpHost - replaces the last jump host with the previous or the next host from the list.
+// Hosts, which are already in the chain, are skipped. If the chain is empty, the first jump host is added.
+func (m *EditModel) selectNextJumpHost(msg tea.KeyPressMsg) {
+	candidates := m.jumpHostCandidates()
+	if len(candidates) == 0 {
+		return
+	}
+
+	chain := m.host.ProxyJump
+	last := len(chain) - 1
+	step := lo.Ternary(msg.String() == "left", -1, 1)
+	index := -1
+	if last >= 0 {
+		index = slices.IndexFunc(candidates, func(h hostModel.Host) bool { return h.ID == chain[last] })
+	}
+
+	for range candidates {
+		if index < 0 {
+			index = lo.Ternary(step > 0, 0, len(candidates)-1)
+		} else {
+			index = (index + step + len(candidates)) % len(candidates)
+		}
+
+		if last < 0 || !slices.Contains(chain[:last], candidates[index].ID) {
+			break
+		}
+	}
+
+	if last < 0 {
+		m.host.ProxyJump = []string{candidates[index].ID}
+	} else {
+		m.host.ProxyJump = append(slices.Clone(chain[:last]), candidates[index].ID)
+	}
+
+	m.logger.Debug("[UI] Select jump host: %q", candidates[index].Title)
+	m.updateJumpHostsInput()
+}
+
+// addJumpHost - appends the first host, which is not in the chain yet.
+func (m *EditModel) addJumpHost() {
+	candidate, found := lo.Find(m.jumpHostCandidates(), func(h hostModel.Host) bool {
+		return !slices.Contains(m.host.ProxyJump, h.ID)
+	})
+
+	if !found {
+		m.logger.Debug("[UI] No more hosts, which can be added as jump hosts")
+		return
+	}
+
+	m.host.ProxyJump = append(slices.Clone(m.host.ProxyJump), candidate.ID)
+	m.logger.Debug("[UI] Add jump host: %q", candidate.Title)
+	m.updateJumpHostsInput()
+}
+
+// removeJumpHost - removes the last jump host from the chain, including the ones which are deleted.
+func (m *EditModel) removeJumpHost() {
+	if len(m.host.ProxyJump) == 0 {
+		return
+	}
+
+	m.host.ProxyJump = slices.Clone(m.host.ProxyJump[:len(m.host.ProxyJump)-1])
+	m.logger.Debug("[UI] Remove last jump host")
+	m.updateJumpHostsInput()
+}
+
+func (m *EditModel) updateJumpHostsInput() {
+	m.inputs[inputJumpHosts].SetValue(m.jumpHostsValue())
+	m.inputs[inputJumpHosts].Err = m.validateJumpHosts("")
+	if m.inputs[inputJumpHosts].Err != nil {
+		m.title = m.inputs[inputJumpHosts].Err.Error()
+	}
+}
+
+// jumpHostsValue - returns titles of the jump hosts in the order ssh connects to them.
+func (m *EditModel) jumpHostsValue() string {
+	titles := make([]string, 0, len(m.host.ProxyJump))
+	for _, hostID := range m.host.ProxyJump {
+		jumpHost, err := m.hostStorage.Get(hostID)
+		if err != nil {
+			titles = append(titles, fmt.Sprintf("<deleted host %s>", hostID))
+			continue
+		}
+
+		titles = append(titles, jumpHost.Title)
+	}
+
+	return strings.Join(titles, " → ")
+}
+
+// validateJumpHosts - returns an error if the jump hosts are deleted, or if they refer to each other.
+// The value of the input is not used, because it only contains titles of the hosts.
+func (m *EditModel) validateJumpHosts(_ string) error {
+	host := m.host.unwrap()
+	return host.ResolveJumpChain(func(hostID string) (hostModel.Host, error) {
+		if !m.isNewHost && hostID == host.ID {
+			// The storage keeps the previous version of the host, which can have other jump hosts.
+			return host, nil
+		}
+
+		return m.hostStorage.Get(hostID)
+	})
+}
+
+func (m *EditModel) visibleInputs() int {
+	return lo.Ternary(m.isInventorySelectable(), len(m.inputs), len(m.inputs)-1)
+}
+
 func (m *EditModel) handleCopyInputValueShortcut() {
 	// Allow a user to copy values between address and title,
 	// because the chances are that these two inputs will have
@@ -545,6 +890,7 @@ func (m *EditModel) handleEditableHost() {
 	m.inputs[inputAddress].Placeholder = "*required*"
 	m.inputs[inputGroup].Placeholder = "n/a"
 	m.inputs[inputDescription].Placeholder = "n/a"
+	m.inputs[inputTags].Placeholder = "e.g. prod, db"
 	m.inputs[inputLogin].Placeholder = fmt.Sprintf("%s: %s", prefix, m.host.SSHHostConfig.User)
 	m.inputs[inputNetworkPort].Placeholder = fmt.Sprintf("%s: %s", prefix, m.host.SSHHostConfig.Port)
 	m.inputs[inputIdentityFile].Placeholder = fmt.Sprintf("%s: %s", prefix, m.host.SSHHostConfig.IdentityFile)
@@ -566,11 +912,39 @@ func (m *EditModel) handleEditableHost() {
 		i.SetEnabled(!customConnectString)
 	})
 
+	m.inputs[inputFile].SetEnabled(m.isInventorySelectable())
+	m.inputs[inputJumpHosts].SetEnabled(m.isJumpHostSelectable())
+	proxyJump := m.host.SSHHostConfig.Value("proxyjump")
+	m.inputs[inputJumpHosts].Placeholder = fmt.Sprintf("%s: %s",
+		lo.Ternary(m.isJumpHostSelectable(), "default", "readonly"),
+		lo.Ternary(utils.StringEmpty(&proxyJump), "none", proxyJump))
+	// Hosts from ssh_config declare options in the host block, and custom ssh commands contain them in the command.
+	m.inputs[inputSSHOptions].SetEnabled(m.isJumpHostSelectable())
+	m.inputs[inputSSHOptions].Placeholder = lo.Ternary(m.isJumpHostSelectable(),
+		"e.g. -A -o ServerAliveInterval=30", "readonly: n/a")
+	// Hosts from ssh_config declare RemoteCommand and RequestTTY options in the host block.
+	remoteCommand := m.host.SSHHostConfig.Value("remotecommand")
+	m.inputs[inputRemoteCommand].SetEnabled(m.isYAMLHost())
+	m.inputs[inputRemoteCommand].Placeholder = fmt.Sprintf("%s: %s",
+		lo.Ternary(m.isYAMLHost(), "default", "readonly"),
+		lo.Ternary(utils.StringEmpty(&remoteCommand), "login shell", remoteCommand))
+	m.inputs[inputRequestTTY].SetEnabled(m.isYAMLHost())
+	requestTTY := m.host.SSHHostConfig.Value("requesttty")
+	m.inputs[inputRequestTTY].Placeholder = lo.Ternary(m.isYAMLHost(),
+		"default: yes for remote command, otherwise auto",
+		"readonly: "+lo.Ternary(utils.StringEmpty(&requestTTY), "auto", requestTTY))
+	// Hosts from ssh_config declare port forwardings with LocalForward, RemoteForward and DynamicForward options.
+	m.inputs[inputForwards].SetEnabled(m.isYAMLHost())
+	m.inputs[inputForwards].Placeholder = lo.Ternary(m.isYAMLHost(), "e.g. db L 5432:localhost:5432, socks D 1080", "n/a")
+
 	lo.ForEach(m.inputs, func(_ input.Input, n int) {
-		if m.inputs[n].Enabled() {
-			m.inputs[n].SetValue(m.host.getHostAttributeValueByIndex(n))
-		} else {
+		switch {
+		case !m.inputs[n].Enabled():
 			m.inputs[n].SetValue("")
+		case n == inputJumpHosts:
+			m.inputs[n].SetValue(m.jumpHostsValue())
+		default:
+			m.inputs[n].SetValue(m.host.getHostAttributeValueByIndex(n))
 		}
 	})
 }
@@ -578,6 +952,10 @@ func (m *EditModel) handleEditableHost() {
 func (m *EditModel) inputsView() string {
 	var b strings.Builder
 	for i := range m.inputs {
+		if i == inputFile && !m.isInventorySelectable() {
+			continue
+		}
+
 		b.WriteString(m.inputs[i].View().Content)
 		if i < len(m.inputs) {
 			b.WriteString("\n\n")
@@ -587,6 +965,95 @@ func (m *EditModel) inputsView() string {
 	return m.styles.componentMargins.Render(b.String())
 }
 
+// editableDirectives are ssh_config directives, which can be changed in the form.
+var editableDirectives = []string{"hostname", "user", "port", "identityfile"}
+
+// directivesView - displays options from ssh_config host block. Readonly hosts display all of them,
+// editable hosts only display the ones which cannot be changed in the form.
+func (m *EditModel) directivesView() string {
+	directives := m.host.Directives
+	if !m.host.IsReadOnly() {
+		directives = lo.Reject(directives, func(d hostModel.Directive, _ int) bool {
+			return slices.Contains(editableDirectives, strings.ToLower(d.Keyword))
+		})
+	}
+
+	if len(directives) == 0 {
+		return ""
+	}
+
+	indent := strings.Repeat(" ", lipgloss.Width(m.inputs[inputTitle].FocusedPrompt))
+	lines := []string{indent + "SSH Config Options"}
+	for _, d := range directives {
+		lines = append(lines, fmt.Sprintf("%s%s %s", indent, d.Keyword, d.Value))
+	}
+
+	return m.styles.textReadonly.Render(strings.Join(lines, "\n"))
+}
+
+// inheritedView - displays options, which the host inherits from wildcard "Host" blocks and global
+// options, and where they're declared. The options cannot be changed in the form.
+func (m *EditModel) inheritedView() string {
+	if len(m.host.Inherited) == 0 {
+		return ""
+	}
+
+	indent := strings.Repeat(" ", lipgloss.Width(m.inputs[inputTitle].FocusedPrompt))
+	lines := []string{indent + "Inherited Options"}
+	if directives := m.directivesView(); !utils.StringEmpty(&directives) {
+		// Separate the section from the host options.
+		lines = append([]string{""}, lines...)
+	}
+
+	for _, d := range m.host.Inherited {
+		block := "global options"
+		if !utils.StringEmpty(&d.Pattern) {
+			block = "Host " + d.Pattern
+		}
+
+		line := fmt.Sprintf("%s%s %s (from %s at %s:%d)",
+			indent, d.Keyword, d.Value, block, utils.RedactURL(d.SourcePath), d.Line)
+		if d.Overrides {
+			line += ", overrides host value"
+		}
+
+		lines = append(lines, line)
+	}
+
+	return m.styles.textReadonly.Render(strings.Join(lines, "\n"))
+}
+
+// metadataView - displays the note, which is read from '# GG:NOTE' metadata in ssh_config.
+// It cannot be changed in the form.
+func (m *EditModel) metadataView() string {
+	indent := strings.Repeat(" ", lipgloss.Width(m.inputs[inputTitle].FocusedPrompt))
+	lines := []string{}
+
+	if !utils.StringEmpty(&m.host.Note) {
+		lines = append(lines, indent+"Note")
+		for _, line := range strings.Split(m.host.Note, "\n") {
+			lines = append(lines, indent+line)
+		}
+		lines = append(lines, "")
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return m.styles.textReadonly.Render(strings.Join(lines, "\n"))
+}
+
+// contentView - returns the content of the viewport. Metadata, directives and inherited options are displayed
+// below the inputs, they're not a part of inputsView, because it's used to calculate the height of a single input.
+func (m *EditModel) contentView() string {
+	sections := []string{m.inputsView(), m.metadataView(), m.directivesView(), m.inheritedView()}
+	sections = lo.Filter(sections,
+		func(section string, _ int) bool { return !utils.StringEmpty(&section) })
+
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
 func (m *EditModel) headerView() string {
 	return m.styles.title.Render(m.title)
 }
@@ -16,9 +16,11 @@ import (
 	model "github.com/grafviktor/goto/internal/model/host"
 	"github.com/grafviktor/goto/internal/model/sshconfig"
 	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/storage"
 	testutils "github.com/grafviktor/goto/internal/testutils"
 	"github.com/grafviktor/goto/internal/testutils/mocklogger"
 	"github.com/grafviktor/goto/internal/ui/message"
+	"github.com/grafviktor/goto/internal/utils"
 )
 
 func TestNotEmptyValidator(t *testing.T) {
@@ -43,6 +45,33 @@ func TestNotEmptyValidator(t *testing.T) {
 	}
 }
 
+func TestHostAddressValidator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected error
+	}{
+		{"", errors.New("value is required")},
+		{"example.com", nil},
+		{"192.168.0.1", nil},
+		{"2001:db8::10", nil},
+		{"[2001:db8::10]", nil},
+		{"fe80::1%eth0", nil},
+		// Custom ssh command.
+		{"root@[2001:db8::10] -p 2222", nil},
+		{"2001:db8::10::1", errors.New("host must be a host name, IPv4 or IPv6 address")},
+		{"exampl$.com", errors.New("host must be a host name, IPv4 or IPv6 address")},
+	}
+
+	for _, test := range tests {
+		result := hostAddressValidator(test.input)
+		if test.expected == nil {
+			require.NoError(t, result, test.input)
+		} else {
+			require.EqualError(t, result, test.expected.Error(), test.input)
+		}
+	}
+}
+
 func TestNetworkPortValidator(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -83,6 +112,7 @@ func TestGetKeyMap(t *testing.T) {
 	// If host was loaded from read-only storage, then all hotkeys apart from 'Discard'
 	// should be disabled independently from which input is selected.
 	host.StorageType = constant.HostStorageType.SSHConfig
+	host.SourcePath = "https://example.com/ssh_config"
 	for i := range 7 {
 		underTest = getKeyMap(host, i)
 		require.False(t, underTest.Up.Enabled(), "Input %s shoud be disabled", underTest.Up)
@@ -118,7 +148,27 @@ func TestSave(t *testing.T) {
 	var dst []tea.Msg
 	testutils.CmdToMessage(messageSequence, &dst)
 	require.Contains(t, dst, message.ViewHostEditClose{})
-	require.Contains(t, dst, message.HostSelect{HostID: 0})
+	require.Contains(t, dst, message.HostSelect{HostID: ""})
+}
+
+// conflictStorage - fails to save hosts, as if they were modified by another process.
+type conflictStorage struct {
+	*testutils.MockStorage
+}
+
+func (cs conflictStorage) Save(h model.Host) (model.Host, error) {
+	return h, fmt.Errorf("%w, cannot save host %q", storage.ErrConflict, h.Title)
+}
+
+func TestSave_Conflict(t *testing.T) {
+	st := conflictStorage{testutils.NewMockStorage(false)}
+	hostEditModel := New(existingHostContext(), st, MockAppState(), &mocklogger.Logger{})
+	hostEditModel.inputs[inputDescription].SetValue("changed")
+
+	// The form must stay open, so that the changes are not lost.
+	require.Nil(t, hostEditModel.save(nil))
+	require.Contains(t, hostEditModel.title, "modified by another process")
+	require.Equal(t, "changed", hostEditModel.inputs[inputDescription].Value())
 }
 
 func TestCopyInputValueFromTo(t *testing.T) {
@@ -168,7 +218,7 @@ func TestHandleCopyInputValueShortcut(t *testing.T) {
 	// in the storage. Otherwise, everything what we type in title will automatically be
 	// propagated to address field.
 	storageShouldFail := false
-	model := New(context.TODO(), testutils.NewMockStorage(storageShouldFail), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(storageShouldFail), MockAppState(), &mocklogger.Logger{})
 	// Override mock values which we received from mock database and set model values to 'test'
 	model.host.Title = "test"
 	model.host.Address = "test"
@@ -213,13 +263,13 @@ func TestHandleCopyInputValueShortcut(t *testing.T) {
 func TestUpdate_HostSSHConfigLoaded(t *testing.T) {
 	// Test that when the model receives HostSSHConfigLoaded message,
 	// the input placeholders are updated with the values from the SSH config.
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	require.NotEqual(t, "default: Mock Identity File", model.inputs[inputIdentityFile].Placeholder)
 	require.NotEqual(t, "default: Mock User", model.inputs[inputLogin].Placeholder)
 	require.NotEqual(t, "default: Mock Port", model.inputs[inputNetworkPort].Placeholder)
 
 	model.Update(message.HostSSHConfigLoadComplete{
-		HostID: 0,
+		HostID: "0",
 		Config: sshconfig.Config{
 			IdentityFile: "Mock Identity File",
 			User:         "Mock User",
@@ -235,7 +285,7 @@ func TestUpdate_HostSSHConfigLoaded(t *testing.T) {
 func TestUpdate_HideUINotification(t *testing.T) {
 	// Test display notification message show and hide functionality
 	uiComponentName := "hostedit"
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	cmd := message.DisplayNotification(uiComponentName, "Test notification message", model)
 	require.Equal(t, "Test notification message", model.title)
 
@@ -259,7 +309,7 @@ func TestUpdate_HideUINotification(t *testing.T) {
 func TestView(t *testing.T) {
 	// Test that by calling View() function first time, we set ready flag to true
 	// and view() returns non-empty string which will be used to build terminal user interface
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	assert.False(t, model.ready)
 	ui := model.View()
 
@@ -269,19 +319,19 @@ func TestView(t *testing.T) {
 
 func TestHelpView(t *testing.T) {
 	// Test that help view is not empty
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	require.NotEmpty(t, model.helpView())
 }
 
 func TestHeaderView(t *testing.T) {
 	// Test that header view is not empty
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	require.NotEmpty(t, model.headerView())
 }
 
 func TestHandleDebounceMessage(t *testing.T) {
 	// Test that only last message is executed when wrap message in the debounce container
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	_, returned1 := model.Update(debouncedMessage{
 		wrappedMsg:  struct{}{},
 		debounceTag: 0,
@@ -309,7 +359,7 @@ func TestHandleDebounceMessage(t *testing.T) {
 func TestUpdateInputPlaceHolders(t *testing.T) {
 	// Make sure that placeholders have correct values once ssh config is changed.
 	appState := MockAppState()
-	model := New(context.TODO(), testutils.NewMockStorage(false), appState, &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), appState, &mocklogger.Logger{})
 	model.host.SSHHostConfig = &sshconfig.Config{
 		IdentityFile: "Mock Identity File",
 		User:         "Mock User",
@@ -363,7 +413,7 @@ func TestUpdateInputPlaceHolders(t *testing.T) {
 
 func TestUpdate_KeyDiscard(t *testing.T) {
 	// When press escape, should receive close form cmd
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	_, cmd := model.Update(tea.KeyPressMsg{
 		Code: tea.KeyEscape,
 	})
@@ -373,7 +423,7 @@ func TestUpdate_KeyDiscard(t *testing.T) {
 
 func TestUpdate_KeySave(t *testing.T) {
 	// When press escape, should receive close form cmd
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	_, cmd := model.Update(tea.KeyPressMsg{
 		Code: 's',
 		Mod:  tea.ModCtrl,
@@ -403,7 +453,8 @@ func TestUpdate_KeyEventWhenHostIsReadOnly(t *testing.T) {
 	storage := testutils.NewMockStorage(false)
 	// simulate that we have a host which is read-only
 	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
-	model := New(context.TODO(), storage, MockAppState(), &mocklogger.Logger{})
+	storage.Hosts[0].SourcePath = "https://example.com/ssh_config"
+	model := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
 
 	_, cmd := model.Update(tea.KeyPressMsg{
 		// Save host shortcut
@@ -412,7 +463,7 @@ func TestUpdate_KeyEventWhenHostIsReadOnly(t *testing.T) {
 	})
 
 	// When host is read-only, the title should display a warning message
-	require.Equal(t, "host loaded from SSH config is readonly", model.title)
+	require.Equal(t, "host loaded from remote SSH config is readonly", model.title)
 
 	done := make(chan tea.Msg, 1)
 	go func() {
@@ -430,7 +481,7 @@ func TestUpdate_KeyEventWhenHostIsReadOnly(t *testing.T) {
 }
 
 func TestDisplayNotificationMsg(t *testing.T) {
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	model := New(existingHostContext(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	cmd := model.displayNotificationMsg("")
 	require.Nil(t, cmd)
 }
@@ -438,3 +489,392 @@ func TestDisplayNotificationMsg(t *testing.T) {
 func MockAppState() *state.State {
 	return &state.State{}
 }
+
+// existingHostContext - returns context which makes edit form to load the first host from mock storage.
+func existingHostContext() context.Context {
+	return context.WithValue(context.TODO(), ItemID, "1")
+}
+
+type mockInventoryStorage struct {
+	*testutils.MockStorage
+	inventories []string
+}
+
+func (s *mockInventoryStorage) Inventories() []string {
+	return s.inventories
+}
+
+func TestSelectInventory(t *testing.T) {
+	storage := &mockInventoryStorage{
+		MockStorage: testutils.NewMockStorage(false),
+		inventories: []string{"/home/user/hosts.yaml", "/team/hosts.yaml"},
+	}
+
+	// New hosts are saved to the default file.
+	model := New(context.TODO(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Equal(t, "/home/user/hosts.yaml", model.host.SourcePath)
+	require.True(t, model.inputs[inputFile].Enabled())
+	require.Contains(t, utils.StripStyles(model.inputsView()), "\n  File")
+	require.Contains(t, model.inputsView(), "/home/user/hosts.yaml")
+
+	// The file input is the last one, it can be focused even if ssh parameters are disabled.
+	model.inputs[inputAddress].SetValue("ssh -p 2222 localhost")
+	model.host.Address = "ssh -p 2222 localhost"
+	model.updateInputFields()
+	model.focusedInput = inputTags
+	model.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputRemoteCommand, model.focusedInput)
+	model.focusedInput = inputRequestTTY
+	model.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputForwards, model.focusedInput)
+	model.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputFile, model.focusedInput)
+
+	// Typing is ignored, the file can only be selected from the list.
+	model.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	require.Equal(t, "/home/user/hosts.yaml", model.inputs[inputFile].Value())
+
+	model.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	require.Equal(t, "/team/hosts.yaml", model.host.SourcePath)
+	require.Equal(t, "/team/hosts.yaml", model.inputs[inputFile].Value())
+	model.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	require.Equal(t, "/home/user/hosts.yaml", model.host.SourcePath)
+	model.Update(tea.KeyPressMsg{Code: tea.KeyLeft})
+	require.Equal(t, "/team/hosts.yaml", model.host.SourcePath)
+
+	// Hosts from ssh_config cannot be moved to a YAML file.
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	model = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.False(t, model.inputs[inputFile].Enabled())
+	require.NotContains(t, utils.StripStyles(model.inputsView()), "\n  File")
+	require.Contains(t, utils.StripStyles(model.inputsView()), "\n  Identity File")
+}
+
+func TestSelectJumpHosts(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	editModel.focusedInput = inputIdentityFile
+	editModel.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputJumpHosts, editModel.focusedInput)
+	require.True(t, editModel.keyMap.AddJumpHost.Enabled())
+
+	// The host itself cannot be a jump host, typing is ignored.
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	editModel.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	require.Equal(t, []string{"2"}, editModel.host.ProxyJump)
+	require.Equal(t, "Mock Host 2", editModel.inputs[inputJumpHosts].Value())
+
+	// Hosts, which are already in the chain, are skipped.
+	editModel.Update(tea.KeyPressMsg{Code: '+', Text: "+"})
+	require.Equal(t, []string{"2", "3"}, editModel.host.ProxyJump)
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyLeft})
+	require.Equal(t, []string{"2", "3"}, editModel.host.ProxyJump)
+	require.Equal(t, "Mock Host 2 → Mock Host 3", editModel.inputs[inputJumpHosts].Value())
+
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	require.Equal(t, []string{"2"}, editModel.host.ProxyJump)
+
+	// Jump hosts must not refer to the host.
+	storage.Hosts[1].ProxyJump = []string{"1"}
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	require.Equal(t, []string{"3"}, editModel.host.ProxyJump)
+	require.NoError(t, editModel.inputs[inputJumpHosts].Err)
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	require.ErrorIs(t, editModel.inputs[inputJumpHosts].Err, model.ErrJumpHostCycle)
+
+	// Deleted jump hosts are displayed, so that they can be removed.
+	editModel.host.ProxyJump = []string{"deleted"}
+	editModel.updateJumpHostsInput()
+	require.Equal(t, "<deleted host deleted>", editModel.inputs[inputJumpHosts].Value())
+	require.ErrorIs(t, editModel.inputs[inputJumpHosts].Err, model.ErrJumpHostNotFound)
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyDelete})
+	require.Empty(t, editModel.host.ProxyJump)
+	require.NoError(t, editModel.inputs[inputJumpHosts].Err)
+
+	// Hosts from ssh_config declare jump hosts with ProxyJump option.
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.False(t, editModel.inputs[inputJumpHosts].Enabled())
+	require.Equal(t, "readonly: none", editModel.inputs[inputJumpHosts].Placeholder)
+}
+
+func Test_directivesView(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	storage.Hosts[0].SourcePath = "/home/user/.ssh/config"
+	storage.Hosts[0].Directives = []model.Directive{
+		{Keyword: "HostName", Value: "10.0.0.1"},
+		{Keyword: "ProxyJump", Value: "bastion"},
+		{Keyword: "LocalForward", Value: "8080 localhost:80"},
+	}
+
+	// Editable host only displays directives, which cannot be changed in the form.
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	view := utils.StripStyles(editModel.directivesView())
+	require.Contains(t, view, "ProxyJump bastion")
+	require.Contains(t, view, "LocalForward 8080 localhost:80")
+	require.NotContains(t, view, "HostName")
+	require.Contains(t, utils.StripStyles(editModel.contentView()), "ProxyJump bastion")
+
+	// Readonly host displays all directives.
+	storage.Hosts[0].SourcePath = "https://example.com/ssh_config"
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Contains(t, utils.StripStyles(editModel.directivesView()), "HostName 10.0.0.1")
+
+	// Hosts without directives do not display the section.
+	storage.Hosts[0].Directives = nil
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Empty(t, editModel.directivesView())
+}
+
+func Test_inheritedView(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	storage.Hosts[0].SourcePath = "/home/user/.ssh/config"
+
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Empty(t, editModel.inheritedView())
+
+	storage.Hosts[0].Inherited = []model.InheritedDirective{
+		{
+			Directive:  model.Directive{Keyword: "ServerAliveInterval", Value: "60"},
+			SourcePath: "/home/user/.ssh/config",
+			Line:       1,
+		},
+		{
+			Directive:  model.Directive{Keyword: "User", Value: "deploy"},
+			Pattern:    "*.prod !db.prod",
+			SourcePath: "/home/user/.ssh/config",
+			Line:       12,
+			Overrides:  true,
+		},
+	}
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	view := utils.StripStyles(editModel.contentView())
+	require.Contains(t, view, "Inherited Options")
+	require.Contains(t, view, "ServerAliveInterval 60 (from global options at /home/user/.ssh/config:1)")
+	require.Contains(t, view,
+		"User deploy (from Host *.prod !db.prod at /home/user/.ssh/config:12), overrides host value")
+}
+
+func Test_metadataView(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	storage.Hosts[0].SourcePath = "/home/user/.ssh/config"
+
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Empty(t, editModel.metadataView())
+
+	storage.Hosts[0].Tags = []string{"prod", "db"}
+	storage.Hosts[0].Note = "Primary database.\nRestart only on weekends."
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	view := utils.StripStyles(editModel.contentView())
+	// Tags are edited in the form, only the note is displayed below it.
+	require.NotContains(t, view, "Tags: prod, db")
+	require.Equal(t, "prod, db", editModel.inputs[inputTags].Value())
+	require.Contains(t, view, "Primary database.")
+	require.Contains(t, view, "Restart only on weekends.")
+}
+
+func TestParseForwards(t *testing.T) {
+	forwards, err := parseForwards("db L 5432:db.internal:5432, socks d 1080,")
+	require.NoError(t, err)
+	require.Equal(t, []model.Forward{
+		{Name: "db", Type: model.ForwardTypes.Local, Spec: "5432:db.internal:5432"},
+		{Name: "socks", Type: model.ForwardTypes.Dynamic, Spec: "1080"},
+	}, forwards)
+	require.Equal(t, "db L 5432:db.internal:5432, socks D 1080", formatForwards(forwards))
+
+	forwards, err = parseForwards("  ")
+	require.NoError(t, err)
+	require.Empty(t, forwards)
+
+	_, err = parseForwards("db 5432:db.internal:5432")
+	require.ErrorContains(t, err, "must be written as")
+	_, err = parseForwards("db X 5432:db.internal:5432")
+	require.ErrorContains(t, err, "unknown type")
+	_, err = parseForwards("db L 5432")
+	require.Error(t, err)
+	_, err = parseForwards("db L 5432:db:5432, db R 8080:localhost:80")
+	require.ErrorContains(t, err, "more than once")
+}
+
+func TestEditForwards(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].Forwards = []model.Forward{{Name: "db", Type: model.ForwardTypes.Local, Spec: "5432:db:5432"}}
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Equal(t, "db L 5432:db:5432", editModel.inputs[inputForwards].Value())
+
+	editModel.focusedInput = inputRequestTTY
+	editModel.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputForwards, editModel.focusedInput)
+
+	// Incomplete forwardings are kept in the input, but they're not saved.
+	for _, r := range ", socks D" {
+		editModel.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	editModel.updateInputFields()
+	require.Equal(t, "db L 5432:db:5432, socks D", editModel.inputs[inputForwards].Value())
+	require.Len(t, editModel.host.Forwards, 1)
+	require.Nil(t, editModel.save(nil))
+	require.Equal(t, "Port Forwards is not valid", editModel.title)
+
+	for _, r := range " 1080" {
+		editModel.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	require.Len(t, editModel.host.Forwards, 2)
+	var msgs []tea.Msg
+	testutils.CmdToMessage(editModel.save(nil), &msgs)
+	require.Contains(t, msgs, message.HostUpdate{Host: editModel.host.unwrap()})
+	require.Equal(t, "1080", editModel.host.Forwards[1].Spec)
+
+	// Hosts from ssh_config declare port forwardings with LocalForward option.
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.False(t, editModel.inputs[inputForwards].Enabled())
+}
+
+func TestParseSSHOptions(t *testing.T) {
+	options, err := parseSSHOptions(" -A  -o ServerAliveInterval=30 StrictHostKeyChecking=accept-new")
+	require.NoError(t, err)
+	require.Equal(t, []string{"-A", "ServerAliveInterval=30", "StrictHostKeyChecking=accept-new"}, options)
+	require.Equal(t, "-A ServerAliveInterval=30 StrictHostKeyChecking=accept-new", formatSSHOptions(options))
+
+	_, err = parseSSHOptions("-A -o")
+	require.ErrorContains(t, err, "must be followed by Keyword=value")
+	_, err = parseSSHOptions("-o -A")
+	require.ErrorContains(t, err, "must be followed by Keyword=value")
+	_, err = parseSSHOptions("-o UnknownOption=yes")
+	require.ErrorContains(t, err, "is unknown")
+}
+
+func TestEditSSHOptions(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].SSHOptions = []string{"-A"}
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Equal(t, "-A", editModel.inputs[inputSSHOptions].Value())
+
+	editModel.focusedInput = inputJumpHosts
+	editModel.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputSSHOptions, editModel.focusedInput)
+
+	// Effective config is loaded again, when the options change.
+	var cmd tea.Cmd
+	for _, r := range " User=deploy" {
+		_, cmd = editModel.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	require.Equal(t, []string{"-A", "User=deploy"}, editModel.host.SSHOptions)
+	require.IsType(t, debouncedMessage{}, cmd())
+
+	// Unknown options are kept in the input, but they're not saved.
+	for _, r := range " Usr=root" {
+		editModel.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	require.Equal(t, []string{"-A", "User=deploy"}, editModel.host.SSHOptions)
+	require.Nil(t, editModel.save(nil))
+	require.Equal(t, "SSH Options is not valid", editModel.title)
+
+	// Custom ssh commands contain all options in the command.
+	editModel.host.Address = "root@localhost -A"
+	editModel.updateInputFields()
+	require.False(t, editModel.inputs[inputSSHOptions].Enabled())
+	require.Equal(t, "readonly: n/a", editModel.inputs[inputSSHOptions].Placeholder)
+}
+
+func TestEditRemoteCommand(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].RemoteCommand = "tmux"
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Equal(t, "tmux", editModel.inputs[inputRemoteCommand].Value())
+	require.Empty(t, editModel.inputs[inputRequestTTY].Value())
+
+	editModel.focusedInput = inputSSHOptions
+	editModel.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputRemoteCommand, editModel.focusedInput)
+	for _, r := range " new -A -s main" {
+		editModel.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	require.Equal(t, "tmux new -A -s main", editModel.host.RemoteCommand)
+
+	// Quotes cannot be passed to ssh, see utils.BuildProcess.
+	editModel.Update(tea.KeyPressMsg{Code: '"', Text: "\""})
+	require.Nil(t, editModel.save(nil))
+	require.Equal(t, "Remote Command is not valid", editModel.title)
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+
+	// Typing is ignored, tty mode can only be selected from the list.
+	editModel.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputRequestTTY, editModel.focusedInput)
+	editModel.Update(tea.KeyPressMsg{Code: 'x', Text: "x"})
+	require.Empty(t, editModel.host.RequestTTY)
+
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	require.Equal(t, "auto", editModel.host.RequestTTY)
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyLeft})
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyLeft})
+	require.Equal(t, "no", editModel.host.RequestTTY)
+	require.Equal(t, "no", editModel.inputs[inputRequestTTY].Value())
+
+	var msgs []tea.Msg
+	testutils.CmdToMessage(editModel.save(nil), &msgs)
+	require.Contains(t, msgs, message.HostUpdate{Host: editModel.host.unwrap()})
+
+	// Hosts from ssh_config declare RemoteCommand and RequestTTY in the host block.
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.False(t, editModel.inputs[inputRemoteCommand].Enabled())
+	require.False(t, editModel.inputs[inputRequestTTY].Enabled())
+	require.Equal(t, "readonly: login shell", editModel.inputs[inputRemoteCommand].Placeholder)
+}
+
+func TestParseTags(t *testing.T) {
+	require.Equal(t, []string{"prod", "db", "eu-west"}, parseTags(" prod,db  eu-west, PROD,"))
+	require.Empty(t, parseTags(" , "))
+	require.Equal(t, "prod, db", formatTags([]string{"prod", "db"}))
+}
+
+func TestEditTags(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[1].Tags = []string{"prod", "db"}
+	storage.Hosts[2].Tags = []string{"dev"}
+	editModel := New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.Empty(t, editModel.inputs[inputTags].Value())
+
+	editModel.focusedInput = inputGroup
+	editModel.inputFocusChange(tea.KeyPressMsg{Code: tea.KeyDown})
+	require.Equal(t, inputTags, editModel.focusedInput)
+	require.True(t, editModel.keyMap.CompleteTag.Enabled())
+
+	// The tag, which is typed, is completed with the right key.
+	editModel.Update(tea.KeyPressMsg{Code: 'p', Text: "p"})
+	require.Equal(t, []string{"prod"}, editModel.inputs[inputTags].MatchedSuggestions())
+	editModel.Update(tea.KeyPressMsg{Code: tea.KeyRight})
+	require.Equal(t, "prod", editModel.inputs[inputTags].Value())
+
+	// Tags, which are already added, are not suggested again.
+	for _, r := range " d" {
+		editModel.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	require.Equal(t, []string{"prod db", "prod dev"}, editModel.inputs[inputTags].MatchedSuggestions())
+	for _, r := range "b, new-tag," {
+		editModel.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	// The input keeps the value as user typed it.
+	require.Equal(t, "prod db, new-tag,", editModel.inputs[inputTags].Value())
+	require.Equal(t, []string{"prod", "db", "new-tag"}, editModel.host.Tags)
+
+	var msgs []tea.Msg
+	testutils.CmdToMessage(editModel.save(nil), &msgs)
+	require.Contains(t, msgs, message.HostUpdate{Host: editModel.host.unwrap()})
+
+	// Tags of hosts from ssh_config are saved to '# GG:TAGS' metadata, so they can be changed too.
+	storage.Hosts[0].StorageType = constant.HostStorageType.SSHConfig
+	editModel = New(existingHostContext(), storage, MockAppState(), &mocklogger.Logger{})
+	require.True(t, editModel.inputs[inputTags].Enabled())
+}
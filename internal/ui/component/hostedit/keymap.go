@@ -9,11 +9,20 @@ type keyMap struct {
 	Down           key.Binding
 	Save           key.Binding
 	CopyInputValue key.Binding
+	SelectFile     key.Binding
+	SelectJumpHost key.Binding
+	AddJumpHost    key.Binding
+	RemoveJumpHost key.Binding
+	SelectTTYMode  key.Binding
+	CompleteTag    key.Binding
 	Discard        key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Save, k.CopyInputValue, k.Discard}
+	return []key.Binding{
+		k.Up, k.Down, k.Save, k.CopyInputValue, k.SelectFile, k.SelectJumpHost, k.AddJumpHost, k.SelectTTYMode,
+		k.CompleteTag, k.Discard,
+	}
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
@@ -37,6 +46,31 @@ var keys = keyMap{
 		key.WithKeys("alt+enter"),
 		key.WithHelp("alt+enter", "title ↔ host"),
 	),
+	SelectFile: key.NewBinding(
+		key.WithKeys("left", "right"),
+		key.WithHelp("←/→", "change file"),
+	),
+	SelectJumpHost: key.NewBinding(
+		key.WithKeys("left", "right"),
+		key.WithHelp("←/→", "change jump host"),
+	),
+	AddJumpHost: key.NewBinding(
+		key.WithKeys("+"),
+		key.WithHelp("+/-", "add/remove jump host"),
+	),
+	// Help is displayed by AddJumpHost binding.
+	RemoveJumpHost: key.NewBinding(
+		key.WithKeys("-", "backspace", "delete"),
+	),
+	SelectTTYMode: key.NewBinding(
+		key.WithKeys("left", "right"),
+		key.WithHelp("←/→", "change tty mode"),
+	),
+	// The tag is completed by the input, see tagCompletionKey. The binding only displays help.
+	CompleteTag: key.NewBinding(
+		key.WithKeys("right"),
+		key.WithHelp("→", "complete tag"),
+	),
 	Discard: key.NewBinding(
 		key.WithKeys("esc"),
 		key.WithHelp("esc", "discard"),
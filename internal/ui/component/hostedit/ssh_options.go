@@ -0,0 +1,47 @@
+package hostedit
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/grafviktor/goto/internal/model/sshcommand"
+)
+
+// parseSSHOptions - reads additional ssh flags and options, which are separated by spaces. Options are written
+// as "Keyword=value", with or without '-o' flag. For instance: "-A -o ServerAliveInterval=30 Compression=yes".
+func parseSSHOptions(value string) ([]string, error) {
+	options := []string{}
+	fields := strings.Fields(value)
+	for i := 0; i < len(fields); i++ {
+		option := fields[i]
+		if option == "-o" {
+			if i == len(fields)-1 {
+				return nil, errors.New("'-o' flag must be followed by Keyword=value")
+			}
+
+			i++
+			option = fields[i]
+			if strings.HasPrefix(option, "-") {
+				return nil, errors.New("'-o' flag must be followed by Keyword=value")
+			}
+		}
+
+		if err := sshcommand.ValidateExtraOption(option); err != nil {
+			return nil, err
+		}
+
+		options = append(options, option)
+	}
+
+	return options, nil
+}
+
+// formatSSHOptions - writes ssh flags and options the same way as parseSSHOptions reads them.
+func formatSSHOptions(options []string) string {
+	return strings.Join(options, " ")
+}
+
+func sshOptionsValidator(s string) error {
+	_, err := parseSSHOptions(s)
+	return err
+}
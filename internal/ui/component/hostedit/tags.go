@@ -0,0 +1,31 @@
+package hostedit
+
+import (
+	"slices"
+	"strings"
+)
+
+// tagSeparators - tags are separated by commas and/or whitespace, the same as in '# GG:TAGS' metadata.
+const tagSeparators = ", \t"
+
+// parseTags - reads tags from the text. Duplicates are removed, tags are case-insensitive. For instance:
+// "prod, db eu-west".
+func parseTags(value string) []string {
+	tags := []string{}
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return strings.ContainsRune(tagSeparators, r) }) {
+		if !containsTag(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
+
+// formatTags - writes tags the same way as parseTags reads them.
+func formatTags(tags []string) string {
+	return strings.Join(tags, ", ")
+}
+
+func containsTag(tags []string, tag string) bool {
+	return slices.ContainsFunc(tags, func(t string) bool { return strings.EqualFold(t, tag) })
+}
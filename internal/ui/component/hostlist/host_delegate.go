@@ -7,6 +7,7 @@ import (
 
 	"charm.land/bubbles/v2/list"
 	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
 	"github.com/samber/lo"
 
 	"github.com/grafviktor/goto/internal/constant"
@@ -88,6 +89,34 @@ func (hd *HostDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 			itemCopy.Host.Title = fmt.Sprintf("%s %s", itemCopy.Title(), hd.styles.groupHint.Render(groupName))
 		}
 
+		// "Match" blocks might change ssh settings of the host, user should be aware of them.
+		if len(itemCopy.MatchCriteria) > 0 && hd.layout != nil && *hd.layout == constant.ScreenLayoutDescription {
+			matchHint := "Match " + strings.Join(itemCopy.MatchCriteria, ", Match ")
+			itemCopy.Host.Description = strings.TrimSpace(
+				fmt.Sprintf("%s %s", itemCopy.Description(), hd.styles.groupHint.Render(matchHint)))
+		}
+
+		// Markers are appended to the title, so that filter highlights the right characters.
+		if itemCopy.Pinned {
+			itemCopy.Host.Title = fmt.Sprintf("%s %s", itemCopy.Title(), hd.styles.groupHint.Render("★"))
+		}
+
+		if itemCopy.Color != "" {
+			colorMarker := lipgloss.NewStyle().Foreground(lipgloss.Color(itemCopy.Color)).Render("●")
+			itemCopy.Host.Title = fmt.Sprintf("%s %s", itemCopy.Title(), colorMarker)
+		}
+
+		// Tags are displayed as badges, for instance "#prod #db".
+		if len(itemCopy.Tags) > 0 {
+			badges := lo.Map(itemCopy.Tags, func(tag string, _ int) string { return hd.styles.groupHint.Render("#" + tag) })
+			itemCopy.Host.Title = fmt.Sprintf("%s %s", itemCopy.Title(), strings.Join(badges, " "))
+		}
+
+		// Hosts from additional ssh_config sources are labeled with the source name.
+		if sourceLabel := itemCopy.SourceLabel(); sourceLabel != "" {
+			itemCopy.Host.Title = fmt.Sprintf("%s %s", itemCopy.Title(), hd.styles.groupHint.Render("@"+sourceLabel))
+		}
+
 		hd.DefaultDelegate.Render(w, m, index, itemCopy)
 	} else {
 		hd.DefaultDelegate.Render(w, m, index, item)
@@ -54,9 +54,14 @@ func Test_IsHostMovedToAnotherGroup(t *testing.T) {
 
 // Test cases for Render function.
 func TestHostDelegate_Render(t *testing.T) {
-	hostNoGroup := ListItemHost{Host: host.NewHost(0, "Mock Host 1", "", "localhost", "", "", "22")}
-	hostWithGroup := ListItemHost{Host: host.NewHost(0, "Mock Host 2", "", "localhost", "", "", "22")}
+	hostNoGroup := ListItemHost{Host: host.NewHost("0", "Mock Host 1", "", "localhost", "", "", "22")}
+	hostWithGroup := ListItemHost{Host: host.NewHost("0", "Mock Host 2", "", "localhost", "", "", "22")}
 	hostWithGroup.Group = "Group 2"
+	hostPinned := ListItemHost{Host: host.NewHost("0", "Mock Host 3", "", "localhost", "", "", "22")}
+	hostPinned.Pinned = true
+	hostPinned.Color = "3"
+	hostTagged := ListItemHost{Host: host.NewHost("0", "Mock Host 4", "", "localhost", "", "", "22")}
+	hostTagged.Tags = []string{"prod", "db"}
 
 	tests := []struct {
 		appStateGroup string
@@ -99,6 +104,20 @@ func TestHostDelegate_Render(t *testing.T) {
 			constant.ScreenLayoutCompact,
 			"Mock Host 2 (Group 2)",
 		},
+		{
+			// Pinned and colored hosts are marked after the title
+			"",
+			hostPinned,
+			constant.ScreenLayoutCompact,
+			"Mock Host 3 ★ ●",
+		},
+		{
+			// Tags are displayed as badges after the title
+			"",
+			hostTagged,
+			constant.ScreenLayoutCompact,
+			"Mock Host 4 #prod #db",
+		},
 	}
 
 	for _, tc := range tests {
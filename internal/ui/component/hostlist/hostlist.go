@@ -8,14 +8,17 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/key"
 	"charm.land/bubbles/v2/list"
+	"charm.land/bubbles/v2/textinput"
 	tea "charm.land/bubbletea/v2"
 	"github.com/samber/lo"
 
 	"github.com/grafviktor/goto/internal/constant"
 	hostModel "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/model/sshcommand"
 	"github.com/grafviktor/goto/internal/state"
 	"github.com/grafviktor/goto/internal/storage"
 	"github.com/grafviktor/goto/internal/ui/message"
@@ -27,6 +30,7 @@ var (
 	modeCloseApp          = "closeApp"
 	modeDefault           = ""
 	modeRemoveItem        = "removeItem"
+	modeRunCommand        = "runCommand"
 	modeSSHCopyID         = "sshCopyID"
 	defaultListTitle      = "press 'n' to add a new host"
 )
@@ -48,6 +52,8 @@ type ListModel struct {
 	logger   iLogger
 	mode     string
 	styles   styles
+	// commandInput - reads the command, which user runs on the host instead of the host's remote command.
+	commandInput textinput.Model
 }
 
 // New - creates new host list model.
@@ -76,13 +82,24 @@ func New(_ context.Context, storage storage.HostStorage, appState *state.State,
 	model.Paginator.InactiveDot = styles.paginatorInactiveDot
 	model.Help.Styles = styles.help
 
+	commandInput := textinput.New()
+	commandInput.Prompt = ""
+	commandInput.CharLimit = 512
+	commandInput.Validate = sshcommand.ValidateRemoteCommand
+	inputStyles := commandInput.Styles()
+	inputStyles.Focused.Text = styles.filterInput
+	// Title is not re-rendered on cursor blink messages, they're handled by the list.
+	inputStyles.Cursor.Blink = false
+	commandInput.SetStyles(inputStyles)
+
 	m := ListModel{
-		Model:    model,
-		keyMap:   delegateKeys,
-		repo:     storage,
-		appState: appState,
-		logger:   log,
-		styles:   styles,
+		Model:        model,
+		keyMap:       delegateKeys,
+		repo:         storage,
+		appState:     appState,
+		logger:       log,
+		styles:       styles,
+		commandInput: commandInput,
 	}
 
 	m.KeyMap.CursorUp.Unbind()
@@ -114,6 +131,9 @@ func (m *ListModel) loadHosts() tea.Cmd {
 		return message.TeaCmd(message.ExitWithError{Err: err})
 	}
 
+	// Helper hosts, for instance bastions, can be hidden with '# GG:HIDE' metadata.
+	hosts = lo.Reject(hosts, func(h hostModel.Host, _ int) bool { return h.Hidden })
+
 	// If host group is selected only load hosts from this group.
 	if m.appState.Group != "" {
 		hosts = lo.Filter(hosts, func(h hostModel.Host, _ int) bool {
@@ -121,6 +141,11 @@ func (m *ListModel) loadHosts() tea.Cmd {
 		})
 	}
 
+	// If tags are selected only load hosts with all of them, or with at least one of them.
+	hosts = lo.Filter(hosts, func(h hostModel.Host, _ int) bool {
+		return h.MatchTags(m.appState.TagFilter, m.appState.TagFilterMatchAll)
+	})
+
 	// Wrap hosts into List items.
 	items := make([]list.Item, 0, len(hosts))
 	for _, h := range hosts {
@@ -128,9 +153,30 @@ func (m *ListModel) loadHosts() tea.Cmd {
 	}
 
 	slices.SortFunc(items, hostComparator)
-	setItemsCmd := m.SetItems(items)
-	selectHostByIDCmd := m.selectHostByID(m.appState.Selected)
-	return tea.Sequence(setItemsCmd, selectHostByIDCmd)
+	if setItemsCmd := m.SetItems(items); setItemsCmd != nil {
+		// When filter is active, the list re-filters items asynchronously. Apply filter results
+		// right away, otherwise the host would be selected in the unfiltered collection.
+		m.Model, _ = m.Model.Update(setItemsCmd())
+	}
+
+	return tea.Sequence(m.selectHostByID(m.appState.Selected), m.displayStorageWarnings())
+}
+
+// displayStorageWarnings - tells the user about hosts which are not displayed, for instance, because
+// the same host ID is used in several files. All warnings are written to the log file.
+func (m *ListModel) displayStorageWarnings() tea.Cmd {
+	reporter, ok := m.repo.(storage.WarningReporter)
+	if !ok || len(reporter.Warnings()) == 0 {
+		return nil
+	}
+
+	warnings := reporter.Warnings()
+	text := warnings[0]
+	if len(warnings) > 1 {
+		text = fmt.Sprintf("%s, %d more warnings in the log file", text, len(warnings)-1)
+	}
+
+	return m.displayNotificationMsg(text)
 }
 
 func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -155,6 +201,12 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case message.GroupSelect:
 		cmd := m.onGroupSelect(msg)
 		return m, cmd
+	case message.TagSelect:
+		cmd := m.onTagSelect(msg)
+		return m, cmd
+	case message.HostListReload:
+		cmd := m.onHostListReload()
+		return m, cmd
 	case message.HideUINotification:
 		if msg.ComponentName == "hostlist" {
 			m.logger.Debug("[UI] Hide notification message")
@@ -164,6 +216,9 @@ func (m *ListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case message.ErrorOccurred:
 		return m, m.displayNotificationMsg(msg.Err.Error())
+	case message.TunnelEnded:
+		text := fmt.Sprintf("tunnel %s (%s) has ended", msg.Exit.Tunnel.Forward.Name, msg.Exit.Tunnel.HostTitle)
+		return m, m.displayNotificationMsg(text)
 	default:
 		m.SetShowStatusBar(m.FilterState() != list.Unfiltered)
 		return m, m.updateChildModel(msg)
@@ -201,8 +256,18 @@ func (m *ListModel) handleKeyboardEvent(msg tea.KeyPressMsg) tea.Cmd {
 		return m.handleKeyEventWhenModeEnabled(msg)
 	case key.Matches(msg, m.keyMap.selectGroup):
 		return message.TeaCmd(message.ViewGroupListOpen{})
+	case key.Matches(msg, m.keyMap.selectTags):
+		return message.TeaCmd(message.ViewTagListOpen{})
+	case key.Matches(msg, m.keyMap.showDetails):
+		return m.showHostDetails()
+	case key.Matches(msg, m.keyMap.showPatterns):
+		return message.TeaCmd(message.ViewPatternListOpen{})
+	case key.Matches(msg, m.keyMap.showTunnels):
+		return message.TeaCmd(message.ViewTunnelListOpen{})
 	case key.Matches(msg, m.keyMap.connect):
 		return m.constructProcessCmd(constant.ProcessTypeSSHConnect)
+	case key.Matches(msg, m.keyMap.runCommand):
+		return m.enterRunCommandMode()
 	case key.Matches(msg, m.keyMap.copyID):
 		return m.enterSSHCopyIDMode()
 	case key.Matches(msg, m.keyMap.remove):
@@ -215,6 +280,8 @@ func (m *ListModel) handleKeyboardEvent(msg tea.KeyPressMsg) tea.Cmd {
 		return m.copyItem()
 	case key.Matches(msg, m.keyMap.toggleLayout):
 		return m.onToggleLayout()
+	case key.Matches(msg, m.keyMap.refresh):
+		return m.refreshHosts()
 	case msg.Key().Code == tea.KeyEsc:
 		m.logger.Debug("[UI] Receive Escape key. Ask user for confirmation to close the app.")
 		m.enterCloseAppMode()
@@ -334,7 +401,7 @@ func (m *ListModel) editItem() tea.Cmd {
 	}
 
 	// m.Model.ResetFilter()
-	m.logger.Info("[UI] Edit item id: %d, title: %s", item.ID, item.Title())
+	m.logger.Info("[UI] Edit item id: %s, title: %s", item.ID, item.Title())
 	return tea.Sequence(
 		message.TeaCmd(message.ViewHostEditOpen{HostID: item.ID}),
 		// Load SSH config for the selected host
@@ -350,11 +417,13 @@ func (m *ListModel) copyItem() tea.Cmd {
 	}
 
 	originalHost := item.Host
-	m.logger.Info("[UI] Copy host item id: %d, title: %s", originalHost.ID, originalHost.Title)
+	m.logger.Info("[UI] Copy host item id: %s, title: %s", originalHost.ID, originalHost.Title)
 	clonedHost := originalHost.Clone()
+	// ssh_config does not allow spaces in host aliases, use dash as a separator instead.
+	titleFormat := lo.Ternary(originalHost.StorageType == constant.HostStorageType.SSHConfig, "%s-%d", "%s (%d)")
 	for i := 1; ok; i++ {
 		// Keep generating new title until it's unique
-		clonedHostTitle := fmt.Sprintf("%s (%d)", originalHost.Title, i)
+		clonedHostTitle := fmt.Sprintf(titleFormat, originalHost.Title, i)
 		listItems := m.Items()
 		idx := slices.IndexFunc(listItems, func(li list.Item) bool {
 			return li.(ListItemHost).Title() == clonedHostTitle //nolint:errcheck // list item always contains ListItemHost
@@ -489,6 +558,40 @@ func (m *ListModel) onGroupSelect(msg message.GroupSelect) tea.Cmd {
 	return tea.Sequence(cmds...)
 }
 
+func (m *ListModel) onTagSelect(msg message.TagSelect) tea.Cmd {
+	m.logger.Debug("[UI] Update app state. Active tags: %q, match all: %v", msg.Tags, msg.MatchAll)
+	m.appState.TagFilter = msg.Tags
+	m.appState.TagFilterMatchAll = msg.MatchAll
+	// Reset filter and re-load hosts, the same as when a group is selected.
+	m.ResetFilter()
+	text := "display all hosts"
+	if len(msg.Tags) > 0 {
+		text = "tags " + strings.Join(msg.Tags, lo.Ternary(msg.MatchAll, " and ", " or "))
+	}
+
+	return tea.Sequence(m.loadHosts(), m.displayNotificationMsg(text))
+}
+
+func (m *ListModel) onHostListReload() tea.Cmd {
+	m.logger.Info("[UI] Hosts were modified outside of the app, reload host list")
+	// Filter and selected host are preserved by loadHosts.
+	return tea.Sequence(m.loadHosts(), m.displayNotificationMsg("host list reloaded"))
+}
+
+func (m *ListModel) refreshHosts() tea.Cmd {
+	m.logger.Info("[UI] Refresh host list")
+	if refreshable, ok := m.repo.(storage.Refreshable); ok {
+		// Remote ssh_config files are fetched again.
+		refreshable.Refresh()
+	}
+
+	return tea.Sequence(
+		message.TeaCmd(message.HostListRefresh{}),
+		m.loadHosts(),
+		m.displayNotificationMsg("host list refreshed"),
+	)
+}
+
 func (m *ListModel) onFocusChanged() tea.Cmd {
 	m.updateTitle()
 	m.updateKeyMap()
@@ -544,24 +647,14 @@ func (m *ListModel) onToggleLayout() tea.Cmd {
  */
 
 func (m *ListModel) constructProcessCmd(processType constant.ProcessType) tea.Cmd {
-	// Do not use m.SelectedItem() here!
-	// list.Model keeps 2 collections - m.items and m.filteredItems, which can be inconsistent
-	// as a result in some hosts taken from m.filteredItems ssh config is nil.
-	var host *hostModel.Host
-	for _, item := range m.Items() {
-		if listItemHost, ok := item.(ListItemHost); ok && listItemHost.ID == m.appState.Selected {
-			host = &listItemHost.Host
-			break
-		}
-	}
-
+	host := m.focusedHost()
 	if host == nil {
-		m.logger.Error("[UI] Could not find host with ID='%d'", m.appState.Selected)
+		m.logger.Error("[UI] Could not find host with ID='%s'", m.appState.Selected)
 		return message.TeaCmd(message.ErrorOccurred{Err: errors.New(itemNotSelectedErrMsg)})
 	}
 
 	if host.SSHHostConfig == nil {
-		errorText := fmt.Sprintf("[UI] SSH config is not set for host ID='%d', Title='%s'", host.ID, host.Title)
+		errorText := fmt.Sprintf("[UI] SSH config is not set for host ID='%s', Title='%s'", host.ID, host.Title)
 		m.logger.Error(errorText)
 		return message.TeaCmd(message.ErrorOccurred{Err: errors.New(errorText)})
 	}
@@ -576,6 +669,53 @@ func (m *ListModel) constructProcessCmd(processType constant.ProcessType) tea.Cm
 	}
 }
 
+// runCommand - connects to the focused host and runs the command instead of the host's remote command.
+func (m *ListModel) runCommand(command string) tea.Cmd {
+	host := m.focusedHost()
+	if host == nil {
+		m.logger.Error("[UI] Could not find host with ID='%s'", m.appState.Selected)
+		return message.TeaCmd(message.ErrorOccurred{Err: errors.New(itemNotSelectedErrMsg)})
+	}
+
+	m.logger.Debug("[UI] Run command %q on host id: %v, title: %q", command, host.ID, host.Title)
+	// The host is a copy, the command is not saved. RequestTTY is reset, so ssh allocates tty by default.
+	hostCopy := *host
+	hostCopy.RemoteCommand = command
+	hostCopy.RequestTTY = ""
+
+	return message.TeaCmd(message.RunProcessSSHConnect{Host: hostCopy})
+}
+
+// focusedHost - returns the host, which is selected in the list, or nil if there is no such host.
+func (m *ListModel) focusedHost() *hostModel.Host {
+	// Do not use m.SelectedItem() here!
+	// list.Model keeps 2 collections - m.items and m.filteredItems, which can be inconsistent
+	// as a result in some hosts taken from m.filteredItems ssh config is nil.
+	for _, item := range m.Items() {
+		if listItemHost, ok := item.(ListItemHost); ok && listItemHost.ID == m.appState.Selected {
+			return &listItemHost.Host
+		}
+	}
+
+	return nil
+}
+
+// showHostDetails - opens the view, which displays effective configuration of the focused host.
+func (m *ListModel) showHostDetails() tea.Cmd {
+	host := m.focusedHost()
+	if host == nil {
+		m.logger.Error("[UI] Could not find host with ID='%s'", m.appState.Selected)
+		return message.TeaCmd(message.ErrorOccurred{Err: errors.New(itemNotSelectedErrMsg)})
+	}
+
+	m.logger.Debug("[UI] Show details for host id: %v, title: %q", host.ID, host.Title)
+	return tea.Sequence(
+		message.TeaCmd(message.ViewHostDetailsOpen{Host: *host}),
+		// Config is loaded again, if the previous attempt failed, the view displays the error.
+		message.TeaCmd(message.RunProcessSSHLoadConfig{Host: *host}),
+	)
+}
+
 func (m *ListModel) updateTitle() {
 	var newTitle string
 	item, isHost := m.SelectedItem().(ListItemHost)
@@ -588,12 +728,22 @@ func (m *ListModel) updateTitle() {
 		newTitle = fmt.Sprintf("delete \"%s\"? (y/N)", item.Title())
 	case m.mode == modeCloseApp:
 		newTitle = "close app? (y/N)"
+	case m.mode == modeRunCommand && isHost:
+		newTitle = fmt.Sprintf("run on \"%s\": %s", item.Title(), m.commandInput.View())
+		if m.commandInput.Err != nil {
+			newTitle = fmt.Sprintf("%s (%v)", newTitle, m.commandInput.Err)
+		}
 	case isHost:
-		connectCmd := cmdSSHConnectPreview(item.Host)
-		newTitle = m.prefixWithGroupName(connectCmd)
+		host := item.Host
+		if err := host.ResolveJumpChain(m.repo.Get); err != nil {
+			m.logger.Debug("[UI] Cannot resolve jump hosts of host id: %s. %v", host.ID, err)
+		}
+
+		connectCmd := cmdSSHConnectPreview(host)
+		newTitle = m.prefixWithFilters(m.suffixWithOfflineHint(connectCmd))
 	default:
 		// If it's NOT a host list item, then probably the list is just empty
-		newTitle = m.prefixWithGroupName(defaultListTitle)
+		newTitle = m.prefixWithFilters(m.suffixWithOfflineHint(defaultListTitle))
 	}
 
 	if m.Title != newTitle {
@@ -602,17 +752,57 @@ func (m *ListModel) updateTitle() {
 	}
 }
 
-func (m *ListModel) prefixWithGroupName(title string) string {
+// prefixWithFilters - prefixes the title with abbreviation of the selected group and with the selected tags,
+// which are joined with "&" when hosts must have all of them, or with "|" otherwise.
+func (m *ListModel) prefixWithFilters(title string) string {
+	prefix := ""
 	if !utils.StringEmpty(&m.appState.Group) {
-		shortGroupName := utils.StringAbbreviation(m.appState.Group)
+		prefix = m.styles.groupAbbreviation.Render(utils.StringAbbreviation(m.appState.Group))
+	}
+
+	if len(m.appState.TagFilter) > 0 {
+		tags := "#" + strings.Join(m.appState.TagFilter, lo.Ternary(m.appState.TagFilterMatchAll, "&", "|"))
+		prefix += m.styles.groupAbbreviation.Render(tags)
+	}
+
+	if prefix != "" {
 		title = m.Styles.Title.Render(title)
 		m.Styles.Title = m.Styles.Title.Padding(0)
-		return fmt.Sprintf("%s%s", m.styles.groupAbbreviation.Render(shortGroupName), title)
+		return prefix + title
 	}
 
 	return title
 }
 
+// suffixWithOfflineHint - tells the user how old the hosts are, when remote ssh_config files cannot be
+// fetched and their cached copies are used.
+func (m *ListModel) suffixWithOfflineHint(title string) string {
+	refreshable, ok := m.repo.(storage.Refreshable)
+	if !ok {
+		return title
+	}
+
+	fetchedAt, offline := refreshable.OfflineSince()
+	if !offline {
+		return title
+	}
+
+	return fmt.Sprintf("%s (offline, data is %s old)", title, formatAge(time.Since(fetchedAt)))
+}
+
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Minute:
+		return "<1m"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
 // SSH config path regex. Example: "... -F /home/user/.ssh/config ...".
 var sshConfigPathRe = regexp.MustCompile(`\s-F "([^"]+)"`)
 
@@ -621,12 +811,26 @@ const sshDefaultPort = "22"
 func cmdSSHConnectPreview(h hostModel.Host) string {
 	if h.StorageType == constant.HostStorageType.SSHConfig && h.SSHHostConfig != nil {
 		// If ssh_config is loaded for the host, then we can build approximate connect command.
-		connectCmd := fmt.Sprintf("ssh %s@%s", h.SSHHostConfig.User, h.SSHHostConfig.Hostname)
+		connectCmd := fmt.Sprintf("ssh %s@%s", h.SSHHostConfig.User, utils.UnbracketAddress(h.SSHHostConfig.Hostname))
 		// Display only non-default network port
 		if h.SSHHostConfig.Port != "" && h.SSHHostConfig.Port != sshDefaultPort {
 			connectCmd = fmt.Sprintf("%s -p %s", connectCmd, h.SSHHostConfig.Port)
 		}
 
+		if proxyJump := h.SSHHostConfig.Value("proxyjump"); proxyJump != "" && proxyJump != "none" {
+			connectCmd = fmt.Sprintf("%s -J %s", connectCmd, proxyJump)
+		}
+
+		// Remote command, which is typed in the prompt, replaces the one from ssh_config.
+		remoteCommand := h.RemoteCommand
+		if utils.StringEmpty(&remoteCommand) && h.SSHHostConfig.Value("remotecommand") != "none" {
+			remoteCommand = h.SSHHostConfig.Value("remotecommand")
+		}
+
+		if !utils.StringEmpty(&remoteCommand) {
+			connectCmd = fmt.Sprintf("%s %q", connectCmd, remoteCommand)
+		}
+
 		return connectCmd
 	}
 
@@ -643,7 +847,7 @@ func (m *ListModel) updateKeyMap() {
 	m.logger.Debug("[UI] Edit keyboard shortcuts: %v", keyMapState)
 }
 
-func (m *ListModel) selectHostByID(id int) tea.Cmd {
+func (m *ListModel) selectHostByID(id string) tea.Cmd {
 	// Use VisibleItems() instead of Items() because we need to find the correct index when deleting an item
 	// while in filter mode where part of the collection is hidden. You can replicate a wrong behavior when using Items():
 	// Enter filter mode, enter remove mode and then cancel it. The focus will be lost.
@@ -704,6 +908,25 @@ func (m *ListModel) enterRemoveItemMode() tea.Cmd {
 	return nil
 }
 
+func (m *ListModel) enterRunCommandMode() tea.Cmd {
+	// Check if item is selected.
+	item, ok := m.SelectedItem().(ListItemHost)
+	if !ok {
+		m.logger.Debug("[UI] Cannot run command. Host is not selected.")
+		return message.TeaCmd(message.ErrorOccurred{Err: errors.New(itemNotSelectedErrMsg)})
+	}
+
+	m.mode = modeRunCommand
+	m.logger.Debug("[UI] Enter %s mode. Ask user for the command.", m.mode)
+	// Host's own remote command is a good starting point, user can edit it or replace it.
+	m.commandInput.SetValue(item.RemoteCommand)
+	m.commandInput.CursorEnd()
+	m.commandInput.Focus()
+	m.updateTitle()
+
+	return nil
+}
+
 func (m *ListModel) enterCloseAppMode() {
 	m.mode = modeCloseApp
 	m.logger.Debug("[UI] Enter %s mode. Ask user for confirmation.", m.mode)
@@ -711,6 +934,10 @@ func (m *ListModel) enterCloseAppMode() {
 }
 
 func (m *ListModel) handleKeyEventWhenModeEnabled(msg tea.KeyPressMsg) tea.Cmd {
+	if m.mode == modeRunCommand && msg.Key().Code != tea.KeyEsc {
+		return m.handleKeyEventWhenRunCommandMode(msg)
+	}
+
 	if key.Matches(msg, m.keyMap.confirm) {
 		return m.confirmAction()
 	}
@@ -730,6 +957,24 @@ func (m *ListModel) handleKeyEventWhenModeEnabled(msg tea.KeyPressMsg) tea.Cmd {
 	return nil
 }
 
+// handleKeyEventWhenRunCommandMode - passes key events to the command input, enter key runs the command.
+func (m *ListModel) handleKeyEventWhenRunCommandMode(msg tea.KeyPressMsg) tea.Cmd {
+	if msg.Key().Code == tea.KeyEnter {
+		if m.commandInput.Err != nil || utils.StringEmpty(lo.ToPtr(m.commandInput.Value())) {
+			m.logger.Debug("[UI] Cannot run command %q. %v", m.commandInput.Value(), m.commandInput.Err)
+			return nil
+		}
+
+		return m.confirmAction()
+	}
+
+	var cmd tea.Cmd
+	m.commandInput, cmd = m.commandInput.Update(msg)
+	m.updateTitle()
+
+	return cmd
+}
+
 func (m *ListModel) confirmAction() tea.Cmd {
 	m.logger.Debug("[UI] Exit %s mode. Confirm action.", m.mode)
 
@@ -746,6 +991,11 @@ func (m *ListModel) confirmAction() tea.Cmd {
 	case modeCloseApp:
 		m.mode = modeDefault
 		cmd = tea.Quit
+	case modeRunCommand:
+		m.mode = modeDefault
+		m.commandInput.Blur()
+		m.updateTitle()
+		cmd = m.runCommand(strings.TrimSpace(m.commandInput.Value()))
 	}
 
 	return cmd
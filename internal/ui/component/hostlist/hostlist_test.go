@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"charm.land/bubbles/v2/list"
 	tea "charm.land/bubbletea/v2"
@@ -24,17 +25,17 @@ func TestListModel_Init(t *testing.T) {
 	// Test Init function which loads data from storage
 	storageShouldFail := false
 	storage := testutils.NewMockStorage(storageShouldFail)
-	fakeAppState := state.State{Selected: 1}
+	fakeAppState := state.State{Selected: "1"}
 	lm := New(context.TODO(), storage, &fakeAppState, &mocklogger.Logger{})
 	teaCmd := lm.Init()
 
 	var dst []tea.Msg
 	testutils.CmdToMessage(teaCmd, &dst)
 	require.Equal(t, []tea.Msg{
-		message.HostSelect{HostID: 1},
+		message.HostSelect{HostID: "1"},
 		message.RunProcessSSHLoadConfig{
 			Host: host.Host{
-				ID:               1,
+				ID:               "1",
 				Title:            "Mock Host 1",
 				Description:      "",
 				Group:            "Group 1",
@@ -51,7 +52,7 @@ func TestListModel_Init(t *testing.T) {
 	require.Equal(t, "Mock Host 1", lm.Items()[0].(ListItemHost).Title())
 	require.Equal(t, "Mock Host 2", lm.Items()[1].(ListItemHost).Title())
 	// Check that currently selected item is "1"
-	require.Equal(t, 1, lm.SelectedItem().(ListItemHost).ID)
+	require.Equal(t, "1", lm.SelectedItem().(ListItemHost).ID)
 
 	// Test loadHosts function when a group is selected
 	storage = testutils.NewMockStorage(false)
@@ -161,10 +162,10 @@ func TestRemoveItem(t *testing.T) {
 			want: []tea.Msg{
 				message.HideUINotification{ComponentName: "hostlist"},
 				// Because we remote item "Mock Host 1" (which has index 0), we should ensure that next available item will be focused
-				message.HostSelect{HostID: 2},
+				message.HostSelect{HostID: "2"},
 				message.RunProcessSSHLoadConfig{
 					Host: host.Host{
-						ID:               2,
+						ID:               "2",
 						Title:            "Mock Host 2",
 						Description:      "",
 						Group:            "Group 2",
@@ -188,10 +189,10 @@ func TestRemoveItem(t *testing.T) {
 			want: []tea.Msg{
 				message.HideUINotification{ComponentName: "hostlist"},
 				// Because we remote item "Mock Host 1" (which has index 0), we should ensure that next available item will be focused
-				message.HostSelect{HostID: 2},
+				message.HostSelect{HostID: "2"},
 				message.RunProcessSSHLoadConfig{
 					Host: host.Host{
-						ID:               2,
+						ID:               "2",
 						Title:            "Mock Host 2",
 						Description:      "",
 						Group:            "Group 2",
@@ -358,10 +359,10 @@ func TestExitRemoveItemMode(t *testing.T) {
 
 	expected := []tea.Msg{
 		// Because we remote item "Mock Host 1" (which has index 0), we should ensure that next available item will be focused
-		message.HostSelect{HostID: 1},
+		message.HostSelect{HostID: "1"},
 		message.RunProcessSSHLoadConfig{
 			Host: host.Host{
-				ID:               1,
+				ID:               "1",
 				Title:            "Mock Host 1",
 				Description:      "",
 				Group:            "Group 1",
@@ -496,6 +497,67 @@ func TestCmdSSHConnectPreview(t *testing.T) {
 			},
 			expected: "ssh root@localhost -p 2222",
 		},
+		{
+			name: "YAML file host, IPv6 address in brackets",
+			host: host.Host{
+				Title:       "MOCK_HOST_4",
+				Address:     "[2001:db8::10]",
+				LoginName:   "root",
+				StorageType: constant.HostStorageType.YAMLFile,
+			},
+			expected: "ssh -l root 2001:db8::10",
+		},
+		{
+			name: "SSH config host, IPv6 address",
+			host: host.Host{
+				Title: "MOCK_HOST_5",
+				SSHHostConfig: &sshconfig.Config{
+					Hostname: "fe80::1%eth0",
+					Port:     "22",
+					User:     "root",
+				},
+				StorageType: constant.HostStorageType.SSHConfig,
+			},
+			expected: "ssh root@fe80::1%eth0",
+		},
+		{
+			name: "YAML file host, jump hosts",
+			host: host.Host{
+				Title:       "MOCK_HOST_6",
+				Address:     "localhost",
+				JumpChain:   "admin@bastion:2222",
+				StorageType: constant.HostStorageType.YAMLFile,
+			},
+			expected: "ssh -J admin@bastion:2222 localhost",
+		},
+		{
+			name: "SSH config host, jump hosts",
+			host: host.Host{
+				Title:         "MOCK_HOST_7",
+				SSHHostConfig: sshconfig.Parse("hostname localhost\nport 22\nuser root\nproxyjump bastion"),
+				StorageType:   constant.HostStorageType.SSHConfig,
+			},
+			expected: "ssh root@localhost -J bastion",
+		},
+		{
+			name: "YAML file host, remote command",
+			host: host.Host{
+				Title:         "MOCK_HOST_8",
+				Address:       "localhost",
+				RemoteCommand: "tmux new -A -s main",
+				StorageType:   constant.HostStorageType.YAMLFile,
+			},
+			expected: `ssh -t localhost "tmux new -A -s main"`,
+		},
+		{
+			name: "SSH config host, remote command",
+			host: host.Host{
+				Title:         "MOCK_HOST_9",
+				SSHHostConfig: sshconfig.Parse("hostname localhost\nport 22\nuser root\nremotecommand tmux attach"),
+				StorageType:   constant.HostStorageType.SSHConfig,
+			},
+			expected: `ssh root@localhost "tmux attach"`,
+		},
 		{
 			name: "SSH config host, config not yet loaded",
 			host: host.Host{
@@ -546,7 +608,7 @@ func TestListModel_editItem(t *testing.T) {
 	var dst []tea.Msg
 	testutils.CmdToMessage(teaCmd, &dst)
 
-	require.Contains(t, dst, message.ViewHostEditOpen{HostID: 1})
+	require.Contains(t, dst, message.ViewHostEditOpen{HostID: "1"})
 	require.Contains(t, dst, message.RunProcessSSHLoadConfig{Host: lm.SelectedItem().(ListItemHost).Host})
 }
 
@@ -563,7 +625,7 @@ func TestListModel_copyItem(t *testing.T) {
 	lm.logger = &mocklogger.Logger{}
 
 	lm.copyItem()
-	host, err := lm.repo.Get(3)
+	host, err := lm.repo.Get("4")
 	require.NoError(t, err)
 	require.Equal(t, "Mock Host 1 (1)", host.Title)
 }
@@ -628,7 +690,7 @@ func TestUpdate_HostSSHConfigLoaded(t *testing.T) {
 		User:         "mock_username",
 	}
 	lm.Update(message.HostSSHConfigLoadComplete{
-		HostID: 1,
+		HostID: "1",
 		Config: expectedConfig,
 	})
 
@@ -645,7 +707,7 @@ func TestUpdate_HostUpdated(t *testing.T) {
 	require.Equal(t, "Mock Host 1", lm.Items()[0].(ListItemHost).Title())
 
 	updatedHost := host.Host{
-		ID:               1,
+		ID:               "1",
 		Title:            "Mock Host 11",
 		Description:      "Mock Host Updated",
 		Address:          "mock_hostname",
@@ -660,7 +722,7 @@ func TestUpdate_HostUpdated(t *testing.T) {
 
 	// Also check that host is inserted into a correct position of the hostlist model
 	updatedHost = host.Host{
-		ID:               1,
+		ID:               "1",
 		Title:            "zzz", // Title is now updated, the host should be positioned at the last index
 		Description:      "Mock Host Updated",
 		Address:          "mock_hostname",
@@ -684,7 +746,7 @@ func TestUpdate_HostCreated(t *testing.T) {
 	require.Equal(t, "Mock Host 1", lm.Items()[0].(ListItemHost).Title())
 
 	createdHost1 := host.Host{
-		ID:               999,
+		ID:               "999",
 		Title:            "AAA new host", // Should be positioned first
 		Description:      "Mock Host Updated",
 		Address:          "mock_hostname",
@@ -700,7 +762,7 @@ func TestUpdate_HostCreated(t *testing.T) {
 
 	// Also check that host is inserted into a correct position of the hostlist model
 	createdHost2 := host.Host{
-		ID:               666,
+		ID:               "666",
 		Title:            "ZZZ new host", // Should be positioned at last index
 		Description:      "Mock Host Updated",
 		Address:          "mock_hostname",
@@ -740,6 +802,47 @@ func TestUpdate_GroupListSelectItem(t *testing.T) {
 	require.Equal(t, list.Unfiltered, model.FilterState())
 }
 
+func TestUpdate_TagSelect(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].Tags = []string{"prod", "db"}
+	storage.Hosts[1].Tags = []string{"prod", "web"}
+	appState := &state.State{}
+	model := New(context.TODO(), storage, appState, &mocklogger.Logger{})
+	model.loadHosts()
+	require.Len(t, model.Items(), 3)
+
+	// Hosts with at least one of the tags.
+	model.Update(message.TagSelect{Tags: []string{"db", "web"}})
+	require.Len(t, model.Items(), 2)
+	require.Equal(t, []string{"db", "web"}, appState.TagFilter)
+	require.Equal(t, "tags db or web", utils.StripStyles(model.Title))
+
+	// Hosts with all of the tags.
+	model.Update(message.TagSelect{Tags: []string{"PROD", "db"}, MatchAll: true})
+	require.Len(t, model.Items(), 1)
+	require.Equal(t, storage.Hosts[0].ID, model.Items()[0].(ListItemHost).ID) //nolint:errcheck // always ListItemHost
+	require.True(t, appState.TagFilterMatchAll)
+	model.Update(message.HideUINotification{ComponentName: "hostlist"})
+	require.Contains(t, utils.StripStyles(model.Title), "#PROD&db")
+
+	model.Update(message.TagSelect{})
+	require.Len(t, model.Items(), 3)
+	require.Equal(t, "display all hosts", utils.StripStyles(model.Title))
+}
+
+func TestListModel_loadHosts_HiddenAndPinned(t *testing.T) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].Hidden = true
+	storage.Hosts[2].Pinned = true
+	model := New(context.TODO(), storage, &state.State{}, &mocklogger.Logger{})
+	model.loadHosts()
+
+	// Hidden host is not displayed, pinned host goes first.
+	require.Len(t, model.Items(), 2)
+	require.Equal(t, storage.Hosts[2].ID, model.Items()[0].(ListItemHost).ID) //nolint:errcheck // always ListItemHost
+	require.Equal(t, storage.Hosts[1].ID, model.Items()[1].(ListItemHost).ID) //nolint:errcheck // always ListItemHost
+}
+
 func TestUpdate_msgHideNotification(t *testing.T) {
 	// Test that title resets back to normal when hiding notification
 	model := New(
@@ -875,6 +978,34 @@ func Test_handleKeyboardEvent_selectGroup(t *testing.T) {
 	require.IsType(t, message.ViewGroupListOpen{}, res)
 }
 
+func Test_handleKeyboardEvent_showPatterns(t *testing.T) {
+	model := newMockListModel(false)
+	model.Init()
+	_, cmd := model.Update(tea.KeyPressMsg{Code: 'p'})
+	res := cmd()
+	require.IsType(t, message.ViewPatternListOpen{}, res)
+}
+
+func Test_handleKeyboardEvent_showTunnels(t *testing.T) {
+	model := newMockListModel(false)
+	model.Init()
+	_, cmd := model.Update(tea.KeyPressMsg{Code: 't', ShiftedCode: 'T', Text: "T", Mod: tea.ModShift})
+	res := cmd()
+	require.IsType(t, message.ViewTunnelListOpen{}, res)
+}
+
+func Test_handleKeyboardEvent_showDetails(t *testing.T) {
+	model := newMockListModel(false)
+	model.Init()
+	_, cmd := model.Update(tea.KeyPressMsg{Code: 's'})
+	var msgs []tea.Msg
+	testutils.CmdToMessage(cmd, &msgs)
+	require.IsType(t, message.ViewHostDetailsOpen{}, msgs[0])
+	require.Equal(t, "Mock Host 1", msgs[0].(message.ViewHostDetailsOpen).Host.Title)
+	// Config is loaded again, so that the view displays an error if ssh fails.
+	require.IsType(t, message.RunProcessSSHLoadConfig{}, msgs[1])
+}
+
 func Test_handleKeyboardEvent_connect(t *testing.T) {
 	// Check that when we press Enter button while host is selected
 	// we dispatch processConstruct command from the host list model
@@ -938,6 +1069,43 @@ func Test_handleKeyboardEvent_remove(t *testing.T) {
 	require.Equal(t, modeRemoveItem, model.mode)
 }
 
+func Test_handleKeyboardEvent_runCommand(t *testing.T) {
+	model := newMockListModel(false)
+	model.Init()
+	require.Equal(t, "Mock Host 1", model.SelectedItem().(ListItemHost).Title())
+
+	model.Update(tea.KeyPressMsg{Code: '!', Text: "!"})
+	require.Equal(t, modeRunCommand, model.mode)
+	for _, r := range "htop" {
+		model.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+
+	require.Equal(t, "run on \"Mock Host 1\": htop", utils.StripStyles(model.Title))
+
+	// Quotes cannot be passed to ssh, the command is not run.
+	model.Update(tea.KeyPressMsg{Code: '"', Text: "\""})
+	require.Contains(t, utils.StripStyles(model.Title), "must not contain quotes")
+	_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Nil(t, cmd)
+	require.Equal(t, modeRunCommand, model.mode)
+
+	model.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	_, cmd = model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Equal(t, modeDefault, model.mode)
+	msg, ok := cmd().(message.RunProcessSSHConnect)
+	require.True(t, ok)
+	require.Equal(t, "htop", msg.Host.RemoteCommand)
+	require.Equal(t, "1", msg.Host.ID)
+	// The command is not saved to the host.
+	require.Empty(t, model.SelectedItem().(ListItemHost).RemoteCommand)
+
+	// Escape key cancels the command.
+	model.Update(tea.KeyPressMsg{Code: '!', Text: "!"})
+	model.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	require.Equal(t, modeDefault, model.mode)
+	require.NotContains(t, utils.StripStyles(model.Title), "run on")
+}
+
 func Test_handleKeyboardEvent_edit(t *testing.T) {
 	t.Skip("In progress")
 }
@@ -966,7 +1134,7 @@ func TestUpdate_SearchFunctionOfInnerModelIsNotRegressed(t *testing.T) {
 	// "Mock Host 2"
 	// "Mock Host 3"
 	storage := testutils.NewMockStorage(false)
-	fakeAppState := state.State{Selected: 1}
+	fakeAppState := state.State{Selected: "1"}
 
 	// Create model
 	model := New(context.TODO(), storage, &fakeAppState, &mocklogger.Logger{})
@@ -1004,7 +1172,7 @@ func TestUpdate_ToggleBetweenScreenLayouts(t *testing.T) {
 	// "Mock Host 2"
 	// "Mock Host 3"
 	storage := testutils.NewMockStorage(false)
-	fakeAppState := state.State{Selected: 1}
+	fakeAppState := state.State{Selected: "1"}
 
 	// Create model
 	model := New(context.TODO(), storage, &fakeAppState, &mocklogger.Logger{})
@@ -1049,7 +1217,7 @@ func TestUpdate_HostFocusPreservedAfterClearFilterMessage(t *testing.T) {
 	// "Mock Host 2"
 	// "Mock Host 3"
 	storage := testutils.NewMockStorage(false)
-	fakeAppState := state.State{Selected: 1}
+	fakeAppState := state.State{Selected: "1"}
 
 	// Create model
 	model := New(context.TODO(), storage, &fakeAppState, &mocklogger.Logger{})
@@ -1089,7 +1257,7 @@ func TestUpdate_HostFocusPreservedAfterClearFilterMessage(t *testing.T) {
 
 func newMockListModel(storageShouldFail bool) *ListModel {
 	storage := testutils.NewMockStorage(storageShouldFail)
-	mockState := state.State{Selected: 1}
+	mockState := state.State{Selected: "1"}
 
 	// Create listModel using constructor function (using 'New' is important to preserve hotkeys)
 	lm := New(context.TODO(), storage, &mockState, &mocklogger.Logger{})
@@ -1105,3 +1273,102 @@ func newMockListModel(storageShouldFail bool) *ListModel {
 
 	return lm
 }
+
+func TestUpdate_HostListReload_PreservesFilterAndSelection(t *testing.T) {
+	model := newMockListModel(false)
+	model.Init()
+	storage := model.repo.(*testutils.MockStorage) //nolint:errcheck // always MockStorage in tests
+
+	// Filter hosts by '2' and accept the results, so that only "Mock Host 2" is visible
+	model.Update(tea.KeyPressMsg{Code: '/'})
+	_, cmds := model.Update(tea.KeyPressMsg{Text: "2"})
+	msgs := []tea.Msg{}
+	testutils.CmdToMessage(cmds, &msgs)
+	for _, m := range msgs {
+		model.Update(m)
+	}
+
+	model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Equal(t, list.FilterApplied, model.FilterState())
+	require.Len(t, model.VisibleItems(), 1)
+	model.appState.Selected = model.SelectedItem().(ListItemHost).ID
+
+	// Hosts file is modified outside of the app: a new host which matches the filter is added
+	_, err := storage.Save(host.NewHost("", "Mock Host 22", "", "localhost", "", "", ""))
+	require.NoError(t, err)
+
+	model.Update(message.HostListReload{})
+
+	require.Equal(t, list.FilterApplied, model.FilterState())
+	require.Len(t, model.Items(), 4)
+	require.Len(t, model.VisibleItems(), 2)
+	require.Equal(t, "Mock Host 2", model.SelectedItem().(ListItemHost).Title())
+}
+
+type mockRefreshableStorage struct {
+	*testutils.MockStorage
+	refreshed    bool
+	offlineSince time.Time
+}
+
+func (s *mockRefreshableStorage) Refresh() {
+	s.refreshed = true
+}
+
+func (s *mockRefreshableStorage) OfflineSince() (time.Time, bool) {
+	return s.offlineSince, !s.offlineSince.IsZero()
+}
+
+func Test_handleKeyboardEvent_refresh(t *testing.T) {
+	model := newMockListModel(false)
+	storage := &mockRefreshableStorage{MockStorage: model.repo.(*testutils.MockStorage)} //nolint:errcheck // always MockStorage in tests
+	model.repo = storage
+
+	_, cmd := model.Update(tea.KeyPressMsg{Code: 'r', Text: "r"})
+	require.True(t, storage.refreshed)
+	require.Equal(t, "host list refreshed", utils.StripStyles(model.Title))
+	// Main model drops cached ssh configs.
+	var msgs []tea.Msg
+	testutils.CmdToMessage(cmd, &msgs)
+	require.Contains(t, msgs, message.HostListRefresh{})
+
+	// Cached copies of remote ssh_config files are used.
+	storage.offlineSince = time.Now().Add(-2 * time.Hour)
+	model.updateTitle()
+	require.Equal(t, "ssh -i id_rsa -p 2222 -l root localhost (offline, data is 2h old)", utils.StripStyles(model.Title))
+}
+
+type mockWarningStorage struct {
+	*testutils.MockStorage
+	warnings []string
+}
+
+func (s *mockWarningStorage) Warnings() []string {
+	return s.warnings
+}
+
+func Test_loadHosts_Warnings(t *testing.T) {
+	storage := &mockWarningStorage{MockStorage: testutils.NewMockStorage(false)}
+	model := New(context.TODO(), storage, &state.State{}, &mocklogger.Logger{})
+
+	// No warnings - title is not changed.
+	model.loadHosts()
+	require.Len(t, model.Items(), 3)
+	require.NotContains(t, utils.StripStyles(model.Title), "ignored")
+
+	storage.warnings = []string{`host "a" from b.yaml is ignored, its id is used in c.yaml`}
+	model.loadHosts()
+	require.Equal(t, `host "a" from b.yaml is ignored, its id is used in c.yaml`, utils.StripStyles(model.Title))
+
+	storage.warnings = append(storage.warnings, "second warning", "third warning")
+	model.loadHosts()
+	require.Equal(t, `host "a" from b.yaml is ignored, its id is used in c.yaml, 2 more warnings in the log file`,
+		utils.StripStyles(model.Title))
+}
+
+func Test_formatAge(t *testing.T) {
+	require.Equal(t, "<1m", formatAge(30*time.Second))
+	require.Equal(t, "5m", formatAge(5*time.Minute))
+	require.Equal(t, "23h", formatAge(23*time.Hour+59*time.Minute))
+	require.Equal(t, "3d", formatAge(80*time.Hour))
+}
@@ -2,10 +2,10 @@ package hostlist
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 
-	"github.com/grafviktor/goto/internal/constant"
 	"github.com/grafviktor/goto/internal/model/host"
 )
 
@@ -21,17 +21,30 @@ func (l ListItemHost) Title() string { return l.Host.Title }
 func (l ListItemHost) Description() string { return l.Host.Description }
 
 // FilterValue - returns the field combination which are used when user performs a search in the list.
+// ssh_config directives are included, so that user can find, for instance, all hosts behind a bastion.
 func (l ListItemHost) FilterValue() string {
-	return fmt.Sprintf("%s\n%s\n%s",
+	directives := lo.Map(l.Host.Directives, func(d host.Directive, _ int) string {
+		return d.Keyword + " " + d.Value
+	})
+
+	return fmt.Sprintf("%s\n%s\n%s\n%s\n%s\n%s\n%s",
 		l.Host.Title,
 		l.Host.Address,
-		l.Host.Description)
+		l.Host.Description,
+		strings.Join(l.Host.Aliases, " "),
+		strings.Join(l.Host.Tags, " "),
+		l.Host.Note,
+		strings.Join(directives, "\n"))
 }
 
-// CompareTo - compares this listItemHost with another one.
+// CompareTo - compares this listItemHost with another one. Pinned hosts go first.
 func (l ListItemHost) CompareTo(host ListItemHost) int {
+	if l.Host.Pinned != host.Pinned {
+		return lo.Ternary(l.Host.Pinned, -1, 1)
+	}
+
 	if l.Host.Title == host.Title() {
-		return l.Host.ID - host.ID
+		return strings.Compare(l.Host.ID, host.ID)
 	}
 
 	return lo.Ternary(l.Host.Title < host.Title(), -1, 1)
@@ -39,5 +52,5 @@ func (l ListItemHost) CompareTo(host ListItemHost) int {
 
 // ReadOnly - returns boolean. Overall, it is used to determine if user can edit host details.
 func (l ListItemHost) ReadOnly() bool {
-	return l.Host.StorageType == constant.HostStorageType.SSHConfig
+	return l.Host.IsReadOnly()
 }
@@ -3,6 +3,8 @@ package hostlist
 import (
 	"testing"
 
+	"charm.land/bubbles/v2/list"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafviktor/goto/internal/constant"
@@ -12,6 +14,7 @@ import (
 func Test_CompareTo(t *testing.T) {
 	testCases := []struct {
 		storageType constant.HostStorageEnum
+		sourcePath  string
 		readonly    bool
 	}{
 		{
@@ -20,15 +23,22 @@ func Test_CompareTo(t *testing.T) {
 		},
 		{
 			storageType: constant.HostStorageType.SSHConfig,
+			sourcePath:  "/home/user/.ssh/config",
+			readonly:    false,
+		},
+		{
+			storageType: constant.HostStorageType.SSHConfig,
+			sourcePath:  "https://example.com/ssh_config",
 			readonly:    true,
 		},
 	}
 
 	for _, tc := range testCases {
-		t.Run(string(tc.storageType), func(t *testing.T) {
+		t.Run(string(tc.storageType)+tc.sourcePath, func(t *testing.T) {
 			item := ListItemHost{
 				Host: host.Host{
 					StorageType: tc.storageType,
+					SourcePath:  tc.sourcePath,
 				},
 			}
 
@@ -40,33 +50,33 @@ func Test_CompareTo(t *testing.T) {
 func Test_ReadOnly(t *testing.T) {
 	testCases := []struct {
 		name     string
-		id1      int
-		id2      int
+		id1      string
+		id2      string
 		title1   string
 		title2   string
 		expected int
 	}{
 		{
 			name:     "Different titles, different IDs",
-			id1:      1,
+			id1:      "1",
 			title1:   "Alpha",
-			id2:      2,
+			id2:      "2",
 			title2:   "Beta",
 			expected: -1,
 		},
 		{
 			name:     "Same titles, different IDs (1 < 2)",
-			id1:      1,
+			id1:      "1",
 			title1:   "Alpha",
-			id2:      2,
+			id2:      "2",
 			title2:   "Alpha",
 			expected: -1,
 		},
 		{
 			name:     "Same titles, different IDs (2 < 1)",
-			id1:      2,
+			id1:      "2",
 			title1:   "Alpha",
-			id2:      1,
+			id2:      "1",
 			title2:   "Alpha",
 			expected: 1,
 		},
@@ -92,3 +102,40 @@ func Test_ReadOnly(t *testing.T) {
 		})
 	}
 }
+
+func Test_FilterValue(t *testing.T) {
+	web := ListItemHost{Host: host.Host{
+		Title:      "web1",
+		Address:    "10.0.0.1",
+		Aliases:    []string{"web1.internal"},
+		Directives: []host.Directive{{Keyword: "ProxyJump", Value: "bastion"}},
+	}}
+	db := ListItemHost{Host: host.Host{Title: "db", Address: "10.0.0.2"}}
+	filterValues := []string{web.FilterValue(), db.FilterValue()}
+
+	// Hosts can be found by aliases and by ssh_config directives.
+	require.Equal(t, []int{0}, lo.Map(hostListFilter("web1.internal", filterValues), rankIndex))
+	require.Equal(t, []int{0}, lo.Map(hostListFilter("bastion", filterValues), rankIndex))
+	require.Equal(t, []int{0}, lo.Map(hostListFilter("proxyjump bastion", filterValues), rankIndex))
+	require.Empty(t, hostListFilter("jumphost", filterValues))
+
+	// Hosts can be found by tags and notes, which are read from ssh_config metadata.
+	db.Tags = []string{"prod", "postgres"}
+	db.Note = "Restart only on weekends"
+	filterValues = []string{web.FilterValue(), db.FilterValue()}
+	require.Equal(t, []int{1}, lo.Map(hostListFilter("postgres", filterValues), rankIndex))
+	require.Equal(t, []int{1}, lo.Map(hostListFilter("weekends", filterValues), rankIndex))
+}
+
+func Test_CompareTo_Pinned(t *testing.T) {
+	alpha := ListItemHost{Host: host.Host{ID: "1", Title: "alpha"}}
+	zulu := ListItemHost{Host: host.Host{ID: "2", Title: "zulu", Pinned: true}}
+
+	// Pinned hosts go first, regardless of the title.
+	require.Equal(t, 1, alpha.CompareTo(zulu))
+	require.Equal(t, -1, zulu.CompareTo(alpha))
+}
+
+func rankIndex(rank list.Rank, _ int) int {
+	return rank.Index
+}
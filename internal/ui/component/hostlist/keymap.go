@@ -22,13 +22,19 @@ type keyMap struct {
 	cursorUp     key.Binding
 	cursorDown   key.Binding
 	selectGroup  key.Binding
+	selectTags   key.Binding
+	showPatterns key.Binding
+	showTunnels  key.Binding
+	showDetails  key.Binding
 	connect      key.Binding
+	runCommand   key.Binding
 	copyID       key.Binding
 	append       key.Binding
 	clone        key.Binding
 	edit         key.Binding
 	remove       key.Binding
 	toggleLayout key.Binding
+	refresh      key.Binding
 	confirm      key.Binding
 	keyMapState  keyMapStateEnum
 }
@@ -47,10 +53,30 @@ func newDelegateKeyMap() *keyMap {
 			key.WithKeys("z"),
 			key.WithHelp("z", "group"),
 		),
+		selectTags: key.NewBinding(
+			key.WithKeys("#"),
+			key.WithHelp("#", "tags"),
+		),
+		showDetails: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "ssh settings"),
+		),
+		showPatterns: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", "patterns"),
+		),
+		showTunnels: key.NewBinding(
+			key.WithKeys("T"),
+			key.WithHelp("T", "tunnels"),
+		),
 		connect: key.NewBinding(
 			key.WithKeys("enter"),
 			key.WithHelp("↩", "connect"),
 		),
+		runCommand: key.NewBinding(
+			key.WithKeys("!"),
+			key.WithHelp("!", "run command"),
+		),
 		append: key.NewBinding(
 			key.WithKeys("i", "n", "insert"),
 			key.WithHelp("i/n", "new"),
@@ -75,6 +101,10 @@ func newDelegateKeyMap() *keyMap {
 			key.WithKeys("v"),
 			key.WithHelp("v", "toggle view"),
 		),
+		refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
 		confirm: key.NewBinding(
 			key.WithKeys("y", "Y"),
 			key.WithHelp("y", "confirm"),
@@ -97,7 +127,9 @@ func (k *keyMap) keysForReadonlyHost() {
 		k.keyMapState = keyMapState.EditkeysPartiallyShown
 		k.clone.SetEnabled(false)
 		k.connect.SetEnabled(true)
+		k.runCommand.SetEnabled(true)
 		k.copyID.SetEnabled(true)
+		k.showDetails.SetEnabled(true)
 		k.cursorDown.SetEnabled(true)
 		k.cursorUp.SetEnabled(true)
 		k.edit.SetEnabled(true)
@@ -117,11 +149,13 @@ func (k *keyMap) keysForWritableHost() {
 func (k *keyMap) keysSetEnabled(val bool) {
 	k.clone.SetEnabled(val)
 	k.connect.SetEnabled(val)
+	k.runCommand.SetEnabled(val)
 	k.cursorDown.SetEnabled(val)
 	k.cursorUp.SetEnabled(val)
 	k.edit.SetEnabled(val)
 	k.remove.SetEnabled(val)
 	k.copyID.SetEnabled(val)
+	k.showDetails.SetEnabled(val)
 }
 
 func (k *keyMap) UpdateKeyVisibility(item list.Item) string {
@@ -156,12 +190,18 @@ func (k *keyMap) ShortHelp() []key.Binding {
 func (k *keyMap) FullHelp() []key.Binding {
 	return []key.Binding{
 		k.connect,
+		k.runCommand,
 		k.append,
 		k.clone,
 		k.edit,
 		k.remove,
 		k.selectGroup,
+		k.selectTags,
+		k.showPatterns,
+		k.showTunnels,
 		k.copyID,
+		k.showDetails,
 		k.toggleLayout,
+		k.refresh,
 	}
 }
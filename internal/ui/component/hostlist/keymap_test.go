@@ -24,7 +24,10 @@ func Test_keyMap_UpdateKeyVisibility(t *testing.T) {
 	require.Equal(t, keyMapState.EditkeysHidden, km.keyMapState)
 
 	// Case 2: item is ListItemHost and IsReadOnly() == true
-	readonlyHost := ListItemHost{Host: host.Host{StorageType: constant.HostStorageType.SSHConfig}}
+	readonlyHost := ListItemHost{Host: host.Host{
+		StorageType: constant.HostStorageType.SSHConfig,
+		SourcePath:  "https://example.com/ssh_config",
+	}}
 	state = km.UpdateKeyVisibility(readonlyHost)
 	require.Equal(t, string(keyMapState.EditkeysPartiallyShown), state)
 	require.Equal(t, keyMapState.EditkeysPartiallyShown, km.keyMapState)
@@ -35,6 +38,17 @@ func Test_keyMap_UpdateKeyVisibility(t *testing.T) {
 	require.Equal(t, string(keyMapState.EditkeysShown), state)
 	require.Equal(t, keyMapState.EditkeysShown, km.keyMapState)
 
+	// Case 3a: host loaded from a local ssh_config file is writable
+	km.UpdateKeyVisibility(readonlyHost)
+	localSSHConfigHost := ListItemHost{Host: host.Host{
+		StorageType: constant.HostStorageType.SSHConfig,
+		SourcePath:  "/home/user/.ssh/config",
+	}}
+	state = km.UpdateKeyVisibility(localSSHConfigHost)
+	require.Equal(t, string(keyMapState.EditkeysShown), state)
+	require.True(t, km.clone.Enabled())
+	require.True(t, km.remove.Enabled())
+
 	// Case 4: item is not ListItemHost but implements list.Item
 	state = km.UpdateKeyVisibility(dummyItem{})
 	require.Equal(t, string(keyMapState.EditkeysHidden), state)
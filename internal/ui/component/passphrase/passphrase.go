@@ -0,0 +1,80 @@
+// Package passphrase implements a UI component which asks user for a passphrase.
+package passphrase
+
+import (
+	"fmt"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+)
+
+// Model - passphrase prompt UI component. It's running as a standalone bubbletea program,
+// therefore it quits when user submits a valid passphrase or cancels the input.
+type Model struct {
+	input     textinput.Model
+	title     string
+	validate  func(string) error
+	err       error
+	submitted bool
+	styles    styles
+}
+
+// New - creates passphrase prompt. validate is called when user presses Enter, if it returns
+// an error, the error is displayed and user is asked to enter the passphrase again.
+func New(title string, validate func(string) error) *Model {
+	styles := defaultStyles()
+	input := textinput.New()
+	input.Prompt = "> "
+	input.EchoMode = textinput.EchoPassword
+	input.EchoCharacter = '*'
+	s := textinput.DefaultStyles(true)
+	s.Focused.Text = styles.textFocused
+	s.Cursor.Color = styles.cursor.GetForeground()
+	input.SetStyles(s)
+
+	return &Model{
+		input:    input,
+		title:    title,
+		validate: validate,
+		styles:   styles,
+	}
+}
+
+func (m *Model) Init() tea.Cmd {
+	return m.input.Focus()
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyPressMsg); ok {
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			return m, tea.Quit
+		case "enter":
+			if m.err = m.validate(m.input.Value()); m.err != nil {
+				m.input.Reset()
+				return m, nil
+			}
+
+			m.submitted = true
+			return m, tea.Quit
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) View() tea.View {
+	content := fmt.Sprintf("%s\n\n%s\n", m.styles.textNormal.Render(m.title), m.input.View())
+	if m.err != nil {
+		content += "\n" + m.styles.inputError.Render(m.err.Error())
+	}
+
+	return tea.NewView(m.styles.componentMargins.Render(content))
+}
+
+// Value - returns the passphrase, if it was submitted.
+func (m *Model) Value() (string, bool) {
+	return m.input.Value(), m.submitted
+}
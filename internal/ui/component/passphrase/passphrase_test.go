@@ -0,0 +1,50 @@
+package passphrase
+
+import (
+	"errors"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func typeText(m *Model, text string) {
+	for _, r := range text {
+		m.Update(tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+}
+
+func TestModel_Submit(t *testing.T) {
+	m := New("Enter passphrase", func(value string) error {
+		if value != "secret" {
+			return errors.New("wrong passphrase")
+		}
+
+		return nil
+	})
+	m.Init()
+
+	typeText(m, "wrong")
+	require.NotContains(t, m.View().Content, "wrong", "passphrase must be masked")
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Nil(t, cmd, "should not quit when validation fails")
+	require.Contains(t, m.View().Content, "wrong passphrase")
+	_, ok := m.Value()
+	require.False(t, ok)
+
+	typeText(m, "secret")
+	_, cmd = m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.IsType(t, tea.QuitMsg{}, cmd())
+	value, ok := m.Value()
+	require.True(t, ok)
+	require.Equal(t, "secret", value)
+}
+
+func TestModel_Cancel(t *testing.T) {
+	m := New("Enter passphrase", func(_ string) error { return nil })
+	typeText(m, "secret")
+	_, cmd := m.Update(tea.KeyPressMsg{Code: tea.KeyEscape})
+	require.IsType(t, tea.QuitMsg{}, cmd())
+	_, ok := m.Value()
+	require.False(t, ok)
+}
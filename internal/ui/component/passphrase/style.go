@@ -0,0 +1,29 @@
+package passphrase
+
+import (
+	"charm.land/lipgloss/v2"
+
+	"github.com/grafviktor/goto/internal/ui/theme"
+)
+
+type styles struct {
+	cursor      lipgloss.Style
+	inputError  lipgloss.Style
+	textFocused lipgloss.Style
+	textNormal  lipgloss.Style
+
+	// Margins for the whole UI component.
+	componentMargins lipgloss.Style
+}
+
+func defaultStyles() styles {
+	themeSettings := theme.Get().Styles.Input
+
+	return styles{
+		componentMargins: lipgloss.NewStyle().Margin(1, 2), //nolint:mnd // magic nums are OK for styles
+		cursor:           themeSettings.Cursor,
+		inputError:       themeSettings.InputError,
+		textFocused:      themeSettings.TextFocused,
+		textNormal:       themeSettings.TextNormal,
+	}
+}
@@ -0,0 +1,43 @@
+package patternlist
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// ListItemPattern is an adaptor between ssh_config wildcard block and bubbletea list model.
+type ListItemPattern struct {
+	host.PatternBlock
+}
+
+// Title - returns "Host" line of the block, or "global options" for options which are declared
+// before the first "Host" line.
+func (l ListItemPattern) Title() string {
+	if utils.StringEmpty(&l.Patterns) {
+		return "global options"
+	}
+
+	return "Host " + l.Patterns
+}
+
+// Description - returns the place where the block is declared, and its options.
+func (l ListItemPattern) Description() string {
+	return fmt.Sprintf("%s:%d  %s", utils.RedactURL(l.SourcePath), l.Line, l.options())
+}
+
+// FilterValue - returns the field combination which are used when user performs a search in the list.
+func (l ListItemPattern) FilterValue() string {
+	return l.Title() + " " + l.options()
+}
+
+func (l ListItemPattern) options() string {
+	options := make([]string, 0, len(l.Directives))
+	for _, d := range l.Directives {
+		options = append(options, d.Keyword+" "+d.Value)
+	}
+
+	return strings.Join(options, "; ")
+}
@@ -0,0 +1,129 @@
+// Package patternlist implements the view, which lists wildcard "Host" blocks from ssh_config, for instance
+// "Host *" or "Host *.prod". Such blocks are not displayed in the host list, but their options apply to hosts.
+package patternlist
+
+import (
+	"context"
+
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/grafviktor/goto/internal/storage"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+type iLogger interface {
+	Debug(format string, args ...any)
+	Info(format string, args ...any)
+	Error(format string, args ...any)
+}
+
+type Model struct {
+	list.Model
+
+	repo   storage.HostStorage
+	logger iLogger
+	styles styles
+}
+
+// New - creates a new UI component which displays wildcard ssh_config blocks.
+func New(_ context.Context, repo storage.HostStorage, log iLogger) *Model {
+	styles := defaultStyles()
+
+	var listItems []list.Item
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles = styles.listDelegate
+
+	model := list.New(listItems, delegate, 0, 0)
+	model.DisableQuitKeybindings() // We don't want to quit the app from this view.
+	model.SetStatusBarItemName("block", "blocks")
+
+	// Setup filter input styles.
+	filterStyles := model.FilterInput.Styles()
+	filterStyles.Focused.Prompt = styles.prompt
+	filterStyles.Focused.Text = styles.filterInput
+	model.FilterInput.SetStyles(filterStyles)
+
+	// Setup model styles.
+	model.Styles = styles.list
+	model.Paginator.ActiveDot = styles.paginatorActiveDot
+	model.Paginator.InactiveDot = styles.paginatorInactiveDot
+	model.Help.Styles = styles.help
+
+	m := Model{
+		Model:  model,
+		repo:   repo,
+		logger: log,
+		styles: styles,
+	}
+
+	m.Title = "ssh_config patterns"
+
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := m.styles.componentMargins.GetFrameSize()
+		m.SetSize(msg.Width-h, msg.Height-v)
+		m.logger.Debug("[UI] Set pattern list size: %d %d", m.Width(), m.Height())
+		return m, nil
+	case tea.KeyPressMsg:
+		cmd = m.handleKeyboardEvent(msg)
+		cmds = append(cmds, cmd)
+	case message.ViewPatternListOpen:
+		return m, m.loadItems()
+	}
+
+	m.Model, cmd = m.Model.Update(msg)
+	// Only calculate status bar visibility AFTER the model is updated.
+	m.SetShowStatusBar(m.FilterState() != list.Unfiltered)
+
+	return m, tea.Batch(append(cmds, cmd)...)
+}
+
+func (m *Model) View() tea.View {
+	return tea.NewView(m.styles.componentMargins.Render(m.Model.View()))
+}
+
+func (m *Model) handleKeyboardEvent(msg tea.KeyPressMsg) tea.Cmd {
+	//exhaustive:ignore // Handle only specific keys, other events are handled by the list model.
+	switch msg.Code {
+	case tea.KeyEscape:
+		// If model is in filter mode and press ESC, just disable filtering.
+		if m.FilterState() == list.Filtering || m.FilterState() == list.FilterApplied {
+			m.logger.Debug("[UI] Escape key. Deactivate filter in pattern list view.")
+			return nil
+		}
+
+		m.logger.Debug("[UI] Escape key. Exit from pattern list view.")
+		return message.TeaCmd(message.ViewPatternListClose{})
+	}
+
+	return nil
+}
+
+func (m *Model) loadItems() tea.Cmd {
+	lister, ok := m.repo.(storage.PatternLister)
+	if !ok {
+		m.logger.Debug("[UI] Storage does not contain ssh_config patterns")
+		return m.SetItems(nil)
+	}
+
+	// Patterns are read together with hosts, they're up to date, because the host list is loaded first.
+	patterns := lister.Patterns()
+	m.logger.Debug("[UI] Load complete. Found '%d' ssh_config patterns", len(patterns))
+
+	items := make([]list.Item, 0, len(patterns))
+	for _, pattern := range patterns {
+		items = append(items, ListItemPattern{pattern})
+	}
+
+	return m.SetItems(items)
+}
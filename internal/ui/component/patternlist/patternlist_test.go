@@ -0,0 +1,93 @@
+package patternlist
+
+import (
+	"context"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	testutils "github.com/grafviktor/goto/internal/testutils"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+type mockPatternStorage struct {
+	*testutils.MockStorage
+	patterns []host.PatternBlock
+}
+
+func (s *mockPatternStorage) Patterns() []host.PatternBlock {
+	return s.patterns
+}
+
+func TestNew(t *testing.T) {
+	model := New(context.TODO(), testutils.NewMockStorage(false), &mocklogger.Logger{})
+	require.True(t, model.FilteringEnabled())
+	// Quit app keys is disabled
+	require.False(t, model.KeyMap.Quit.Enabled())
+	require.Equal(t, "ssh_config patterns", model.Title)
+}
+
+func TestLoadItems(t *testing.T) {
+	model := newMockPatternModel()
+	model.Update(message.ViewPatternListOpen{})
+	require.Len(t, model.Items(), 2)
+
+	global := model.Items()[0].(ListItemPattern)
+	require.Equal(t, "global options", global.Title())
+	require.Equal(t, "/home/user/.ssh/config:1  ServerAliveInterval 60", global.Description())
+
+	prod := model.Items()[1].(ListItemPattern)
+	require.Equal(t, "Host *.prod !db.prod", prod.Title())
+	require.Equal(t, "/home/user/.ssh/config:12  User deploy; IdentityFile ~/.ssh/prod", prod.Description())
+
+	// Storage without ssh_config does not have patterns.
+	model = New(context.TODO(), testutils.NewMockStorage(false), &mocklogger.Logger{})
+	model.Update(message.ViewPatternListOpen{})
+	require.Empty(t, model.Items())
+}
+
+func Test_handleEscapeKey(t *testing.T) {
+	model := newMockPatternModel()
+	model.Update(message.ViewPatternListOpen{})
+	// Escape in filter mode only deactivates the filter.
+	model.Update(tea.KeyPressMsg{Code: '/'})
+	require.True(t, model.SettingFilter())
+	_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	require.Nil(t, cmd)
+
+	_, cmd = model.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	var actualMsgs []tea.Msg
+	testutils.CmdToMessage(cmd, &actualMsgs)
+	require.Equal(t, []tea.Msg{message.ViewPatternListClose{}}, actualMsgs)
+}
+
+// ==============================================
+// ============== utility methods ===============
+// ==============================================
+
+func newMockPatternModel() *Model {
+	storage := &mockPatternStorage{
+		MockStorage: testutils.NewMockStorage(false),
+		patterns: []host.PatternBlock{
+			{
+				SourcePath: "/home/user/.ssh/config",
+				Line:       1,
+				Directives: []host.Directive{{Keyword: "ServerAliveInterval", Value: "60"}},
+			},
+			{
+				Patterns:   "*.prod !db.prod",
+				SourcePath: "/home/user/.ssh/config",
+				Line:       12,
+				Directives: []host.Directive{
+					{Keyword: "User", Value: "deploy"},
+					{Keyword: "IdentityFile", Value: "~/.ssh/prod"},
+				},
+			},
+		},
+	}
+
+	return New(context.TODO(), storage, &mocklogger.Logger{})
+}
@@ -0,0 +1,28 @@
+package taglist
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+)
+
+// ListItemTag is an adaptor between host tag and bubbletea list model. Hosts is the number of hosts
+// with the tag, and Selected is true when the host list is filtered by the tag.
+type ListItemTag struct {
+	Name     string
+	Hosts    int
+	Selected bool
+}
+
+// Title - returns tag name, selected tags are marked.
+func (l ListItemTag) Title() string {
+	return lo.Ternary(l.Selected, l.Name+" ✓", l.Name)
+}
+
+// Description - returns the number of hosts with the tag.
+func (l ListItemTag) Description() string {
+	return fmt.Sprintf("%d %s", l.Hosts, lo.Ternary(l.Hosts == 1, "host", "hosts"))
+}
+
+// FilterValue - returns the field combination which are used when user performs a search in the list.
+func (l ListItemTag) FilterValue() string { return l.Name }
@@ -0,0 +1,226 @@
+// Package taglist implements the tag list view. User selects one or several tags in this view, and the host
+// list only displays hosts which have all of the selected tags, or at least one of them.
+package taglist
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+	"github.com/samber/lo"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/state"
+	"github.com/grafviktor/goto/internal/storage"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+type iLogger interface {
+	Debug(format string, args ...any)
+	Info(format string, args ...any)
+	Error(format string, args ...any)
+}
+
+type keyMap struct {
+	toggle    key.Binding
+	matchMode key.Binding
+	clear     key.Binding
+	apply     key.Binding
+}
+
+var keys = keyMap{
+	toggle: key.NewBinding(
+		key.WithKeys("space"),
+		key.WithHelp("space", "select"),
+	),
+	matchMode: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "match all/any"),
+	),
+	clear: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "clear"),
+	),
+	apply: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("↩", "apply"),
+	),
+}
+
+type Model struct {
+	list.Model
+
+	repo     storage.HostStorage
+	appState *state.State
+	// selected and matchAll are applied to the host list, when user presses enter, see message.TagSelect.
+	selected []string
+	matchAll bool
+	logger   iLogger
+	styles   styles
+}
+
+// New - creates a new UI component which is used to select tags, the host list is filtered by them.
+func New(_ context.Context, repo storage.HostStorage, appState *state.State, log iLogger) *Model {
+	styles := defaultStyles()
+
+	var listItems []list.Item
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles = styles.listDelegate
+
+	model := list.New(listItems, delegate, 0, 0)
+	model.DisableQuitKeybindings() // We don't want to quit the app from this view.
+	model.SetStatusBarItemName("tag", "tags")
+	model.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.toggle, keys.matchMode, keys.apply}
+	}
+	model.AdditionalFullHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.toggle, keys.matchMode, keys.clear, keys.apply}
+	}
+
+	// Setup filter input styles.
+	filterStyles := model.FilterInput.Styles()
+	filterStyles.Focused.Prompt = styles.prompt
+	filterStyles.Focused.Text = styles.filterInput
+	model.FilterInput.SetStyles(filterStyles)
+
+	// Setup model styles.
+	model.Styles = styles.list
+	model.Paginator.ActiveDot = styles.paginatorActiveDot
+	model.Paginator.InactiveDot = styles.paginatorInactiveDot
+	model.Help.Styles = styles.help
+
+	m := Model{
+		Model:    model,
+		repo:     repo,
+		appState: appState,
+		logger:   log,
+		styles:   styles,
+	}
+
+	m.updateTitle()
+
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := m.styles.componentMargins.GetFrameSize()
+		m.SetSize(msg.Width-h, msg.Height-v)
+		m.logger.Debug("[UI] Set tag list size: %d %d", m.Width(), m.Height())
+		return m, nil
+	case tea.KeyPressMsg:
+		if cmd, handled := m.handleKeyboardEvent(msg); handled {
+			return m, cmd
+		}
+	case message.ViewTagListOpen:
+		// Selection starts from the active tag filter, it's not applied until user presses enter.
+		m.selected = slices.Clone(m.appState.TagFilter)
+		m.matchAll = m.appState.TagFilterMatchAll
+		m.updateTitle()
+		return m, m.loadItems()
+	}
+
+	m.Model, cmd = m.Model.Update(msg)
+	// Only calculate status bar visibility AFTER the model is updated.
+	m.SetShowStatusBar(m.FilterState() != list.Unfiltered)
+
+	return m, cmd
+}
+
+func (m *Model) View() tea.View {
+	return tea.NewView(m.styles.componentMargins.Render(m.Model.View()))
+}
+
+// handleKeyboardEvent - returns true when the key is handled and should not be passed to the list model.
+func (m *Model) handleKeyboardEvent(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if m.FilterState() == list.Filtering {
+		// Let the list model handle the filter input.
+		return nil, false
+	}
+
+	switch {
+	case msg.Code == tea.KeyEscape:
+		// If model is in filter mode and press ESC, just disable filtering.
+		if m.FilterState() == list.FilterApplied {
+			m.logger.Debug("[UI] Escape key. Deactivate filter in tag list view.")
+			return nil, false
+		}
+
+		m.logger.Debug("[UI] Escape key. Exit from tag list view, selection is discarded.")
+		return message.TeaCmd(message.ViewTagListClose{}), true
+	case key.Matches(msg, keys.toggle):
+		return m.toggleTag(), true
+	case key.Matches(msg, keys.matchMode):
+		m.matchAll = !m.matchAll
+		m.logger.Debug("[UI] Match all tags: %v", m.matchAll)
+		m.updateTitle()
+		return nil, true
+	case key.Matches(msg, keys.clear):
+		m.logger.Debug("[UI] Clear selected tags")
+		m.selected = nil
+		return m.loadItems(), true
+	case key.Matches(msg, keys.apply):
+		m.logger.Debug("[UI] Enter key. Select tags %q, match all: %v", m.selected, m.matchAll)
+		return tea.Sequence(
+			message.TeaCmd(message.TagSelect{Tags: slices.Clone(m.selected), MatchAll: m.matchAll}),
+			message.TeaCmd(message.ViewTagListClose{}),
+		), true
+	}
+
+	return nil, false
+}
+
+func (m *Model) toggleTag() tea.Cmd {
+	item, ok := m.SelectedItem().(ListItemTag)
+	if !ok {
+		return nil
+	}
+
+	if item.Selected {
+		m.selected = slices.DeleteFunc(m.selected, func(t string) bool { return strings.EqualFold(t, item.Name) })
+	} else {
+		m.selected = append(m.selected, item.Name)
+	}
+
+	item.Selected = !item.Selected
+	return m.SetItem(m.GlobalIndex(), item)
+}
+
+func (m *Model) updateTitle() {
+	m.Title = fmt.Sprintf("select tags, match %s", lo.Ternary(m.matchAll, "all", "any"))
+}
+
+// loadItems - displays tags of all hosts. Selected tags are displayed even if there are no hosts with them,
+// so that they can be deselected.
+func (m *Model) loadItems() tea.Cmd {
+	m.logger.Debug("[UI] Load tags from the database")
+	hosts, err := m.repo.GetAll()
+	if err != nil {
+		m.logger.Error("[UI] Cannot read database. %v", err)
+		return message.TeaCmd(message.ErrorOccurred{Err: err})
+	}
+
+	// Hidden hosts are not displayed, their tags would be empty.
+	hosts = lo.Reject(hosts, func(h host.Host, _ int) bool { return h.Hidden })
+	tags := host.AllTags(append(hosts, host.Host{Tags: m.selected}))
+	items := make([]list.Item, 0, len(tags))
+	for _, tag := range tags {
+		items = append(items, ListItemTag{
+			Name:     tag,
+			Hosts:    lo.CountBy(hosts, func(h host.Host) bool { return h.HasTag(tag) }),
+			Selected: slices.ContainsFunc(m.selected, func(t string) bool { return strings.EqualFold(t, tag) }),
+		})
+	}
+
+	m.logger.Debug("[UI] Load complete. Found '%d' tags", len(items))
+	return m.SetItems(items)
+}
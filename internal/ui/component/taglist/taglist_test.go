@@ -0,0 +1,111 @@
+package taglist
+
+import (
+	"context"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/state"
+	testutils "github.com/grafviktor/goto/internal/testutils"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+func TestNew(t *testing.T) {
+	model := New(context.TODO(), testutils.NewMockStorage(false), &state.State{}, &mocklogger.Logger{})
+	require.True(t, model.FilteringEnabled())
+	// Quit app keys is disabled
+	require.False(t, model.KeyMap.Quit.Enabled())
+	require.Equal(t, "select tags, match any", model.Title)
+}
+
+func TestLoadItems(t *testing.T) {
+	model, appState := newMockTagModel()
+	// The tag is selected, though there are no hosts with it, so that it can be deselected.
+	appState.TagFilter = []string{"staging"}
+	model.Update(message.ViewTagListOpen{})
+
+	require.Len(t, model.Items(), 4)
+	db := model.Items()[0].(ListItemTag)
+	require.Equal(t, "db", db.Title())
+	require.Equal(t, "1 host", db.Description())
+
+	prod := model.Items()[1].(ListItemTag)
+	require.Equal(t, "prod", prod.Title())
+	require.Equal(t, "2 hosts", prod.Description())
+
+	staging := model.Items()[2].(ListItemTag)
+	require.Equal(t, "staging ✓", staging.Title())
+	require.Equal(t, "0 hosts", staging.Description())
+}
+
+func TestSelectTags(t *testing.T) {
+	model, appState := newMockTagModel()
+	model.Update(message.ViewTagListOpen{})
+
+	// Select "db" and "prod" tags, and match all of them.
+	model.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	model.Update(tea.KeyPressMsg{Code: tea.KeyDown})
+	model.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	require.Equal(t, "prod ✓", model.Items()[1].(ListItemTag).Title())
+	model.Update(tea.KeyPressMsg{Code: 'm', Text: "m"})
+	require.Equal(t, "select tags, match all", model.Title)
+
+	_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	var actualMsgs []tea.Msg
+	testutils.CmdToMessage(cmd, &actualMsgs)
+	require.Equal(t, []tea.Msg{
+		message.TagSelect{Tags: []string{"db", "prod"}, MatchAll: true},
+		message.ViewTagListClose{},
+	}, actualMsgs)
+
+	// The selection is applied by the host list, not by this view.
+	require.Empty(t, appState.TagFilter)
+
+	// Deselect and clear.
+	model.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	require.Equal(t, "prod", model.Items()[1].(ListItemTag).Title())
+	model.Update(tea.KeyPressMsg{Code: 'c', Text: "c"})
+	require.Empty(t, model.selected)
+	require.Equal(t, "db", model.Items()[0].(ListItemTag).Title())
+}
+
+func Test_handleEscapeKey(t *testing.T) {
+	model, appState := newMockTagModel()
+	appState.TagFilter = []string{"db"}
+	model.Update(message.ViewTagListOpen{})
+	// Escape in filter mode only deactivates the filter.
+	model.Update(tea.KeyPressMsg{Code: '/'})
+	require.True(t, model.SettingFilter())
+	_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	require.Nil(t, cmd)
+
+	// Selection is discarded.
+	model.Update(tea.KeyPressMsg{Code: tea.KeySpace, Text: " "})
+	_, cmd = model.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	var actualMsgs []tea.Msg
+	testutils.CmdToMessage(cmd, &actualMsgs)
+	require.Equal(t, []tea.Msg{message.ViewTagListClose{}}, actualMsgs)
+	require.Equal(t, []string{"db"}, appState.TagFilter)
+}
+
+// ==============================================
+// ============== utility methods ===============
+// ==============================================
+
+func newMockTagModel() (*Model, *state.State) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].Tags = []string{"prod", "db"}
+	storage.Hosts[1].Tags = []string{"Prod", "web"}
+	// Tags of hidden hosts are not displayed.
+	storage.Hosts[2].Tags = []string{"bastion"}
+	storage.Hosts[2].Hidden = true
+	appState := &state.State{}
+
+	model := New(context.TODO(), storage, appState, &mocklogger.Logger{})
+	model.SetSize(100, 100)
+
+	return model, appState
+}
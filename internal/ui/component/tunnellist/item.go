@@ -0,0 +1,65 @@
+package tunnellist
+
+import (
+	"fmt"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/tunnel"
+	"github.com/grafviktor/goto/internal/utils"
+)
+
+// ListItemTunnel is an adaptor between host port forwarding and bubbletea list model. Running is nil
+// when the tunnel is stopped. Orphaned tunnels are running, but their host or forwarding is deleted.
+type ListItemTunnel struct {
+	HostID    string
+	HostTitle string
+	Forward   host.Forward
+	Running   *tunnel.Tunnel
+	Orphaned  bool
+	// LastError contains ssh output, when the tunnel has ended unexpectedly.
+	LastError string
+}
+
+// ID - returns tunnel identifier.
+func (l ListItemTunnel) ID() string {
+	return tunnel.ID(l.HostID, l.Forward.Name)
+}
+
+// Title - returns forwarding name and the host title.
+func (l ListItemTunnel) Title() string {
+	return fmt.Sprintf("%s (%s)", l.Forward.Name, l.HostTitle)
+}
+
+// Description - returns the port, which ssh listens to, and the tunnel state.
+func (l ListItemTunnel) Description() string {
+	return fmt.Sprintf("%s  %s", l.forwarding(), l.state())
+}
+
+// FilterValue - returns the field combination which are used when user performs a search in the list.
+func (l ListItemTunnel) FilterValue() string {
+	return l.Title() + " " + l.Forward.Spec
+}
+
+func (l ListItemTunnel) forwarding() string {
+	switch l.Forward.Type {
+	case host.ForwardTypes.Dynamic:
+		return fmt.Sprintf("dynamic %s (SOCKS)", l.Forward.ListenPort())
+	case host.ForwardTypes.Remote:
+		return fmt.Sprintf("remote %s → %s", l.Forward.ListenPort(), l.Forward.Destination())
+	default:
+		return fmt.Sprintf("local %s → %s", l.Forward.ListenPort(), l.Forward.Destination())
+	}
+}
+
+func (l ListItemTunnel) state() string {
+	switch {
+	case l.Running != nil && l.Orphaned:
+		return fmt.Sprintf("running, pid %d, forwarding is deleted", l.Running.PID)
+	case l.Running != nil:
+		return fmt.Sprintf("running, pid %d, since %s", l.Running.PID, l.Running.StartedAt.Format("Jan 2 15:04"))
+	case !utils.StringEmpty(&l.LastError):
+		return "stopped: " + l.LastError
+	default:
+		return "stopped"
+	}
+}
@@ -0,0 +1,259 @@
+// Package tunnellist implements the view, which lists port forwardings of all hosts. The forwardings can be
+// started in background and stopped from this view.
+package tunnellist
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/key"
+	"charm.land/bubbles/v2/list"
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/storage"
+	"github.com/grafviktor/goto/internal/tunnel"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+const defaultTitle = "tunnels"
+
+type iLogger interface {
+	Debug(format string, args ...any)
+	Info(format string, args ...any)
+	Error(format string, args ...any)
+}
+
+type tunnelManager interface {
+	Running() ([]tunnel.Tunnel, error)
+	Start(h host.Host, forward host.Forward) (tunnel.Tunnel, error)
+	Stop(tunnelID string) error
+}
+
+var toggleKey = key.NewBinding(
+	key.WithKeys("enter"),
+	key.WithHelp("↩", "start/stop"),
+)
+
+type Model struct {
+	list.Model
+
+	repo    storage.HostStorage
+	tunnels tunnelManager
+	// lastErrors are keyed by tunnel ID and contain ssh output of tunnels, which have ended unexpectedly.
+	lastErrors map[string]string
+	logger     iLogger
+	styles     styles
+}
+
+// New - creates a new UI component which displays port forwardings of all hosts.
+func New(_ context.Context, repo storage.HostStorage, tunnels tunnelManager, log iLogger) *Model {
+	styles := defaultStyles()
+
+	var listItems []list.Item
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles = styles.listDelegate
+
+	model := list.New(listItems, delegate, 0, 0)
+	model.DisableQuitKeybindings() // We don't want to quit the app from this view.
+	model.SetStatusBarItemName("tunnel", "tunnels")
+	model.AdditionalShortHelpKeys = func() []key.Binding { return []key.Binding{toggleKey} }
+	model.AdditionalFullHelpKeys = model.AdditionalShortHelpKeys
+
+	// Setup filter input styles.
+	filterStyles := model.FilterInput.Styles()
+	filterStyles.Focused.Prompt = styles.prompt
+	filterStyles.Focused.Text = styles.filterInput
+	model.FilterInput.SetStyles(filterStyles)
+
+	// Setup model styles.
+	model.Styles = styles.list
+	model.Paginator.ActiveDot = styles.paginatorActiveDot
+	model.Paginator.InactiveDot = styles.paginatorInactiveDot
+	model.Help.Styles = styles.help
+
+	m := Model{
+		Model:      model,
+		repo:       repo,
+		tunnels:    tunnels,
+		lastErrors: make(map[string]string),
+		logger:     log,
+		styles:     styles,
+	}
+
+	m.Title = defaultTitle
+
+	return &m
+}
+
+func (m *Model) Init() tea.Cmd { return nil }
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		h, v := m.styles.componentMargins.GetFrameSize()
+		m.SetSize(msg.Width-h, msg.Height-v)
+		m.logger.Debug("[UI] Set tunnel list size: %d %d", m.Width(), m.Height())
+		return m, nil
+	case tea.KeyPressMsg:
+		if cmd, handled := m.handleKeyboardEvent(msg); handled {
+			return m, cmd
+		}
+	case message.ViewTunnelListOpen:
+		return m, m.loadItems()
+	case message.TunnelEnded:
+		output := strings.Split(strings.TrimSpace(msg.Exit.Output), "\n")
+		m.lastErrors[msg.Exit.Tunnel.ID()] = output[len(output)-1]
+		if m.lastErrors[msg.Exit.Tunnel.ID()] == "" && msg.Exit.Err != nil {
+			m.lastErrors[msg.Exit.Tunnel.ID()] = msg.Exit.Err.Error()
+		}
+
+		return m, m.loadItems()
+	case message.HideUINotification:
+		if msg.ComponentName == "tunnellist" {
+			m.logger.Debug("[UI] Hide notification message")
+			m.Title = defaultTitle
+		}
+
+		return m, nil
+	}
+
+	m.Model, cmd = m.Model.Update(msg)
+	// Only calculate status bar visibility AFTER the model is updated.
+	m.SetShowStatusBar(m.FilterState() != list.Unfiltered)
+
+	return m, tea.Batch(append(cmds, cmd)...)
+}
+
+func (m *Model) View() tea.View {
+	return tea.NewView(m.styles.componentMargins.Render(m.Model.View()))
+}
+
+// SetTitle - displays a notification in the title, see message.DisplayNotification.
+func (m *Model) SetTitle(title string) {
+	m.Title = title
+}
+
+// handleKeyboardEvent - returns true when the key is handled and should not be passed to the list model.
+func (m *Model) handleKeyboardEvent(msg tea.KeyPressMsg) (tea.Cmd, bool) {
+	if m.FilterState() == list.Filtering {
+		// Let the list model handle the filter input.
+		return nil, false
+	}
+
+	switch {
+	case msg.Code == tea.KeyEscape:
+		// If model is in filter mode and press ESC, just disable filtering.
+		if m.FilterState() == list.FilterApplied {
+			m.logger.Debug("[UI] Escape key. Deactivate filter in tunnel list view.")
+			return nil, false
+		}
+
+		m.logger.Debug("[UI] Escape key. Exit from tunnel list view.")
+		return message.TeaCmd(message.ViewTunnelListClose{}), true
+	case key.Matches(msg, toggleKey):
+		return m.toggleTunnel(), true
+	}
+
+	return nil, false
+}
+
+func (m *Model) toggleTunnel() tea.Cmd {
+	item, ok := m.SelectedItem().(ListItemTunnel)
+	if !ok {
+		return nil
+	}
+
+	if item.Running != nil {
+		m.logger.Info("[UI] Stop tunnel %q", item.ID())
+		if err := m.tunnels.Stop(item.ID()); err != nil {
+			m.logger.Error("[UI] Cannot stop tunnel %q. %v", item.ID(), err)
+			return tea.Batch(m.loadItems(), m.displayNotificationMsg(err.Error()))
+		}
+
+		return tea.Batch(m.loadItems(), m.displayNotificationMsg(fmt.Sprintf("%s stopped", item.Forward.Name)))
+	}
+
+	delete(m.lastErrors, item.ID())
+	h, err := m.repo.Get(item.HostID)
+	if err == nil {
+		err = h.ResolveJumpChain(m.repo.Get)
+	}
+
+	if err == nil {
+		_, err = m.tunnels.Start(h, item.Forward)
+	}
+
+	if err != nil {
+		m.logger.Error("[UI] Cannot start tunnel %q. %v", item.ID(), err)
+		m.lastErrors[item.ID()] = err.Error()
+		return m.loadItems()
+	}
+
+	m.logger.Info("[UI] Start tunnel %q", item.ID())
+	return tea.Batch(m.loadItems(),
+		m.displayNotificationMsg(fmt.Sprintf("%s started on port %s", item.Forward.Name, item.Forward.ListenPort())))
+}
+
+// loadItems - displays forwardings of all hosts, and running tunnels, whose hosts or forwardings are deleted,
+// so that they can be stopped.
+func (m *Model) loadItems() tea.Cmd {
+	hosts, err := m.repo.GetAll()
+	if err != nil {
+		m.logger.Error("[UI] Cannot read database. %v", err)
+		return message.TeaCmd(message.ErrorOccurred{Err: err})
+	}
+
+	running, err := m.tunnels.Running()
+	if err != nil {
+		m.logger.Error("[UI] Cannot read running tunnels. %v", err)
+		return message.TeaCmd(message.ErrorOccurred{Err: err})
+	}
+
+	slices.SortFunc(hosts, func(a, b host.Host) int {
+		return cmp.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	})
+
+	items := []list.Item{}
+	listed := make(map[string]bool)
+	for _, h := range hosts {
+		for _, forward := range h.Forwards {
+			item := ListItemTunnel{HostID: h.ID, HostTitle: h.Title, Forward: forward}
+			item.LastError = m.lastErrors[item.ID()]
+			if index := slices.IndexFunc(running, func(t tunnel.Tunnel) bool { return t.ID() == item.ID() }); index >= 0 {
+				item.Running = &running[index]
+				listed[item.ID()] = true
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	for i := range running {
+		if listed[running[i].ID()] {
+			continue
+		}
+
+		items = append(items, ListItemTunnel{
+			HostID:    running[i].HostID,
+			HostTitle: running[i].HostTitle,
+			Forward:   running[i].Forward,
+			Running:   &running[i],
+			Orphaned:  true,
+		})
+	}
+
+	m.logger.Debug("[UI] Load complete. Found '%d' tunnels", len(items))
+	return m.SetItems(items)
+}
+
+func (m *Model) displayNotificationMsg(msg string) tea.Cmd {
+	m.logger.Debug("[UI] Notification message: %s", msg)
+	return message.DisplayNotification("tunnellist", msg, m)
+}
@@ -0,0 +1,155 @@
+package tunnellist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/model/host"
+	testutils "github.com/grafviktor/goto/internal/testutils"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+	"github.com/grafviktor/goto/internal/tunnel"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+type mockTunnelManager struct {
+	running  []tunnel.Tunnel
+	startErr error
+	started  []host.Host
+}
+
+func (m *mockTunnelManager) Running() ([]tunnel.Tunnel, error) {
+	return m.running, nil
+}
+
+func (m *mockTunnelManager) Start(h host.Host, forward host.Forward) (tunnel.Tunnel, error) {
+	if m.startErr != nil {
+		return tunnel.Tunnel{}, m.startErr
+	}
+
+	t := tunnel.Tunnel{HostID: h.ID, HostTitle: h.Title, Forward: forward, PID: 100, StartedAt: time.Now()}
+	m.started = append(m.started, h)
+	m.running = append(m.running, t)
+	return t, nil
+}
+
+func (m *mockTunnelManager) Stop(tunnelID string) error {
+	for i := range m.running {
+		if m.running[i].ID() == tunnelID {
+			m.running = append(m.running[:i], m.running[i+1:]...)
+			return nil
+		}
+	}
+
+	return tunnel.ErrNotRunning
+}
+
+var (
+	forwardDB    = host.Forward{Name: "db", Type: host.ForwardTypes.Local, Spec: "5432:db.internal:5432"}
+	forwardSocks = host.Forward{Name: "socks", Type: host.ForwardTypes.Dynamic, Spec: "1080"}
+)
+
+func TestNew(t *testing.T) {
+	model := New(context.TODO(), testutils.NewMockStorage(false), &mockTunnelManager{}, &mocklogger.Logger{})
+	require.True(t, model.FilteringEnabled())
+	// Quit app keys is disabled
+	require.False(t, model.KeyMap.Quit.Enabled())
+	require.Equal(t, "tunnels", model.Title)
+}
+
+func TestLoadItems(t *testing.T) {
+	model, _, tunnels := newMockTunnelModel()
+	tunnels.running = []tunnel.Tunnel{
+		{HostID: "2", HostTitle: "Mock Host 2", Forward: forwardSocks, PID: 100},
+		// The host is deleted, but the tunnel is still running.
+		{HostID: "deleted", HostTitle: "Deleted Host", Forward: forwardDB, PID: 200},
+	}
+
+	model.Update(message.ViewTunnelListOpen{})
+	require.Len(t, model.Items(), 3)
+
+	db := model.Items()[0].(ListItemTunnel)
+	require.Equal(t, "db (Mock Host 1)", db.Title())
+	require.Equal(t, "local 5432 → db.internal:5432  stopped", db.Description())
+
+	socks := model.Items()[1].(ListItemTunnel)
+	require.Equal(t, "socks (Mock Host 2)", socks.Title())
+	require.Contains(t, socks.Description(), "dynamic 1080 (SOCKS)  running, pid 100")
+
+	orphaned := model.Items()[2].(ListItemTunnel)
+	require.True(t, orphaned.Orphaned)
+	require.Equal(t, "local 5432 → db.internal:5432  running, pid 200, forwarding is deleted", orphaned.Description())
+}
+
+func TestToggleTunnel(t *testing.T) {
+	model, storage, tunnels := newMockTunnelModel()
+	model.Update(message.ViewTunnelListOpen{})
+
+	// Jump hosts are resolved before the tunnel is started.
+	storage.Hosts[0].ProxyJump = []string{"3"}
+	model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Len(t, tunnels.started, 1)
+	require.Equal(t, "root@localhost:2222", tunnels.started[0].JumpChain)
+	require.NotNil(t, model.Items()[0].(ListItemTunnel).Running)
+	require.Equal(t, "db started on port 5432", model.Title)
+
+	model.Update(message.HideUINotification{ComponentName: "tunnellist"})
+	require.Equal(t, "tunnels", model.Title)
+
+	model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Empty(t, tunnels.running)
+	require.Nil(t, model.Items()[0].(ListItemTunnel).Running)
+
+	// The error is displayed until the tunnel is started again.
+	tunnels.startErr = errors.New("exec: \"ssh\": executable file not found in $PATH")
+	model.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	require.Equal(t, "local 5432 → db.internal:5432  stopped: exec: \"ssh\": executable file not found in $PATH",
+		model.Items()[0].(ListItemTunnel).Description())
+}
+
+func TestTunnelEnded(t *testing.T) {
+	model, _, _ := newMockTunnelModel()
+	model.Update(message.TunnelEnded{Exit: tunnel.Exit{
+		Tunnel: tunnel.Tunnel{HostID: "1", HostTitle: "Mock Host 1", Forward: forwardDB},
+		Err:    errors.New("exit status 255"),
+		Output: "Warning: Permanently added 'localhost'\nbind [127.0.0.1]:5432: Address already in use",
+	}})
+
+	require.Equal(t, "local 5432 → db.internal:5432  stopped: bind [127.0.0.1]:5432: Address already in use",
+		model.Items()[0].(ListItemTunnel).Description())
+}
+
+func Test_handleEscapeKey(t *testing.T) {
+	model, _, _ := newMockTunnelModel()
+	model.Update(message.ViewTunnelListOpen{})
+	// Escape in filter mode only deactivates the filter.
+	model.Update(tea.KeyPressMsg{Code: '/'})
+	require.True(t, model.SettingFilter())
+	_, cmd := model.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	require.Nil(t, cmd)
+
+	_, cmd = model.Update(tea.KeyPressMsg{Code: tea.KeyEsc})
+	var actualMsgs []tea.Msg
+	testutils.CmdToMessage(cmd, &actualMsgs)
+	require.Equal(t, []tea.Msg{message.ViewTunnelListClose{}}, actualMsgs)
+}
+
+// ==============================================
+// ============== utility methods ===============
+// ==============================================
+
+func newMockTunnelModel() (*Model, *testutils.MockStorage, *mockTunnelManager) {
+	storage := testutils.NewMockStorage(false)
+	storage.Hosts[0].Forwards = []host.Forward{forwardDB}
+	storage.Hosts[1].Forwards = []host.Forward{forwardSocks}
+	tunnels := &mockTunnelManager{}
+
+	model := New(context.TODO(), storage, tunnels, &mocklogger.Logger{})
+	model.SetSize(100, 100)
+
+	return model, storage, tunnels
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/grafviktor/goto/internal/constant"
 	"github.com/grafviktor/goto/internal/model/host"
 	"github.com/grafviktor/goto/internal/model/sshconfig"
+	"github.com/grafviktor/goto/internal/tunnel"
 )
 
 type (
@@ -17,29 +18,62 @@ type (
 	// TerminalSizePolling - is a message which is sent when terminal width and/or height changes.
 	TerminalSizePolling struct{ Width, Height int }
 	// HostSelect is required to let host list know that it's time to update title.
-	HostSelect struct{ HostID int }
+	HostSelect struct{ HostID string }
 	// HostCreate - is dispatched when a new host was added to the database.
 	HostCreate struct{ Host host.Host }
 	// HostUpdate - is dispatched when host model is updated.
 	HostUpdate struct{ Host host.Host }
+	// HostListReload - is dispatched when storage files were modified outside of the app.
+	HostListReload struct{}
+	// HostListRefresh - is dispatched when user forces the app to read all storage files again.
+	HostListRefresh struct{}
 	// HostSSHConfigLoadComplete triggers when app loads a host config using ssh -G <hostname>.
 	// The config is stored in main model: m.appState.HostSSHConfig.
 	HostSSHConfigLoadComplete struct {
-		HostID int
+		HostID string
 		Config sshconfig.Config
 	}
+	// HostSSHConfigLoadFailed triggers when 'ssh -G <hostname>' ends with an error.
+	HostSSHConfigLoadFailed struct {
+		HostID string
+		Err    string
+	}
 	// ViewGroupListOpen - dispatched when it's required to open group list view.
 	ViewGroupListOpen struct{}
 	// ViewGroupListClose - dispatched when it's required to close group list view.
 	ViewGroupListClose struct{}
 	// GroupSelect - is dispatched when select a group in group list view.
 	GroupSelect struct{ Name string }
+	// ViewTagListOpen - dispatched when it's required to open tag list view.
+	ViewTagListOpen struct{}
+	// ViewTagListClose - dispatched when it's required to close tag list view.
+	ViewTagListClose struct{}
+	// TagSelect - is dispatched when select tags in tag list view. Hosts must have all of the tags when
+	// MatchAll is true, otherwise at least one of them.
+	TagSelect struct {
+		Tags     []string
+		MatchAll bool
+	}
+	// ViewPatternListOpen - dispatched when it's required to open the list of wildcard ssh_config blocks.
+	ViewPatternListOpen struct{}
+	// ViewPatternListClose - dispatched when it's required to close the list of wildcard ssh_config blocks.
+	ViewPatternListClose struct{}
 	// HideUINotification - is dispatched when it's time to hide UI notification and display normal component's title.
 	HideUINotification struct{ ComponentName string }
 	// ViewHostEditOpen fires when user press edit button on a selected host.
-	ViewHostEditOpen struct{ HostID int }
+	ViewHostEditOpen struct{ HostID string }
 	// ViewHostEditClose triggers when users exits from edit form without saving results.
 	ViewHostEditClose struct{}
+	// ViewHostDetailsOpen - dispatched when it's required to display effective configuration of the host.
+	ViewHostDetailsOpen struct{ Host host.Host }
+	// ViewHostDetailsClose - dispatched when it's required to close host details view.
+	ViewHostDetailsClose struct{}
+	// ViewTunnelListOpen - dispatched when it's required to open the list of port forwardings.
+	ViewTunnelListOpen struct{}
+	// ViewTunnelListClose - dispatched when it's required to close the list of port forwardings.
+	ViewTunnelListClose struct{}
+	// TunnelEnded - is dispatched when a tunnel, which is started by the app, ends without being stopped.
+	TunnelEnded struct{ Exit tunnel.Exit }
 	// ErrorOccurred - is dispatched when an error occurs.
 	ErrorOccurred struct{ Err error }
 	// ExitWithError - indicates that something bad happened and we need to close the application.
@@ -59,6 +93,7 @@ type (
 	// RunProcessSuccess fires when external process exits normally.
 	RunProcessSuccess struct {
 		ProcessType constant.ProcessType
+		HostID      string // Host which the process was started for. Only set when ssh config is loaded.
 		StdOut      string
 		StdErr      string // Even if process succeeds, it may have some output.
 	}
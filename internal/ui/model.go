@@ -6,21 +6,32 @@ import (
 	"fmt"
 	"os/exec"
 	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/viewport"
 	tea "charm.land/bubbletea/v2"
 
 	"github.com/grafviktor/goto/internal/constant"
+	hostModel "github.com/grafviktor/goto/internal/model/host"
 	"github.com/grafviktor/goto/internal/model/sshconfig"
 	"github.com/grafviktor/goto/internal/state"
 	"github.com/grafviktor/goto/internal/storage"
+	"github.com/grafviktor/goto/internal/tunnel"
 	"github.com/grafviktor/goto/internal/ui/component/grouplist"
+	"github.com/grafviktor/goto/internal/ui/component/hostdetails"
 	"github.com/grafviktor/goto/internal/ui/component/hostedit"
 	"github.com/grafviktor/goto/internal/ui/component/hostlist"
+	"github.com/grafviktor/goto/internal/ui/component/patternlist"
+	"github.com/grafviktor/goto/internal/ui/component/taglist"
+	"github.com/grafviktor/goto/internal/ui/component/tunnellist"
 	"github.com/grafviktor/goto/internal/ui/message"
 	"github.com/grafviktor/goto/internal/utils"
 )
 
+// sshConfigLoadDebounceTime - 'ssh -G' process starts only when focus stays on a host during this time,
+// so that scrolling through the host list does not spawn a process for every host.
+const sshConfigLoadDebounceTime = time.Millisecond * 150
+
 type iLogger interface {
 	Debug(format string, args ...any)
 	Info(format string, args ...any)
@@ -36,13 +47,19 @@ func New(
 	appState *state.State,
 	log iLogger,
 ) MainModel {
+	tunnels := tunnel.NewManager(appState.AppHome, log)
 	m := MainModel{
-		modelHostList:  hostlist.New(ctx, storage, appState, log),
-		modelGroupList: grouplist.New(ctx, storage, appState, log),
-		appContext:     ctx,
-		hostStorage:    storage,
-		appState:       appState,
-		logger:         log,
+		modelHostList:    hostlist.New(ctx, storage, appState, log),
+		modelGroupList:   grouplist.New(ctx, storage, appState, log),
+		modelTagList:     taglist.New(ctx, storage, appState, log),
+		modelPatternList: patternlist.New(ctx, storage, log),
+		modelTunnelList:  tunnellist.New(ctx, storage, tunnels, log),
+		sshConfigCache:   newSSHConfigCache(),
+		tunnels:          tunnels,
+		appContext:       ctx,
+		hostStorage:      storage,
+		appState:         appState,
+		logger:           log,
 	}
 
 	return m
@@ -53,7 +70,14 @@ type MainModel struct {
 	hostStorage        storage.HostStorage
 	modelHostList      tea.Model
 	modelGroupList     tea.Model
+	modelTagList       tea.Model
+	modelPatternList   tea.Model
+	modelTunnelList    tea.Model
 	modelHostEdit      tea.Model
+	modelHostDetails   tea.Model
+	sshConfigCache     *sshConfigCache
+	sshConfigLookup    sshConfigLookup
+	tunnels            *tunnel.Manager
 	appState           *state.State
 	viewMessageContent string
 	logger             iLogger
@@ -66,7 +90,38 @@ func (m *MainModel) Init() tea.Cmd {
 	m.logger.Debug("[UI] Render main view")
 
 	// Loads hosts from DB
-	return m.modelHostList.Init()
+	return tea.Batch(m.modelHostList.Init(), m.waitForStorageChanges(), m.waitForTunnelExits())
+}
+
+// waitForTunnelExits - returns a command which blocks until a tunnel, started by the app, ends unexpectedly.
+// The command must be re-issued after every TunnelEnded message.
+func (m *MainModel) waitForTunnelExits() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case exit := <-m.tunnels.Exits():
+			return message.TunnelEnded{Exit: exit}
+		case <-m.appContext.Done():
+			return nil
+		}
+	}
+}
+
+// waitForStorageChanges - returns a command which blocks until hosts are modified outside of the app.
+// The command must be re-issued after every HostListReload message.
+func (m *MainModel) waitForStorageChanges() tea.Cmd {
+	watchable, ok := m.hostStorage.(storage.Watchable)
+	if !ok {
+		return nil
+	}
+
+	return func() tea.Msg {
+		select {
+		case <-watchable.Changes():
+			return message.HostListReload{}
+		case <-m.appContext.Done():
+			return nil
+		}
+	}
 }
 
 //nolint:funlen
@@ -91,23 +146,61 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case message.ViewHostEditClose:
 		m.logger.Debug("[UI] Close host edit form")
 		m.appState.CurrentView = state.ViewHostList
+	case message.ViewHostDetailsOpen:
+		m.logger.Debug("[UI] Open host details for host id: %s", msg.Host.ID)
+		m.appState.CurrentView = state.ViewHostDetails
+		m.modelHostDetails = hostdetails.New(m.appContext, msg.Host, m.appState, m.logger)
+	case message.ViewHostDetailsClose:
+		m.logger.Debug("[UI] Close host details")
+		m.appState.CurrentView = state.ViewHostList
 	case message.ViewGroupListOpen:
 		m.logger.Debug("[UI] Open select group form")
 		m.appState.CurrentView = state.ViewGroupList
 	case message.ViewGroupListClose:
 		m.logger.Debug("[UI] Close select group form")
 		m.appState.CurrentView = state.ViewHostList
+	case message.ViewTagListOpen:
+		m.logger.Debug("[UI] Open select tags form")
+		m.appState.CurrentView = state.ViewTagList
+	case message.ViewTagListClose:
+		m.logger.Debug("[UI] Close select tags form")
+		m.appState.CurrentView = state.ViewHostList
+	case message.ViewPatternListOpen:
+		m.logger.Debug("[UI] Open ssh_config pattern list")
+		m.appState.CurrentView = state.ViewPatternList
+	case message.ViewPatternListClose:
+		m.logger.Debug("[UI] Close ssh_config pattern list")
+		m.appState.CurrentView = state.ViewHostList
+	case message.ViewTunnelListOpen:
+		m.logger.Debug("[UI] Open tunnel list")
+		m.appState.CurrentView = state.ViewTunnelList
+	case message.ViewTunnelListClose:
+		m.logger.Debug("[UI] Close tunnel list")
+		m.appState.CurrentView = state.ViewHostList
+	case message.TunnelEnded:
+		m.logger.Debug("[UI] Tunnel %q ended, wait for the next one", msg.Exit.Tunnel.ID())
+		cmds = append(cmds, m.waitForTunnelExits())
+	case message.HostListReload:
+		m.logger.Debug("[UI] Storage files changed, wait for the next change")
+		m.sshConfigCache.invalidate()
+		cmds = append(cmds, m.waitForStorageChanges())
+	case message.HostListRefresh, message.HostCreate, message.HostUpdate:
+		// Any change of ssh_config can affect all hosts, for instance when "Host *" block is modified.
+		m.logger.Debug("[UI] Hosts changed, drop cached SSH configs")
+		m.sshConfigCache.invalidate()
 	case message.HostSelect:
-		m.logger.Debug("[UI] Update app state. Active host id: %d", msg.HostID)
+		m.logger.Debug("[UI] Update app state. Active host id: %s", msg.HostID)
 		m.appState.Selected = msg.HostID
 	case message.RunProcessSSHConnect:
 		m.logger.Debug("[UI] Connect to focused SSH host")
 		return m, m.dispatchProcessSSHConnect(msg)
 	case message.RunProcessSSHLoadConfig:
-		m.logger.Debug("[UI] Load SSH config for focused host id: %d, title: %q", msg.Host.ID, msg.Host.Title)
+		m.logger.Debug("[UI] Load SSH config for focused host id: %s, title: %q", msg.Host.ID, msg.Host.Title)
+		return m, m.scheduleSSHConfigLookup(msg)
+	case sshConfigLoadDebounced:
 		return m, m.dispatchProcessSSHLoadConfig(msg)
 	case message.RunProcessSSHCopyID:
-		m.logger.Debug("[UI] Copy SSH config to host id: %d, title: %q", msg.Host.ID, msg.Host.Title)
+		m.logger.Debug("[UI] Copy SSH config to host id: %s, title: %q", msg.Host.ID, msg.Host.Title)
 		return m, m.dispatchProcessSSHCopyID(msg)
 	case message.RunProcessSuccess:
 		m.logger.Debug("[UI] Handle process success message. Process: %v", msg.ProcessType)
@@ -126,6 +219,12 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	cmds = append(cmds, cmd)
 	m.modelGroupList, cmd = m.modelGroupList.Update(msg)
 	cmds = append(cmds, cmd)
+	m.modelTagList, cmd = m.modelTagList.Update(msg)
+	cmds = append(cmds, cmd)
+	m.modelPatternList, cmd = m.modelPatternList.Update(msg)
+	cmds = append(cmds, cmd)
+	m.modelTunnelList, cmd = m.modelTunnelList.Update(msg)
+	cmds = append(cmds, cmd)
 
 	if m.appState.CurrentView == state.ViewEditItem {
 		// Edit host receives messages only if it's active. We re-create this component every time we go to edit mode
@@ -133,6 +232,12 @@ func (m *MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	if m.appState.CurrentView == state.ViewHostDetails {
+		// Host details view is re-created every time it's opened, the same as edit host.
+		m.modelHostDetails, cmd = m.modelHostDetails.Update(msg)
+		cmds = append(cmds, cmd)
+	}
+
 	return m, tea.Batch(cmds...)
 }
 
@@ -144,10 +249,18 @@ func (m *MainModel) View() tea.View {
 		content = m.modelHostList.View()
 	case state.ViewGroupList:
 		content = m.modelGroupList.View()
+	case state.ViewTagList:
+		content = m.modelTagList.View()
+	case state.ViewPatternList:
+		content = m.modelPatternList.View()
+	case state.ViewTunnelList:
+		content = m.modelTunnelList.View()
 	case state.ViewMessage:
 		content = tea.NewView(m.viewMessageContent)
 	case state.ViewEditItem:
 		content = m.modelHostEdit.View()
+	case state.ViewHostDetails:
+		content = m.modelHostDetails.View()
 	}
 
 	// Wrap UI into the ViewPort
@@ -179,8 +292,16 @@ func (m *MainModel) handleKeyEvent(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
 		m.modelHostList, cmd = m.modelHostList.Update(msg)
 	case state.ViewGroupList:
 		m.modelGroupList, cmd = m.modelGroupList.Update(msg)
+	case state.ViewTagList:
+		m.modelTagList, cmd = m.modelTagList.Update(msg)
+	case state.ViewPatternList:
+		m.modelPatternList, cmd = m.modelPatternList.Update(msg)
+	case state.ViewTunnelList:
+		m.modelTunnelList, cmd = m.modelTunnelList.Update(msg)
 	case state.ViewEditItem:
 		m.modelHostEdit, cmd = m.modelHostEdit.Update(msg)
+	case state.ViewHostDetails:
+		m.modelHostDetails, cmd = m.modelHostDetails.Update(msg)
 	}
 
 	return m, cmd
@@ -265,37 +386,123 @@ func (m *MainModel) dispatchProcess(
 
 func (m *MainModel) dispatchProcessSSHConnect(msg message.RunProcessSSHConnect) tea.Cmd {
 	m.logger.Debug("[EXEC] Build ssh connect command for hostname: %v, title: %v", msg.Host.Address, msg.Host.Title)
+	if err := m.resolveJumpChain(&msg.Host); err != nil {
+		m.displayJumpChainError(msg.Host, err)
+		return nil
+	}
+
 	process := utils.BuildProcessInterceptStdErr(msg.Host.CmdSSHConnect())
 	m.logger.Info("[EXEC] Run process: '%s'", process.String())
 
 	return m.dispatchProcess(constant.ProcessTypeSSHConnect, process, false, false)
 }
 
-func (m *MainModel) dispatchProcessSSHLoadConfig(msg message.RunProcessSSHLoadConfig) tea.Cmd {
-	m.logger.Debug("[EXEC] Read ssh configuration for host: '%+v'", msg.Host)
-	process := utils.BuildProcessInterceptStdAll(msg.Host.CmdSSHConfig())
+// scheduleSSHConfigLookup - returns cached host config, or schedules 'ssh -G' process, which starts when debounce
+// time expires. Any lookup which is scheduled or running is cancelled, because the result is not required anymore.
+func (m *MainModel) scheduleSSHConfigLookup(msg message.RunProcessSSHLoadConfig) tea.Cmd {
+	m.cancelSSHConfigLookup()
+	if err := m.resolveJumpChain(&msg.Host); err != nil {
+		// The config is still loaded, though it does not contain jump hosts.
+		m.logger.Error("[EXEC] Cannot resolve jump hosts of host id: %s. %v", msg.Host.ID, err)
+	}
+
+	command := msg.Host.CmdSSHConfig()
+	if config, found := m.sshConfigCache.get(msg.Host.ID, command); found {
+		m.logger.Debug("[EXEC] Use cached SSH config for host id: %s", msg.Host.ID)
+		return message.TeaCmd(message.HostSSHConfigLoadComplete{HostID: msg.Host.ID, Config: config})
+	}
+
+	tag := m.sshConfigLookup.tag
+	hostID := msg.Host.ID
+	return tea.Tick(sshConfigLoadDebounceTime, func(_ time.Time) tea.Msg {
+		return sshConfigLoadDebounced{tag: tag, hostID: hostID, command: command}
+	})
+}
+
+// cancelSSHConfigLookup - supersedes the lookup, which is scheduled, and kills 'ssh -G' process if it's running.
+func (m *MainModel) cancelSSHConfigLookup() {
+	if m.sshConfigLookup.cancel != nil {
+		m.sshConfigLookup.cancel()
+	}
+
+	m.sshConfigLookup = sshConfigLookup{tag: m.sshConfigLookup.tag + 1}
+}
+
+func (m *MainModel) dispatchProcessSSHLoadConfig(msg sshConfigLoadDebounced) tea.Cmd {
+	if msg.tag != m.sshConfigLookup.tag {
+		m.logger.Debug("[EXEC] Skip SSH config lookup for host id: %s, it's superseded", msg.hostID)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(m.appContext)
+	m.sshConfigLookup.hostID = msg.hostID
+	m.sshConfigLookup.command = msg.command
+	m.sshConfigLookup.cancel = cancel
+	process := utils.BuildProcessInterceptStdAll(ctx, msg.command)
 	m.logger.Info("[EXEC] Run process: '%s'", process.String())
 
-	// Should run in non-blocking fashion for ssh load config
-	return m.dispatchProcess(constant.ProcessTypeSSHLoadConfig, process, true, true)
+	// Should run in non-blocking fashion for ssh load config. Cancelled process ends with an error, which is ignored.
+	dispatch := m.dispatchProcess(constant.ProcessTypeSSHLoadConfig, process, true, false)
+	return func() tea.Msg {
+		defer cancel()
+		result := dispatch()
+		if ctx.Err() != nil {
+			// The lookup is superseded while the process was running.
+			return nil
+		}
+
+		if success, ok := result.(message.RunProcessSuccess); ok {
+			// Result must be applied to the host which requested it, rather than the focused one.
+			success.HostID = msg.hostID
+			return success
+		}
+
+		if failure, ok := result.(message.RunProcessErrorOccurred); ok {
+			// The error is displayed by the views which wait for the config, rather than in message view.
+			return message.HostSSHConfigLoadFailed{HostID: msg.hostID, Err: failure.StdErr}
+		}
+
+		return result
+	}
 }
 
 func (m *MainModel) dispatchProcessSSHCopyID(msg message.RunProcessSSHCopyID) tea.Cmd {
 	identityFile, hostname := msg.Host.SSHHostConfig.IdentityFile, msg.Host.SSHHostConfig.Hostname
 	m.logger.Debug("[EXEC] Copy ssh-key '%s.pub' to host '%s'", identityFile, hostname)
-	process := utils.BuildProcessInterceptStdAll(msg.Host.CmdSSHCopyID())
+	if err := m.resolveJumpChain(&msg.Host); err != nil {
+		m.displayJumpChainError(msg.Host, err)
+		return nil
+	}
+
+	process := utils.BuildProcessInterceptStdAll(m.appContext, msg.Host.CmdSSHCopyID())
 	m.logger.Info("[EXEC] Run process: '%s'", process.String())
 
 	// Should run in non-blocking fashion for ssh copy id
 	return m.dispatchProcess(constant.ProcessTypeSSHCopyID, process, false, false)
 }
 
+// resolveJumpChain - builds ssh '-J' option value from the jump hosts, which are read from the storage,
+// so that the command uses their current addresses, even if they're changed after the host is loaded.
+func (m *MainModel) resolveJumpChain(host *hostModel.Host) error {
+	return host.ResolveJumpChain(m.hostStorage.Get)
+}
+
+func (m *MainModel) displayJumpChainError(host hostModel.Host, err error) {
+	m.logger.Error("[EXEC] Cannot resolve jump hosts of host id: %s. %v", host.ID, err)
+	m.viewMessageContent = fmt.Sprintf("Cannot connect to %q through jump hosts.\nError:   %v", host.Title, err)
+	m.appState.CurrentView = state.ViewMessage
+}
+
 func (m *MainModel) handleProcessSuccess(msg message.RunProcessSuccess) tea.Cmd {
 	if msg.ProcessType == constant.ProcessTypeSSHLoadConfig {
 		parsedSSHConfig := sshconfig.Parse(msg.StdOut)
-		m.logger.Debug("[EXEC] Host SSH config loaded: %+v", *parsedSSHConfig)
+		m.logger.Debug("[EXEC] Host SSH config loaded for host id: %s", msg.HostID)
+		if msg.HostID == m.sshConfigLookup.hostID {
+			m.sshConfigCache.put(msg.HostID, m.sshConfigLookup.command, *parsedSSHConfig)
+		}
+
 		return message.TeaCmd(message.HostSSHConfigLoadComplete{
-			HostID: m.appState.Selected,
+			HostID: msg.HostID,
 			Config: *parsedSSHConfig,
 		})
 	}
@@ -25,7 +25,10 @@ import (
 )
 
 func TestNew(t *testing.T) {
-	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	// The context is canceled, so that the command, which waits for tunnel exits, does not block.
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	model := New(ctx, testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
 	require.NotNil(t, model)
 	cmd := model.Init()
 	var msgs []tea.Msg
@@ -117,20 +120,43 @@ func TestDispatchProcess_Background_Fail(t *testing.T) {
 	require.IsType(t, message.RunProcessErrorOccurred{}, result)
 }
 
+func TestDispatchProcessSSHLoadConfig_Fail(t *testing.T) {
+	model := New(context.TODO(), testutils.NewMockStorage(true), MockAppState(), &mocklogger.Logger{})
+	model.cancelSSHConfigLookup()
+	msg := sshConfigLoadDebounced{tag: model.sshConfigLookup.tag, hostID: "2", command: "nonexistent command"}
+
+	// The error is delivered to the views, which wait for the host config, rather than displayed in message view.
+	result := model.dispatchProcessSSHLoadConfig(msg)()
+	require.IsType(t, message.HostSSHConfigLoadFailed{}, result)
+	require.Equal(t, "2", result.(message.HostSSHConfigLoadFailed).HostID)
+	require.Contains(t, result.(message.HostSSHConfigLoadFailed).Err, "nonexistent command")
+	require.NotEqual(t, state.ViewMessage, model.appState.CurrentView)
+}
+
 func TestHandleProcessSuccess_SSH_load_config(t *testing.T) {
 	model := New(context.TODO(), testutils.NewMockStorage(true), MockAppState(), &mocklogger.Logger{})
+	// Result belongs to the host which requested it, even if another host is focused.
+	model.appState.Selected = "1"
 	given := message.RunProcessSuccess{
 		ProcessType: constant.ProcessTypeSSHLoadConfig,
+		HostID:      "2",
 		StdOut:      "hostname localhost\r\nport 2222\r\nidentityfile /tmp\r\nuser root",
 	}
 
 	expected := message.HostSSHConfigLoadComplete{
-		HostID: 0,
+		HostID: "2",
 		Config: sshconfig.Config{
-			Hostname:     "localhost",
-			IdentityFile: "/tmp",
-			Port:         "2222",
-			User:         "root",
+			Hostname:      "localhost",
+			IdentityFile:  "/tmp",
+			Port:          "2222",
+			User:          "root",
+			IdentityFiles: []string{"/tmp"},
+			Settings: []sshconfig.Setting{
+				{Key: "hostname", Values: []string{"localhost"}},
+				{Key: "port", Values: []string{"2222"}},
+				{Key: "identityfile", Values: []string{"/tmp"}},
+				{Key: "user", Values: []string{"root"}},
+			},
 		},
 	}
 
@@ -312,9 +338,13 @@ func Test_view(t *testing.T) {
 	// There will be no output without setting proper size of the viewport.
 	m.viewport = viewport.New(viewport.WithHeight(1))
 	m.modelGroupList = fakeModelFactory("mock group list")
+	m.modelTagList = fakeModelFactory("mock tag list")
+	m.modelPatternList = fakeModelFactory("mock pattern list")
+	m.modelTunnelList = fakeModelFactory("mock tunnel list")
 	m.modelHostList = fakeModelFactory("mock host list")
 	m.viewMessageContent = "mock message content"
 	m.modelHostEdit = fakeModelFactory("mock host edit")
+	m.modelHostDetails = fakeModelFactory("mock host details")
 
 	tests := []struct {
 		name     string
@@ -326,6 +356,21 @@ func Test_view(t *testing.T) {
 			appState: state.ViewGroupList,
 			expected: "mock group list",
 		},
+		{
+			name:     "View should return tag list when app state is ViewTagList",
+			appState: state.ViewTagList,
+			expected: "mock tag list",
+		},
+		{
+			name:     "View should return pattern list when app state is ViewPatternList",
+			appState: state.ViewPatternList,
+			expected: "mock pattern list",
+		},
+		{
+			name:     "View should return tunnel list when app state is ViewTunnelList",
+			appState: state.ViewTunnelList,
+			expected: "mock tunnel list",
+		},
 		{
 			name:     "View should return host list when app state is ViewHostList",
 			appState: state.ViewHostList,
@@ -341,6 +386,11 @@ func Test_view(t *testing.T) {
 			appState: state.ViewEditItem,
 			expected: "mock host edit",
 		},
+		{
+			name:     "View should return host details when app state is ViewHostDetails",
+			appState: state.ViewHostDetails,
+			expected: "mock host details",
+		},
 	}
 
 	for _, tt := range tests {
@@ -367,3 +417,14 @@ type modelFunc struct {
 func (m modelFunc) Init() tea.Cmd                           { return m.init() }
 func (m modelFunc) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m.update(msg) }
 func (m modelFunc) View() tea.View                          { return m.view() }
+
+func TestDispatchProcessSSHConnect_DeletedJumpHost(t *testing.T) {
+	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	host := hostModel.NewHost("4", "Mock Host 4", "", "localhost", "root", "", "")
+	host.ProxyJump = []string{"1", "deleted"}
+
+	cmd := model.dispatchProcessSSHConnect(message.RunProcessSSHConnect{Host: host})
+	require.Nil(t, cmd)
+	require.Equal(t, state.ViewMessage, model.appState.CurrentView)
+	require.Contains(t, model.viewMessageContent, "jump host not found")
+}
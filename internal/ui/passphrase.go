@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"errors"
+
+	tea "charm.land/bubbletea/v2"
+
+	"github.com/grafviktor/goto/internal/ui/component/passphrase"
+)
+
+// ErrPromptCancelled - is returned when user cancels the passphrase prompt.
+var ErrPromptCancelled = errors.New("cancelled by user")
+
+// PromptPassphrase - asks user for a passphrase and blocks until it passes validation or user
+// cancels the prompt. It must be called before the main user interface starts.
+func PromptPassphrase(title string, validate func(string) error) (string, error) {
+	model := passphrase.New(title, validate)
+	if _, err := tea.NewProgram(model).Run(); err != nil {
+		return "", err
+	}
+
+	value, ok := model.Value()
+	if !ok {
+		return "", ErrPromptCancelled
+	}
+
+	return value, nil
+}
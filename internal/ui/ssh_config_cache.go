@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/grafviktor/goto/internal/model/sshconfig"
+)
+
+// sshConfigCache - keeps 'ssh -G' results keyed by host ID. Every entry stores the command which
+// produced it. When the host is changed, its command changes too, and the entry is not used.
+// The cache is dropped when hosts or ssh_config files are modified, because a change in one
+// host block, for instance "Host *", can affect all other hosts.
+type sshConfigCache struct {
+	entries map[string]sshConfigCacheEntry
+}
+
+type sshConfigCacheEntry struct {
+	command string
+	config  sshconfig.Config
+}
+
+func newSSHConfigCache() *sshConfigCache {
+	return &sshConfigCache{entries: make(map[string]sshConfigCacheEntry)}
+}
+
+func (c *sshConfigCache) get(hostID, command string) (sshconfig.Config, bool) {
+	entry, found := c.entries[hostID]
+	if !found || entry.command != command {
+		return sshconfig.Config{}, false
+	}
+
+	return entry.config, true
+}
+
+func (c *sshConfigCache) put(hostID, command string, config sshconfig.Config) {
+	// New hosts do not have an ID yet.
+	if hostID == "" {
+		return
+	}
+
+	c.entries[hostID] = sshConfigCacheEntry{command: command, config: config}
+}
+
+func (c *sshConfigCache) invalidate() {
+	c.entries = make(map[string]sshConfigCacheEntry)
+}
+
+// sshConfigLookup - is 'ssh -G' process, which is scheduled or running. Only one lookup is active at a
+// time, a new one supersedes the previous: tag is incremented and the running process is cancelled.
+type sshConfigLookup struct {
+	tag     int
+	hostID  string
+	command string
+	cancel  context.CancelFunc
+}
+
+// sshConfigLoadDebounced - is dispatched when debounce time of a lookup expires. The lookup starts
+// only if it's not superseded by another one.
+type sshConfigLoadDebounced struct {
+	tag     int
+	hostID  string
+	command string
+}
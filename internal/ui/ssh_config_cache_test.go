@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafviktor/goto/internal/constant"
+	hostModel "github.com/grafviktor/goto/internal/model/host"
+	"github.com/grafviktor/goto/internal/model/sshconfig"
+	testutils "github.com/grafviktor/goto/internal/testutils"
+	"github.com/grafviktor/goto/internal/testutils/mocklogger"
+	"github.com/grafviktor/goto/internal/ui/message"
+)
+
+func TestSSHConfigCache(t *testing.T) {
+	cache := newSSHConfigCache()
+	cache.put("1", "ssh -G web1", sshconfig.Config{User: "admin"})
+	// Hosts without ID are not cached.
+	cache.put("", "ssh -G new", sshconfig.Config{User: "admin"})
+
+	config, found := cache.get("1", "ssh -G web1")
+	require.True(t, found)
+	require.Equal(t, "admin", config.User)
+
+	// Host was changed, so its command is different.
+	_, found = cache.get("1", "ssh -G web1 -p 2222")
+	require.False(t, found)
+	_, found = cache.get("", "ssh -G new")
+	require.False(t, found)
+
+	cache.invalidate()
+	_, found = cache.get("1", "ssh -G web1")
+	require.False(t, found)
+}
+
+func TestScheduleSSHConfigLookup_Debounce(t *testing.T) {
+	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	host1 := hostModel.NewHost("1", "host1", "", "localhost", "", "", "")
+	host2 := hostModel.NewHost("2", "host2", "", "localhost", "", "", "")
+
+	first := model.scheduleSSHConfigLookup(message.RunProcessSSHLoadConfig{Host: host1})().(sshConfigLoadDebounced)
+	second := model.scheduleSSHConfigLookup(message.RunProcessSSHLoadConfig{Host: host2})().(sshConfigLoadDebounced)
+	require.Equal(t, "1", first.hostID)
+	require.Equal(t, "2", second.hostID)
+
+	// The first lookup is superseded, ssh process is not started.
+	require.Nil(t, model.dispatchProcessSSHLoadConfig(first))
+	require.Empty(t, model.sshConfigLookup.hostID)
+
+	// Only the last lookup starts.
+	require.NotNil(t, model.dispatchProcessSSHLoadConfig(second))
+	require.Equal(t, "2", model.sshConfigLookup.hostID)
+	model.cancelSSHConfigLookup()
+}
+
+func TestScheduleSSHConfigLookup_Cache(t *testing.T) {
+	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	host := hostModel.NewHost("1", "host1", "", "localhost", "", "", "")
+	model.sshConfigLookup.hostID = host.ID
+	model.sshConfigLookup.command = host.CmdSSHConfig()
+
+	// Loaded config is cached for the host which requested it.
+	model.handleProcessSuccess(message.RunProcessSuccess{
+		ProcessType: constant.ProcessTypeSSHLoadConfig,
+		HostID:      host.ID,
+		StdOut:      "user cached",
+	})
+
+	msg := model.scheduleSSHConfigLookup(message.RunProcessSSHLoadConfig{Host: host})()
+	require.IsType(t, message.HostSSHConfigLoadComplete{}, msg)
+	require.Equal(t, "cached", msg.(message.HostSSHConfigLoadComplete).Config.User)
+
+	// Cache is dropped when hosts are modified.
+	model.Update(message.HostListRefresh{})
+	msg = model.scheduleSSHConfigLookup(message.RunProcessSSHLoadConfig{Host: host})()
+	require.IsType(t, sshConfigLoadDebounced{}, msg)
+}
+
+func TestCancelSSHConfigLookup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep command is not available on Windows")
+	}
+
+	model := New(context.TODO(), testutils.NewMockStorage(false), MockAppState(), &mocklogger.Logger{})
+	lookup := sshConfigLoadDebounced{tag: model.sshConfigLookup.tag, hostID: "1", command: "sleep 10"}
+	cmd := model.dispatchProcessSSHLoadConfig(lookup)
+	require.NotNil(t, cmd)
+
+	result := make(chan any)
+	go func() { result <- cmd() }()
+	model.cancelSSHConfigLookup()
+
+	select {
+	case msg := <-result:
+		require.Nil(t, msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("ssh config lookup is not cancelled")
+	}
+}
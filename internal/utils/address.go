@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+// MaxHostnameLength - maximum length of a host name, see RFC 1035.
+const MaxHostnameLength = 253
+
+/*
+Valid hostname regex (RFC 1035 + RFC 1123). Underscores are allowed, because they're often used
+in internal DNS zones and /etc/hosts aliases, and ssh accepts them.
+
+"example.com",     // Valid.
+"sub.example.com", // Valid.
+"localhost",       // Valid (for local use).
+"123.example",     // Valid.
+"db_primary",      // Valid.
+"-invalid.com",    // Invalid (starts with `-`).
+"example-.com",    // Invalid (ends with `-`).
+"ex@mpl$.com",     // Invalid (special characters).
+"superlonglabelnamethatiswaytoolongtobevalid.example.com", // Invalid (over 63 chars per label).
+*/
+var hostnameRegex = regexp.MustCompile(
+	`^(?i:[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9_])?(\.[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9_])?)*)$`,
+)
+
+// Host name which consists of digits and dots only, must be a valid IPv4 address.
+var ipv4LikeRegex = regexp.MustCompile(`^[0-9.]+$`)
+
+// parseIPAddress - parses IPv4 or IPv6 address. IPv6 address can be enclosed in square brackets
+// and contain a zone ID, for instance "[fe80::1%eth0]".
+func parseIPAddress(address string) (netip.Addr, bool) {
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		ip, err := netip.ParseAddr(address[1 : len(address)-1])
+		return ip, err == nil && ip.Is6()
+	}
+
+	ip, err := netip.ParseAddr(address)
+	return ip, err == nil
+}
+
+// IsIPv6Address - returns true if the address is IPv6 literal, with or without square brackets.
+func IsIPv6Address(address string) bool {
+	ip, ok := parseIPAddress(address)
+	return ok && ip.Is6()
+}
+
+// IsValidHostAddress - returns true if the address is a host name, IPv4 or IPv6 address.
+func IsValidHostAddress(address string) bool {
+	if _, ok := parseIPAddress(address); ok {
+		return true
+	}
+
+	if len(address) > MaxHostnameLength || ipv4LikeRegex.MatchString(address) {
+		return false
+	}
+
+	return hostnameRegex.MatchString(address)
+}
+
+// UnbracketAddress - removes square brackets around IPv6 address: "[2001:db8::10]" => "2001:db8::10".
+// ssh and ssh_config expect IPv6 address without brackets. Other values are returned unchanged.
+func UnbracketAddress(address string) string {
+	if strings.HasPrefix(address, "[") && IsIPv6Address(address) {
+		return address[1 : len(address)-1]
+	}
+
+	return address
+}
+
+// BracketAddress - encloses IPv6 address in square brackets: "2001:db8::10" => "[2001:db8::10]", so that
+// it can be combined with a user name or a port, as ssh-copy-id and scp expect. Other values are returned
+// unchanged.
+func BracketAddress(address string) string {
+	if !strings.HasPrefix(address, "[") && IsIPv6Address(address) {
+		return "[" + address + "]"
+	}
+
+	return address
+}
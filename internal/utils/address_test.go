@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_IsValidHostAddress(t *testing.T) {
+	valid := []string{
+		"example.com",
+		"localhost",
+		"db_primary",
+		"192.168.0.1",
+		"2001:db8::10",
+		"[2001:db8::10]",
+		"fe80::1%eth0",
+		"[fe80::1%eth0]",
+		"::1",
+	}
+	for _, address := range valid {
+		require.True(t, IsValidHostAddress(address), address)
+	}
+
+	invalid := []string{
+		"",
+		"-invalid.com",
+		"ex@mpl$.com",
+		"999.168.0.1",
+		"2001:db8::10::1",
+		"[192.168.0.1]",
+		"[2001:db8::10",
+		strings.Repeat("a.", 127) + "com",
+	}
+	for _, address := range invalid {
+		require.False(t, IsValidHostAddress(address), address)
+	}
+}
+
+func Test_IsIPv6Address(t *testing.T) {
+	require.True(t, IsIPv6Address("2001:db8::10"))
+	require.True(t, IsIPv6Address("[fe80::1%eth0]"))
+	require.False(t, IsIPv6Address("192.168.0.1"))
+	require.False(t, IsIPv6Address("example.com"))
+}
+
+func Test_UnbracketAddress(t *testing.T) {
+	require.Equal(t, "2001:db8::10", UnbracketAddress("[2001:db8::10]"))
+	require.Equal(t, "fe80::1%eth0", UnbracketAddress("[fe80::1%eth0]"))
+	require.Equal(t, "2001:db8::10", UnbracketAddress("2001:db8::10"))
+	require.Equal(t, "example.com", UnbracketAddress("example.com"))
+	// Not an IPv6 address, the value is returned unchanged.
+	require.Equal(t, "[example.com]", UnbracketAddress("[example.com]"))
+}
+
+func Test_BracketAddress(t *testing.T) {
+	require.Equal(t, "[2001:db8::10]", BracketAddress("2001:db8::10"))
+	require.Equal(t, "[fe80::1%eth0]", BracketAddress("fe80::1%eth0"))
+	require.Equal(t, "[2001:db8::10]", BracketAddress("[2001:db8::10]"))
+	require.Equal(t, "192.168.0.1", BracketAddress("192.168.0.1"))
+	require.Equal(t, "example.com", BracketAddress("example.com"))
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// ErrLockTimeout is returned when a file lock is held by another process for too long.
+var ErrLockTimeout = errors.New("file is locked by another process")
+
+const (
+	lockFileSuffix    = ".lock"
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// lockTimeout - is a variable to make it possible to override it in unit-tests.
+var lockTimeout = 5 * time.Second
+
+// LockFile - acquires an advisory lock, which protects filePath from being modified by several
+// application instances at the same time. The lock is held on a separate "<filePath>.lock" file,
+// because filePath itself is replaced on every write, see WriteFileAtomic. Returns a function
+// which releases the lock.
+func LockFile(filePath string) (func(), error) {
+	lockFile, err := os.OpenFile(filePath+lockFileSuffix, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		locked, lockErr := tryLockFile(lockFile)
+		if lockErr != nil {
+			_ = lockFile.Close()
+			return nil, lockErr
+		}
+
+		if locked {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			_ = lockFile.Close()
+			return nil, fmt.Errorf("%w: %s", ErrLockTimeout, filePath)
+		}
+
+		time.Sleep(lockRetryInterval)
+	}
+
+	return func() {
+		_ = unlockFile(lockFile)
+		_ = lockFile.Close()
+	}, nil
+}
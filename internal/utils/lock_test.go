@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_LockFile(t *testing.T) {
+	originalTimeout := lockTimeout
+	lockTimeout = 100 * time.Millisecond
+	t.Cleanup(func() { lockTimeout = originalTimeout })
+
+	filePath := filepath.Join(t.TempDir(), "hosts.yaml")
+	unlock, err := LockFile(filePath)
+	require.NoError(t, err)
+	require.FileExists(t, filePath+lockFileSuffix)
+
+	// The file is already locked.
+	_, err = LockFile(filePath)
+	require.ErrorIs(t, err, ErrLockTimeout)
+
+	// The lock can be acquired again, once it is released.
+	unlock()
+	unlock, err = LockFile(filePath)
+	require.NoError(t, err)
+	unlock()
+}
+
+func Test_LockFile_InvalidPath(t *testing.T) {
+	_, err := LockFile(filepath.Join(t.TempDir(), "missing", "hosts.yaml"))
+	require.Error(t, err)
+}
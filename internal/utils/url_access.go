@@ -0,0 +1,291 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+)
+
+// URLAccess describes how to access remote files, which URLs start with URL prefix. Secrets are never
+// stored in the configuration, they're read from environment variables or printed by external commands,
+// for instance "pass show config-server/token". When both bearer token and username are set, bearer
+// token is used. Only header values, which are not secret, can be set as is, see URLHeader.
+type URLAccess struct {
+	URL                string               `yaml:"url"`
+	BearerTokenEnv     string               `yaml:"bearer_token_env,omitempty"`
+	BearerTokenCommand string               `yaml:"bearer_token_command,omitempty"`
+	Username           string               `yaml:"username,omitempty"`
+	PasswordEnv        string               `yaml:"password_env,omitempty"`
+	PasswordCommand    string               `yaml:"password_command,omitempty"`
+	CAFile             string               `yaml:"ca_file,omitempty"`
+	ClientCert         string               `yaml:"client_cert,omitempty"`
+	ClientKey          string               `yaml:"client_key,omitempty"`
+	Headers            map[string]URLHeader `yaml:"headers,omitempty"`
+}
+
+// URLHeader - is a value of an extra request header. Secret values, for instance, API keys, are read from
+// an environment variable or printed by an external command, the same way as bearer token. A value which
+// is not secret can be set as is, in that case the header is written as "name: value" in the configuration.
+type URLHeader struct {
+	Value        string `yaml:"value,omitempty"`
+	ValueEnv     string `yaml:"value_env,omitempty"`
+	ValueCommand string `yaml:"value_command,omitempty"`
+}
+
+// UnmarshalYAML - reads the header either from a string, or from an object with env and command fields.
+func (h *URLHeader) UnmarshalYAML(unmarshal func(any) error) error {
+	var value string
+	if err := unmarshal(&value); err == nil {
+		*h = URLHeader{Value: value}
+		return nil
+	}
+
+	type plain URLHeader
+	return unmarshal((*plain)(h))
+}
+
+// MarshalYAML - writes the header as a string, if its value is set as is.
+func (h URLHeader) MarshalYAML() (any, error) {
+	if StringEmpty(&h.ValueEnv) && StringEmpty(&h.ValueCommand) {
+		return h.Value, nil
+	}
+
+	type plain URLHeader
+	return plain(h), nil
+}
+
+// read - returns the header value, see readSecret.
+func (h URLHeader) read() (string, error) {
+	if StringEmpty(&h.ValueEnv) && StringEmpty(&h.ValueCommand) {
+		return h.Value, nil
+	}
+
+	return readSecret(h.ValueEnv, h.ValueCommand)
+}
+
+// urlAccess keeps access rules and secrets, which were read by external commands. Secrets are cached
+// to avoid running the command for every included file.
+var urlAccess = struct {
+	mu      sync.Mutex
+	rules   []URLAccess
+	secrets map[string]string
+}{secrets: make(map[string]string)}
+
+// SetURLAccess - sets rules which are used by FetchFromURL to access remote files.
+func SetURLAccess(rules []URLAccess) {
+	urlAccess.mu.Lock()
+	defer urlAccess.mu.Unlock()
+	urlAccess.rules = rules
+	urlAccess.secrets = make(map[string]string)
+}
+
+// urlAccessFor - returns the rule with the longest URL prefix which matches the URL, see urlMatchesRule.
+func urlAccessFor(urlPath string) URLAccess {
+	urlAccess.mu.Lock()
+	defer urlAccess.mu.Unlock()
+
+	parsedURL, err := url.Parse(urlPath)
+	if err != nil {
+		return URLAccess{}
+	}
+
+	var result URLAccess
+	for _, rule := range urlAccess.rules {
+		if urlMatchesRule(parsedURL, rule.URL) && len(rule.URL) > len(result.URL) {
+			result = rule
+		}
+	}
+
+	return result
+}
+
+// urlMatchesRule - returns true if the URL has the same scheme, host and port as the rule, and its path
+// starts with the rule path on a '/' boundary. Credentials must not be sent to look-alike hosts, such as
+// "config.company.com.evil.example", or to sibling paths, such as "/team-other" for "/team" rule.
+func urlMatchesRule(target *url.URL, ruleURL string) bool {
+	rule, err := url.Parse(ruleURL)
+	if err != nil || rule.Host == "" {
+		return false
+	}
+
+	if !strings.EqualFold(target.Scheme, rule.Scheme) ||
+		!strings.EqualFold(hostWithPort(target), hostWithPort(rule)) {
+		return false
+	}
+
+	rulePath := strings.TrimSuffix(rule.EscapedPath(), "/")
+	targetPath := target.EscapedPath()
+	return rulePath == "" || targetPath == rulePath || strings.HasPrefix(targetPath, rulePath+"/")
+}
+
+// hostWithPort - returns "host:port" of the URL, the port is set to the scheme default if it's omitted.
+func hostWithPort(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = lo.Ternary(strings.EqualFold(u.Scheme, "http"), "80", "443")
+	}
+
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// httpClient - returns HTTP client which trusts the custom CA and presents the client certificate.
+func (a URLAccess) httpClient() (*http.Client, error) {
+	client := &http.Client{Timeout: networkResponseTimeout, CheckRedirect: a.checkRedirect}
+	if StringEmpty(&a.CAFile) && StringEmpty(&a.ClientCert) {
+		return client, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if !StringEmpty(&a.CAFile) {
+		caData, err := os.ReadFile(a.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read CA bundle: %w", err)
+		}
+
+		// Custom CA is trusted in addition to the system ones.
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no certificates found in CA bundle: %s", a.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if !StringEmpty(&a.ClientCert) {
+		// Key can be stored in the same file as the certificate.
+		keyFile := lo.Ternary(StringEmpty(&a.ClientKey), a.ClientCert, a.ClientKey)
+		cert, err := tls.LoadX509KeyPair(a.ClientCert, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:errcheck // always *http.Transport
+	transport.TLSClientConfig = tlsConfig
+	client.Transport = transport
+
+	return client, nil
+}
+
+// maxRedirects - is the same as the default limit of http.Client.
+const maxRedirects = 10
+
+// checkRedirect - removes credentials and extra headers from the request, when it's redirected to
+// a different scheme, host or port. http.Client only removes Authorization header, and only when
+// the request is redirected to another domain.
+func (a URLAccess) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	original := via[0].URL
+	if strings.EqualFold(req.URL.Scheme, original.Scheme) &&
+		strings.EqualFold(hostWithPort(req.URL), hostWithPort(original)) {
+		return nil
+	}
+
+	req.Header.Del("Authorization")
+	for name := range a.Headers {
+		req.Header.Del(name)
+	}
+
+	return nil
+}
+
+// authorize - adds extra headers and credentials to the request.
+func (a URLAccess) authorize(req *http.Request) error {
+	for name, header := range a.Headers {
+		value, err := header.read()
+		if err != nil {
+			return fmt.Errorf("cannot read %s header: %w", name, err)
+		}
+		req.Header.Set(name, value)
+	}
+
+	switch {
+	case !StringEmpty(&a.BearerTokenEnv) || !StringEmpty(&a.BearerTokenCommand):
+		token, err := readSecret(a.BearerTokenEnv, a.BearerTokenCommand)
+		if err != nil {
+			return fmt.Errorf("cannot read bearer token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case !StringEmpty(&a.Username):
+		password, err := readSecret(a.PasswordEnv, a.PasswordCommand)
+		if err != nil {
+			return fmt.Errorf("cannot read password: %w", err)
+		}
+		req.SetBasicAuth(a.Username, password)
+	}
+
+	return nil
+}
+
+// readSecret - reads the secret from the environment variable, or runs the command and reads its output.
+// Error messages never contain the secret value.
+func readSecret(envName, command string) (string, error) {
+	if !StringEmpty(&envName) {
+		if value, ok := os.LookupEnv(envName); ok {
+			return value, nil
+		}
+
+		if StringEmpty(&command) {
+			return "", fmt.Errorf("environment variable %s is not set", envName)
+		}
+	}
+
+	if StringEmpty(&command) {
+		return "", errors.New("secret source is not set")
+	}
+
+	urlAccess.mu.Lock()
+	value, ok := urlAccess.secrets[command]
+	urlAccess.mu.Unlock()
+	if ok {
+		return value, nil
+	}
+
+	// The lock is not held while the command runs, so that a slow command does not block other downloads.
+	// The command runs while the UI is displayed, so it must not use the terminal. If it needs a passphrase,
+	// for instance, to unlock gpg key, it should use an agent, like "pass" does. Stderr is hidden and it's
+	// not added to the error, as it may contain the secret.
+	process := BuildProcess(command)
+	process.Stderr = &ProcessBufferWriter{}
+	output, err := process.Output()
+	if err != nil {
+		return "", fmt.Errorf("command %q failed: %w", command, err)
+	}
+
+	value = strings.TrimRight(string(output), "\r\n")
+	urlAccess.mu.Lock()
+	urlAccess.secrets[command] = value
+	urlAccess.mu.Unlock()
+	return value, nil
+}
+
+// RedactURL - replaces password in the URL with "xxxxx", so that the URL can be logged. Values which are
+// not URLs are returned as is.
+func RedactURL(value string) string {
+	if !IsSupportedURL(value) {
+		return value
+	}
+
+	parsedURL, err := url.Parse(value)
+	if err != nil {
+		return value
+	}
+
+	return parsedURL.Redacted()
+}
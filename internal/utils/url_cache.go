@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	urlCacheDataSuffix = ".data"
+	urlCacheMetaSuffix = ".json"
+)
+
+// urlCacheEntry describes a document, which was fetched from a remote location and stored on disk.
+type urlCacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// urlCache keeps copies of remote documents, so that they're available when the remote location is
+// unreachable. offline is keyed by URL and contains the time when the cached copy was fetched. It only
+// contains documents which were loaded from the cache because the latest fetch attempt failed.
+var urlCache = struct {
+	mu      sync.Mutex
+	folder  string
+	offline map[string]time.Time
+}{offline: make(map[string]time.Time)}
+
+// SetURLCacheFolder - enables caching of documents which are fetched by FetchFromURL. When the folder
+// is not set, documents are fetched on every call and are not available offline.
+func SetURLCacheFolder(folder string) {
+	urlCache.mu.Lock()
+	defer urlCache.mu.Unlock()
+	urlCache.folder = folder
+}
+
+// URLCacheOfflineSince - returns the time when the oldest cached document, which is used instead of
+// the remote one, was fetched. The second value is false if all documents were fetched successfully.
+func URLCacheOfflineSince() (time.Time, bool) {
+	urlCache.mu.Lock()
+	defer urlCache.mu.Unlock()
+
+	var oldest time.Time
+	for _, fetchedAt := range urlCache.offline {
+		if oldest.IsZero() || fetchedAt.Before(oldest) {
+			oldest = fetchedAt
+		}
+	}
+
+	return oldest, !oldest.IsZero()
+}
+
+func urlCacheFolder() string {
+	urlCache.mu.Lock()
+	defer urlCache.mu.Unlock()
+	return urlCache.folder
+}
+
+func setURLOffline(urlPath string, fetchedAt time.Time, offline bool) {
+	urlCache.mu.Lock()
+	defer urlCache.mu.Unlock()
+
+	if offline {
+		urlCache.offline[urlPath] = fetchedAt
+	} else {
+		delete(urlCache.offline, urlPath)
+	}
+}
+
+// urlCacheFilePath - returns cache file path without extension. URL is hashed, because it may
+// contain characters which are not allowed in file names.
+func urlCacheFilePath(folder, urlPath string) string {
+	hash := sha256.Sum256([]byte(urlPath))
+	return filepath.Join(folder, hex.EncodeToString(hash[:]))
+}
+
+func readURLCache(folder, urlPath string) (urlCacheEntry, []byte, error) {
+	filePath := urlCacheFilePath(folder, urlPath)
+	metaData, err := os.ReadFile(filePath + urlCacheMetaSuffix)
+	if err != nil {
+		return urlCacheEntry{}, nil, err
+	}
+
+	var entry urlCacheEntry
+	if err = json.Unmarshal(metaData, &entry); err != nil {
+		return urlCacheEntry{}, nil, err
+	}
+
+	if entry.URL != urlPath {
+		return urlCacheEntry{}, nil, fmt.Errorf("cache entry belongs to another URL: %s", entry.URL)
+	}
+
+	data, err := os.ReadFile(filePath + urlCacheDataSuffix)
+	if err != nil {
+		return urlCacheEntry{}, nil, err
+	}
+
+	return entry, data, nil
+}
+
+func writeURLCache(folder string, entry urlCacheEntry, data []byte) error {
+	if err := os.MkdirAll(folder, 0o700); err != nil {
+		return err
+	}
+
+	// Data is written first, so that metadata never points to a document which is not stored yet.
+	filePath := urlCacheFilePath(folder, entry.URL)
+	if err := WriteFileAtomic(filePath+urlCacheDataSuffix, data, 0o600); err != nil {
+		return err
+	}
+
+	return writeURLCacheMeta(folder, entry)
+}
+
+func writeURLCacheMeta(folder string, entry urlCacheEntry) error {
+	metaData, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	return WriteFileAtomic(urlCacheFilePath(folder, entry.URL)+urlCacheMetaSuffix, metaData, 0o600)
+}
+
+// fetchFromURLCached - fetches the document and stores it in the cache folder. If the document was
+// cached before, the request is conditional and the server may confirm that the cached copy is
+// up to date. If the remote location is unreachable, the cached copy is returned. Cache write errors
+// are ignored, as they only affect the next application run.
+func fetchFromURLCached(folder, urlPath string) (io.ReadCloser, error) {
+	entry, cachedData, cacheErr := readURLCache(folder, urlPath)
+	if cacheErr != nil {
+		entry = urlCacheEntry{URL: urlPath}
+	}
+
+	data, err := fetchFromURL(urlPath, &entry)
+	switch {
+	case errors.Is(err, errNotModified) && cacheErr == nil:
+		entry.FetchedAt = time.Now()
+		_ = writeURLCacheMeta(folder, entry)
+		data = cachedData
+	case err != nil && cacheErr == nil:
+		setURLOffline(urlPath, entry.FetchedAt, true)
+		return io.NopCloser(bytes.NewReader(cachedData)), nil
+	case err != nil:
+		return nil, err
+	default:
+		_ = writeURLCache(folder, entry, data)
+	}
+
+	setURLOffline(urlPath, time.Time{}, false)
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+// fetchFromURL - downloads the document using credentials and TLS settings, which are configured for
+// the URL. When the document is downloaded, entry is updated with the response validators and fetch time.
+func fetchFromURL(urlPath string, entry *urlCacheEntry) ([]byte, error) {
+	//nolint:noctx // want to use http.NewRequest instead of http.NewRequestWithContext
+	req, err := http.NewRequest(http.MethodGet, urlPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	access := urlAccessFor(urlPath)
+	client, err := access.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	if err = access.authorize(req); err != nil {
+		return nil, err
+	}
+
+	setConditionalHeaders(req, entry)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("failed to fetch %s: status code %d", RedactURL(urlPath), resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	entry.ETag = resp.Header.Get("ETag")
+	entry.LastModified = resp.Header.Get("Last-Modified")
+	entry.FetchedAt = time.Now()
+	return data, nil
+}
+
+// setConditionalHeaders - asks the server to return the document only if it was modified since it
+// was cached.
+func setConditionalHeaders(req *http.Request, entry *urlCacheEntry) {
+	if !StringEmpty(&entry.ETag) {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+
+	if !StringEmpty(&entry.LastModified) {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// errNotModified - is returned by fetch function when the server confirms that cached copy is up to date.
+var errNotModified = errors.New("not modified")
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, urlPath string) string {
+	t.Helper()
+
+	reader, err := FetchFromURL(urlPath)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func Test_FetchFromURL_Cache(t *testing.T) {
+	SetURLCacheFolder(t.TempDir())
+	t.Cleanup(func() { SetURLCacheFolder("") })
+
+	requests, unavailable := 0, false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case unavailable:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			_, _ = w.Write([]byte("Host example"))
+		}
+	}))
+	defer ts.Close()
+	urlPath := ts.URL + "/ssh_config"
+
+	// Document is downloaded and cached.
+	require.Equal(t, "Host example", readAll(t, urlPath))
+	_, offline := URLCacheOfflineSince()
+	require.False(t, offline)
+
+	// Server confirms that the cached copy is up to date.
+	require.Equal(t, "Host example", readAll(t, urlPath))
+	require.Equal(t, 2, requests)
+
+	// Remote location is unavailable, cached copy is used.
+	unavailable = true
+	require.Equal(t, "Host example", readAll(t, urlPath))
+	fetchedAt, offline := URLCacheOfflineSince()
+	require.True(t, offline)
+	require.False(t, fetchedAt.IsZero())
+
+	// Documents which were never cached cannot be loaded.
+	_, err := FetchFromURL(ts.URL + "/another_config")
+	require.Error(t, err)
+
+	// When the document is fetched again, it's no longer reported as offline.
+	unavailable = false
+	require.Equal(t, "Host example", readAll(t, urlPath))
+	_, offline = URLCacheOfflineSince()
+	require.False(t, offline)
+}
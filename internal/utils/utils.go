@@ -2,10 +2,11 @@
 package utils //nolint:revive,nolintlint // utils is a common name
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
-	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
@@ -156,7 +157,9 @@ func ExtractBaseURL(urlPath string) (string, error) {
 // Not 'const' as redefined in unit tests to reduce execution time.
 var networkResponseTimeout = 10 * time.Second
 
-// FetchFromURL fetches content from a URL and returns it as a string.
+// FetchFromURL fetches content from a URL and returns it as a string. When URL cache is enabled, see
+// SetURLCacheFolder, the content is revalidated using ETag and Last-Modified headers, and the cached
+// copy is returned if the remote location is unreachable.
 func FetchFromURL(urlPath string) (io.ReadCloser, error) {
 	if !IsSupportedURL(urlPath) {
 		return nil, fmt.Errorf("not a valid URL: %s", urlPath)
@@ -167,24 +170,16 @@ func FetchFromURL(urlPath string) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("invalid URL format: %w", err)
 	}
 
-	//nolint:noctx // want to use http.NewRequest instead of http.NewRequestWithContext
-	req, err := http.NewRequest(http.MethodGet, parsedURL.String(), nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	if folder := urlCacheFolder(); !StringEmpty(&folder) {
+		return fetchFromURLCached(folder, parsedURL.String())
 	}
 
-	client := &http.Client{Timeout: networkResponseTimeout}
-	resp, err := client.Do(req)
+	data, err := fetchFromURL(parsedURL.String(), &urlCacheEntry{})
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		_ = resp.Body.Close()
-		return nil, fmt.Errorf("failed to fetch %s: status code %d", urlPath, resp.StatusCode)
-	}
-
-	return resp.Body, nil
+	return io.NopCloser(bytes.NewReader(data)), nil
 }
 
 // SSHConfigPath - returns ssh_config path or error.
@@ -257,6 +252,13 @@ func splitArguments(cmd string) []string {
 
 // BuildProcess - builds exec.Cmd object from command string.
 func BuildProcess(cmd string) *exec.Cmd {
+	// I'm not going to cancel the process.
+	return BuildProcessContext(context.Background(), cmd)
+}
+
+// BuildProcessContext - builds exec.Cmd object from command string. The process is killed
+// when the context is done before the process exits.
+func BuildProcessContext(ctx context.Context, cmd string) *exec.Cmd {
 	if strings.TrimSpace(cmd) == "" {
 		return nil
 	}
@@ -265,7 +267,7 @@ func BuildProcess(cmd string) *exec.Cmd {
 	command := commandWithArguments[0]
 	arguments := commandWithArguments[1:]
 
-	return exec.Command(command, arguments...) //nolint:noctx // I'm not going to cancel the process.
+	return exec.CommandContext(ctx, command, arguments...)
 }
 
 // ProcessBufferWriter - is an object which pretends to be a writer, however it saves all data into a temporary buffer
@@ -302,11 +304,12 @@ func BuildProcessInterceptStdErr(command string) *exec.Cmd {
 }
 
 // BuildProcessInterceptStdAll - builds a process where both stdout and stderr are intercepted for further processing.
-func BuildProcessInterceptStdAll(command string) *exec.Cmd {
+// The process is killed when the context is done.
+func BuildProcessInterceptStdAll(ctx context.Context, command string) *exec.Cmd {
 	// Use case 1: User edits host
 	// Use case 2: User is going to copy his ssh key using <t> command from the hostlist
 
-	process := BuildProcess(command)
+	process := BuildProcessContext(ctx, command)
 	process.Stdout = &ProcessBufferWriter{}
 	process.Stderr = &ProcessBufferWriter{}
 
@@ -322,6 +325,52 @@ func IsFolderExists(folderPath string) bool {
 	return true
 }
 
+// WriteFileAtomic - writes data to a temporary file which is located in the same folder as
+// filePath, flushes it to disk and then renames it to filePath. If the write is interrupted,
+// the original file stays intact.
+func WriteFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+	folder := filepath.Dir(filePath)
+	tmpFile, err := os.CreateTemp(folder, "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tmpFilePath := tmpFile.Name()
+	// Remove the temporary file if something goes wrong, after successful rename it does not exist.
+	defer func() { _ = os.Remove(tmpFilePath) }()
+
+	if _, err = tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err = tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmpFilePath, perm); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmpFilePath, filePath); err != nil {
+		return err
+	}
+
+	// Flush the folder entry as well, so that the rename survives a power loss.
+	// This is not supported on Windows, therefore the error is ignored.
+	if dir, dirErr := os.Open(folder); dirErr == nil {
+		_ = dir.Sync()
+		_ = dir.Close()
+	}
+
+	return nil
+}
+
 var requiredBinaryInPath = "ssh"
 
 func CheckAppRequirements(appHome string) error {
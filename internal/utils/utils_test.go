@@ -210,7 +210,7 @@ func TestBuildConnectSSH(t *testing.T) {
 
 func TestBuildLoadSSHConfig(t *testing.T) {
 	// Test case: Load SSH config sanity check
-	cmd := BuildProcessInterceptStdAll("localhost")
+	cmd := BuildProcessInterceptStdAll(context.TODO(), "localhost")
 
 	// Check that cmd is created and stdErr and stdOut are re-defined
 	require.NotNil(t, cmd)
@@ -407,6 +407,34 @@ func Test_FetchFromURL(t *testing.T) {
 	}
 }
 
+func Test_WriteFileAtomic(t *testing.T) {
+	folder := t.TempDir()
+	filePath := filepath.Join(folder, "hosts.yaml")
+
+	// Create a new file
+	err := WriteFileAtomic(filePath, []byte("first"), 0o600)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, "first", string(data))
+
+	// Replace existing file
+	err = WriteFileAtomic(filePath, []byte("second"), 0o600)
+	require.NoError(t, err)
+	data, err = os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Equal(t, "second", string(data))
+
+	// Temporary files should not be left behind
+	entries, err := os.ReadDir(folder)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	// Folder does not exist
+	err = WriteFileAtomic(filepath.Join(folder, "missing", "hosts.yaml"), []byte("data"), 0o600)
+	require.Error(t, err)
+}
+
 func Test_CheckAppRequirements(t *testing.T) {
 	tmpDir := t.TempDir()
 	appHomeOk := path.Join(tmpDir, "app_home")